@@ -0,0 +1,165 @@
+// Replay re-executes a recording of Secret Manager calls and diffs the
+// responses.
+//
+// Recordings are written by the emulator with --record. By default they are
+// replayed against a fresh in-process emulator whose clock is set to each
+// call's recorded time, so a recording made with --freeze-time replays with
+// identical timestamps. With --target they are replayed against any Secret
+// Manager endpoint, e.g. another emulator or a local stand-in for a real one.
+//
+// Usage:
+//
+//	replay recording.jsonl
+//	replay --target localhost:9090 --ignore createTime recording.jsonl
+//
+// Every call whose status or response differs is printed with its
+// differences. The exit status is 1 if any call differed.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/record"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+var (
+	target  = flag.String("target", "", "Secret Manager endpoint (host:port) to replay against; empty starts a fresh in-process emulator")
+	useTLS  = flag.Bool("tls", false, "Connect to --target over TLS")
+	caFile  = flag.String("ca", "", "PEM CA bundle trusted for --target; implies --tls")
+	ignore  = flag.String("ignore", "", "Comma-separated JSON field names, such as createTime, left out of the comparison")
+	verbose = flag.Bool("v", false, "Print every replayed call, not just those that differ")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] recording.jsonl\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	differed, err := run(context.Background(), flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		os.Exit(2)
+	}
+	if differed {
+		os.Exit(1)
+	}
+}
+
+// run replays the recording at path and reports whether any call differed.
+func run(ctx context.Context, path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	entries, err := record.ReadEntries(f)
+	f.Close()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var opts []record.ReplayOption
+	if *ignore != "" {
+		opts = append(opts, record.WithIgnoredFields(strings.Split(*ignore, ",")...))
+	}
+	var conn *grpc.ClientConn
+	if *target != "" {
+		conn, err = dialTarget()
+	} else {
+		var clk *clock.Virtual
+		conn, clk, err = startEmulator()
+		opts = append(opts, record.WithClock(clk))
+	}
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	replayer := record.NewReplayer(conn, opts...)
+	differed := 0
+	for n, e := range entries {
+		result, err := replayer.Replay(ctx, e)
+		if err != nil {
+			return false, fmt.Errorf("entry %d: %w", n+1, err)
+		}
+		method := e.Method[strings.LastIndex(e.Method, "/")+1:]
+		if len(result.Diffs) == 0 {
+			if *verbose {
+				fmt.Printf("#%d %s: ok\n", n+1, method)
+			}
+			continue
+		}
+		differed++
+		fmt.Printf("#%d %s (%s):\n", n+1, method, e.Transport)
+		for _, d := range result.Diffs {
+			fmt.Printf("    %s\n", d)
+		}
+	}
+	fmt.Printf("replayed %d calls: %d matched, %d differed\n", len(entries), len(entries)-differed, differed)
+	return differed > 0, nil
+}
+
+// dialTarget connects to --target.
+func dialTarget() (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if *useTLS || *caFile != "" {
+		config := &tls.Config{MinVersion: tls.VersionTLS12}
+		if *caFile != "" {
+			pem, err := os.ReadFile(*caFile)
+			if err != nil {
+				return nil, err
+			}
+			config.RootCAs = x509.NewCertPool()
+			if !config.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, errors.New("no certificates found in " + *caFile)
+			}
+		}
+		creds = credentials.NewTLS(config)
+	}
+	return grpc.NewClient(*target, grpc.WithTransportCredentials(creds))
+}
+
+// startEmulator serves a fresh emulator in process on a frozen clock and
+// connects to it.
+func startEmulator() (*grpc.ClientConn, *clock.Virtual, error) {
+	clk := clock.NewFrozen(time.Now())
+	mockServer, err := server.NewServer(server.WithClock(clk))
+	if err != nil {
+		return nil, nil, err
+	}
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	secretmanagerpb.RegisterSecretManagerServiceServer(grpcServer, mockServer)
+	go func() { _ = grpcServer.Serve(lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///emulator",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, clk, nil
+}
@@ -6,102 +6,490 @@
 // Usage:
 //
 //	server-rest --http-port 8080 --grpc-port 9090
+//	server-rest --listen unix:///tmp/sm.sock --grpc-listen unix:///tmp/sm-grpc.sock
+//
+// The bound HTTP address is printed to stdout as a JSON line, e.g.
+// {"event":"listening","protocol":"http","network":"unix","address":"unix:///tmp/sm.sock"}.
 //
 // Environment Variables:
 //
 //	GCP_MOCK_HTTP_PORT   - HTTP port to listen on (default: 8080)
 //	GCP_MOCK_GRPC_PORT   - gRPC port to listen on (default: 9090)
+//	GCP_MOCK_LISTEN      - HTTP address, overriding GCP_MOCK_HTTP_PORT: host:port or unix:///path
+//	GCP_MOCK_GRPC_LISTEN - Internal gRPC address, overriding GCP_MOCK_GRPC_PORT
 //	GCP_MOCK_LOG_LEVEL   - Log level: debug, info, warn, error (default: info)
+//	GCP_MOCK_LOG_FORMAT  - Log format: text, json (default: text)
+//	GCP_MOCK_ADMIN_PORT  - Admin HTTP port serving /metrics (default: 0, served on the HTTP port)
+//	GCP_MOCK_TRACE_EXPORTER - Trace exporter: none, otlp, stdout (default: none)
+//	GCP_MOCK_CORS_ORIGINS - Comma-separated CORS origins, "*" for any (default: disabled)
+//	GCP_MOCK_MAX_BODY_BYTES - Maximum REST request body size (default: 1048576)
+//	GCP_MOCK_MAX_ADMIN_BODY_BYTES - Maximum admin API request body size, e.g. snapshot imports (default: 16777216)
+//	GCP_MOCK_DISABLE_COMPRESSION - Set to "true" to disable response compression
+//	GCP_MOCK_PROTO_FIELD_NAMES - Set to "true" for snake_case JSON field names
+//	GCP_MOCK_TLS_CERT    - PEM server certificate; with GCP_MOCK_TLS_KEY enables TLS
+//	GCP_MOCK_TLS_KEY     - PEM server private key
+//	GCP_MOCK_CLIENT_CA   - PEM CA bundle for client certificates; enables mutual TLS
+//	GCP_MOCK_TLS_SELF_SIGNED - Set to "true" to serve TLS with a generated certificate
+//	GCP_MOCK_TLS_CA_OUT  - Where the self-signed CA bundle is written
+//	GCP_MOCK_TLS_HOSTS   - Comma-separated extra SANs for the self-signed certificate
+//	GCP_MOCK_ENABLE_ADMIN - Set to "true" to serve the admin API (reset, snapshots, stats)
+//	GCP_MOCK_SEED        - Seed file, or directory of seed files, loaded at startup
+//	GCP_MOCK_SEED_WATCH  - Set to "true" to re-apply seed files when they change
+//	GCP_MOCK_SNAPSHOT    - Snapshot file imported at startup
+//	GCP_MOCK_SNAPSHOT_OUT - Where a snapshot is written on SIGUSR1 and at shutdown
+//	GCP_MOCK_FAULTS      - YAML/JSON fault injection rules loaded at startup
+//	GCP_MOCK_QUOTA       - Per-project quotas: "default" and/or class=requests-per-minute pairs
+//	GCP_MOCK_FREEZE_TIME - RFC 3339 time to freeze the emulator clock at startup
+//	GCP_MOCK_RECORD      - JSONL file every Secret Manager call is appended to
+//	GCP_MOCK_RECORD_REDACT - Set to "true" to clear payloads from recorded calls
+//	GCP_MOCK_AUDIT_LOG   - JSONL file, or "-" for stdout, Cloud Audit Logs entries are appended to
+//	GCP_MOCK_TOKENS      - YAML/JSON file mapping bearer tokens to principals
+//	GCP_MOCK_IAM_CACHE_TTL - How long IAM decisions are cached, e.g. "30s" (default: 0, disabled); bounds how stale they get
+//	GCP_MOCK_IAM_FILTER_LISTS - Set to "true" to drop list results the caller may not get
 package main
 
 import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
-	"net"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/audit"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/fault"
 	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/gateway"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/health"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/listen"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/logging"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/quota"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/record"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/seed"
 	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/tlsutil"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/tracing"
 )
 
 var (
-	httpPort = flag.Int("http-port", getEnvInt("GCP_MOCK_HTTP_PORT", 8080), "HTTP port to listen on")
-	grpcPort = flag.Int("grpc-port", getEnvInt("GCP_MOCK_GRPC_PORT", 9090), "gRPC port to listen on (internal)")
-	logLevel = flag.String("log-level", getEnv("GCP_MOCK_LOG_LEVEL", "info"), "Log level (debug, info, warn, error)")
-	version  = "1.1.0"
+	httpPort           = flag.Int("http-port", getEnvInt("GCP_MOCK_HTTP_PORT", 8080), "HTTP port to listen on")
+	grpcPort           = flag.Int("grpc-port", getEnvInt("GCP_MOCK_GRPC_PORT", 9090), "gRPC port to listen on (internal)")
+	listenAddr         = flag.String("listen", getEnv("GCP_MOCK_LISTEN", ""), "HTTP address to listen on, overriding --http-port: host:port or unix:///path/to.sock")
+	grpcListen         = flag.String("grpc-listen", getEnv("GCP_MOCK_GRPC_LISTEN", ""), "Internal gRPC address, overriding --grpc-port: host:port or unix:///path/to.sock")
+	logLevel           = flag.String("log-level", getEnv("GCP_MOCK_LOG_LEVEL", "info"), "Log level (debug, info, warn, error)")
+	logFormat          = flag.String("log-format", getEnv("GCP_MOCK_LOG_FORMAT", "text"), "Log format (text, json)")
+	adminPort          = flag.Int("admin-port", getEnvInt("GCP_MOCK_ADMIN_PORT", 0), "Admin HTTP port serving /metrics (0 serves it on the HTTP port)")
+	traceExporter      = flag.String("trace-exporter", getEnv("GCP_MOCK_TRACE_EXPORTER", "none"), "Trace exporter (none, otlp, stdout); otlp uses the OTEL_EXPORTER_OTLP_* environment variables")
+	corsOrigins        = flag.String("cors-origins", getEnv("GCP_MOCK_CORS_ORIGINS", ""), "Comma-separated origins allowed to call the REST API (\"*\" for any); empty disables CORS")
+	maxBodyBytes       = flag.Int("max-body-bytes", getEnvInt("GCP_MOCK_MAX_BODY_BYTES", gateway.DefaultMaxBodyBytes), "Maximum REST request body size in bytes")
+	maxAdminBodyBytes  = flag.Int("max-admin-body-bytes", getEnvInt("GCP_MOCK_MAX_ADMIN_BODY_BYTES", gateway.DefaultMaxAdminBodyBytes), "Maximum admin API request body size in bytes, e.g. for snapshot imports")
+	protoFieldNames    = flag.Bool("proto-field-names", getEnv("GCP_MOCK_PROTO_FIELD_NAMES", "") == "true", "Use snake_case proto field names in REST JSON responses (pre-1.4 behavior)")
+	disableCompression = flag.Bool("disable-compression", getEnv("GCP_MOCK_DISABLE_COMPRESSION", "") == "true", "Disable gzip/deflate REST response compression")
+	tlsCert            = flag.String("tls-cert", getEnv("GCP_MOCK_TLS_CERT", ""), "PEM server certificate; enables TLS together with --tls-key")
+	tlsKey             = flag.String("tls-key", getEnv("GCP_MOCK_TLS_KEY", ""), "PEM server private key")
+	clientCA           = flag.String("client-ca", getEnv("GCP_MOCK_CLIENT_CA", ""), "PEM CA bundle trusted for client certificates; enables mutual TLS")
+	tlsSelfSigned      = flag.Bool("tls-self-signed", getEnv("GCP_MOCK_TLS_SELF_SIGNED", "") == "true", "Serve TLS with a certificate from a generated CA")
+	tlsCAOut           = flag.String("tls-ca-out", getEnv("GCP_MOCK_TLS_CA_OUT", tlsutil.DefaultCAOut), "Where --tls-self-signed writes the CA bundle for clients")
+	tlsHosts           = flag.String("tls-hosts", getEnv("GCP_MOCK_TLS_HOSTS", ""), "Comma-separated extra DNS names or IPs for the self-signed certificate")
+	enableAdmin        = flag.Bool("enable-admin", getEnv("GCP_MOCK_ENABLE_ADMIN", "") == "true", "Serve the admin API (reset, snapshots, stats); it bypasses IAM")
+	seedPath           = flag.String("seed", getEnv("GCP_MOCK_SEED", ""), "YAML/JSON seed file, or directory of seed files, to pre-populate secrets from")
+	seedWatch          = flag.Bool("seed-watch", getEnv("GCP_MOCK_SEED_WATCH", "") == "true", "Reconcile storage with --seed whenever the seed files change")
+	snapshotPath       = flag.String("snapshot", getEnv("GCP_MOCK_SNAPSHOT", ""), "Snapshot file to import at startup, as written by --snapshot-out or the admin API")
+	snapshotOut        = flag.String("snapshot-out", getEnv("GCP_MOCK_SNAPSHOT_OUT", ""), "Write a snapshot of all state to this file on SIGUSR1 and at shutdown")
+	faultsPath         = flag.String("faults", getEnv("GCP_MOCK_FAULTS", ""), "YAML/JSON file of fault injection rules for Secret Manager RPCs")
+	quotaSpec          = flag.String("quota", getEnv("GCP_MOCK_QUOTA", ""), "Per-project quotas: \"default\" for GCP's limits and/or class=requests-per-minute pairs for access, read and write")
+	freezeTime         = flag.String("freeze-time", getEnv("GCP_MOCK_FREEZE_TIME", ""), "Start with the emulator clock frozen at this RFC 3339 time; the admin API can advance it")
+	recordPath         = flag.String("record", getEnv("GCP_MOCK_RECORD", ""), "Append every Secret Manager request and response to this JSONL file, for the replay command")
+	recordRedact       = flag.Bool("record-redact", getEnv("GCP_MOCK_RECORD_REDACT", "") == "true", "Clear secret payloads and their checksums from --record")
+	auditLogPath       = flag.String("audit-log", getEnv("GCP_MOCK_AUDIT_LOG", ""), "Append Cloud Audit Logs entries for every Secret Manager call to this JSONL file, or \"-\" for stdout")
+	tokensPath         = flag.String("tokens", getEnv("GCP_MOCK_TOKENS", ""), "YAML/JSON file mapping bearer tokens to IAM principals; JWTs are decoded without it")
+	iamCacheTTL        = flag.Duration("iam-cache-ttl", getEnvDuration("GCP_MOCK_IAM_CACHE_TTL", 0), "How long strict-mode IAM decisions are cached (0 disables caching); IAM policy changes take up to this long to apply")
+	iamFilterLists     = flag.Bool("iam-filter-lists", getEnv("GCP_MOCK_IAM_FILTER_LISTS", "") == "true", "Drop list results the caller may not get, checking each item with IAM; Secret Manager only checks the list permission")
+	version            = "1.1.0"
 )
 
 func main() {
 	flag.Parse()
 
-	log.Printf("GCP Secret Manager Mock Server v%s (REST API)", version)
-	log.Printf("Starting gRPC backend on port %d", *grpcPort)
-	log.Printf("Starting HTTP gateway on port %d", *httpPort)
-	log.Printf("Log level: %s", *logLevel)
+	logger, err := logging.New(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging configuration: %v\n", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:       *traceExporter,
+		ServiceName:    "gcp-secret-manager-emulator",
+		ServiceVersion: version,
+	})
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("Error flushing traces", "error", err)
+		}
+	}()
+
+	creds, err := loadTLS()
+	if err != nil {
+		fatal("Failed to configure TLS", err)
+	}
+	if creds != nil {
+		logger.Info("TLS enabled", "mutual_tls", creds.MutualTLS, "ca_bundle", creds.CAFile)
+	}
+
+	logger.Info("GCP Secret Manager Mock Server (REST API)", "version", version, "grpc_port", *grpcPort, "http_port", *httpPort, "log_level", *logLevel)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Start gRPC server
-	grpcAddr := fmt.Sprintf("localhost:%d", *grpcPort)
-	lis, err := net.Listen("tcp", grpcAddr)
+	grpcAddr := *grpcListen
+	if grpcAddr == "" {
+		grpcAddr = fmt.Sprintf("localhost:%d", *grpcPort)
+	}
+	lis, err := listen.Listen(grpcAddr)
 	if err != nil {
-		log.Fatalf("Failed to listen on gRPC port: %v", err)
+		fatal("Failed to listen on gRPC port", err)
 	}
 
-	grpcServer := grpc.NewServer()
-	mockServer, err := server.NewServer()
+	// Inject faults from --faults and the admin API; dropping connections
+	// needs the listener wrapped
+	faults, err := loadFaults()
+	if err != nil {
+		fatal("Invalid fault rules", err)
+	}
+	lis = faults.Listener(lis)
+
+	// Storage, quotas and the admin API share one controllable clock
+	clk, err := newClock()
+	if err != nil {
+		fatal("Invalid freeze time", err)
+	}
+
+	// The gRPC backend is internal; requests are logged by the gateway.
+	m := metrics.New()
+	limiter, err := loadQuota(m, clk)
+	if err != nil {
+		fatal("Invalid quota", err)
+	}
+	recorder, err := openRecording(clk)
+	if err != nil {
+		fatal("Failed to open recording", err)
+	}
+	defer recorder.Close()
+	auditLog, err := openAuditLog(clk)
+	if err != nil {
+		fatal("Failed to open audit log", err)
+	}
+	defer auditLog.Close()
+	tokens, err := loadTokens(clk)
+	if err != nil {
+		fatal("Invalid tokens", err)
+	}
+	grpcServer := grpc.NewServer(append(append(creds.ServerOptions(), tokens.ServerOptions()...),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(m.UnaryServerInterceptor(), recorder.UnaryServerInterceptor(), auditLog.UnaryServerInterceptor(), limiter.UnaryServerInterceptor(), faults.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(m.StreamServerInterceptor()),
+	)...)
+	serverOpts := []server.Option{server.WithMetrics(m), server.WithClock(clk), server.WithPermissionCache(*iamCacheTTL)}
+	if *iamFilterLists {
+		serverOpts = append(serverOpts, server.WithListFiltering())
+	}
+	mockServer, err := server.NewServer(serverOpts...)
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		fatal("Failed to create server", err)
 	}
+	mockServer.Tenants().OnDelete(limiter.ForgetTenant)
 	secretmanagerpb.RegisterSecretManagerServiceServer(grpcServer, mockServer)
+
+	// Restore state from a snapshot
+	if *snapshotPath != "" {
+		resp, err := admin.ImportSnapshotFile(*snapshotPath, mockServer.Tenants())
+		if err != nil {
+			fatal("Failed to import snapshot", err)
+		}
+		logger.Info("Snapshot imported", "path", *snapshotPath, "secrets", resp.GetImportedSecrets(), "versions", resp.GetImportedVersions())
+	}
+
+	// Pre-populate storage from seed files
+	if *seedPath != "" {
+		files, err := seed.Load(*seedPath)
+		if err != nil {
+			fatal("Invalid seed", err)
+		}
+		summary, err := seed.ApplyTenants(context.Background(), mockServer.Tenants(), files...)
+		if err != nil {
+			fatal("Failed to apply seed", err)
+		}
+		logger.Info("Seed loaded", "path", *seedPath, "files", len(files), "secrets", summary.Secrets, "versions", summary.Versions)
+
+		if *seedWatch {
+			watchCtx, stopWatch := context.WithCancel(context.Background())
+			defer stopWatch()
+			go seed.NewWatcher(*seedPath, mockServer.Tenants(), seed.DefaultWatchInterval, logger).Run(watchCtx)
+			logger.Info("Watching seed for changes", "path", *seedPath)
+		}
+	}
 	reflection.Register(grpcServer)
 
+	// Register grpc.health.v1; readiness tracks the IAM emulator in strict mode
+	checker := health.NewChecker()
+	checker.AddCheck("iam", mockServer.CheckIAM)
+	checker.Register(grpcServer)
+
+	// Register the admin API only when asked; it bypasses IAM
+	if *enableAdmin {
+		admin.NewServer(mockServer.Storage(), admin.WithFaults(faults), admin.WithClock(clk), admin.WithAudit(auditLog), admin.WithTenants(mockServer.Tenants()), admin.WithTokens(tokens), admin.WithPermissionCache(mockServer.PermissionCache())).Register(grpcServer)
+		logger.Warn("Admin API enabled")
+	}
+
 	// Start gRPC server in background
 	go func() {
-		log.Printf("gRPC server listening at %v", lis.Addr())
+		logger.Info("gRPC server listening", "addr", listen.Address(lis.Addr()))
 		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("Failed to serve gRPC: %v", err)
+			fatal("Failed to serve gRPC", err)
 		}
 	}()
 
 	// Start REST gateway
-	httpAddr := fmt.Sprintf(":%d", *httpPort)
-	gateway := gateway.NewServer(grpcAddr)
+	httpAddr := *listenAddr
+	if httpAddr == "" {
+		httpAddr = fmt.Sprintf(":%d", *httpPort)
+	}
+	httpLis, err := listen.Listen(httpAddr)
+	if err != nil {
+		fatal("Failed to listen on HTTP port", err)
+	}
+	gateway := gateway.NewServer(listen.DialTarget(lis.Addr()), gatewayOptions(m, creds, tokens)...)
 
+	logger.Info("HTTP gateway listening", "addr", listen.Address(httpLis.Addr()))
+	if err := listen.Announce(os.Stdout, "http", httpLis); err != nil {
+		logger.Error("Failed to announce listener", "error", err)
+	}
 	go func() {
-		log.Printf("HTTP gateway listening at %s", httpAddr)
-		log.Printf("Ready to accept REST requests")
-		log.Printf("Example: curl http://localhost:%d/v1/projects/test-project/secrets", *httpPort)
-		if err := gateway.Start(ctx, httpAddr); err != nil {
-			log.Fatalf("Failed to serve HTTP: %v", err)
+		logger.Info("Ready to accept REST requests")
+		if err := gateway.Serve(httpLis); err != nil && err != http.ErrServerClosed {
+			fatal("Failed to serve HTTP", err)
 		}
 	}()
 
+	// Start admin server (metrics)
+	var adminServer *http.Server
+	if *adminPort > 0 {
+		adminServer = newAdminServer(*adminPort, m)
+		go func() {
+			logger.Info("Admin server listening", "addr", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fatal("Failed to serve admin endpoints", err)
+			}
+		}()
+	}
+
+	// Write snapshots on demand; SIGUSR1 is not available on Windows
+	if *snapshotOut != "" {
+		snapshotCtx, stopSnapshots := context.WithCancel(context.Background())
+		defer stopSnapshots()
+		go admin.ExportSnapshotOnSignal(snapshotCtx, *snapshotOut, mockServer.Tenants(), logger)
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down servers...")
+	logger.Info("Shutting down servers...")
+	checker.Shutdown()
 
 	// Shutdown REST gateway
 	if err := gateway.Stop(ctx); err != nil {
-		log.Printf("Error stopping HTTP gateway: %v", err)
+		logger.Error("Error stopping HTTP gateway", "error", err)
+	}
+
+	// Shutdown admin server
+	if adminServer != nil {
+		_ = adminServer.Shutdown(ctx)
 	}
 
 	// Shutdown gRPC server
 	grpcServer.GracefulStop()
 
-	log.Println("Servers stopped")
+	// Capture the final state after in-flight requests complete
+	if *snapshotOut != "" {
+		if snapshot, err := admin.ExportSnapshotFile(*snapshotOut, mockServer.Tenants()); err != nil {
+			logger.Error("Failed to write snapshot", "path", *snapshotOut, "error", err)
+		} else {
+			logger.Info("Snapshot written", "path", *snapshotOut, "secrets", len(snapshot.GetSecrets()), "tenants", len(snapshot.GetTenants()))
+		}
+	}
+
+	logger.Info("Servers stopped")
+}
+
+// gatewayOptions builds the REST gateway options from flags.
+func gatewayOptions(m *metrics.Metrics, creds *tlsutil.Credentials, tokens *token.Resolver) []gateway.Option {
+	opts := []gateway.Option{
+		gateway.WithMaxBodyBytes(int64(*maxBodyBytes)),
+		gateway.WithMaxAdminBodyBytes(int64(*maxAdminBodyBytes)),
+		gateway.WithCompression(!*disableCompression),
+		gateway.WithProtoFieldNames(*protoFieldNames),
+		gateway.WithLogger(slog.Default()),
+		gateway.WithMetrics(m),
+		gateway.WithTokens(tokens),
+	}
+	if *adminPort == 0 {
+		opts = append(opts, gateway.WithHandler("/metrics", m.Handler()))
+	}
+	if *corsOrigins != "" {
+		opts = append(opts, gateway.WithCORS(gateway.CORSConfig{
+			AllowedOrigins:   strings.Split(*corsOrigins, ","),
+			AllowCredentials: true,
+			MaxAge:           600,
+		}))
+	}
+	if *enableAdmin {
+		opts = append(opts, gateway.WithAdmin())
+	}
+	if creds != nil {
+		opts = append(opts, gateway.WithTLS(creds.Server), gateway.WithBackendTLS(creds.Gateway))
+	}
+	return opts
+}
+
+// newAdminServer returns an HTTP server for the admin endpoints.
+func newAdminServer(port int, m *metrics.Metrics) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	return &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+}
+
+// loadTLS builds TLS credentials from flags. Nil credentials mean plaintext.
+func loadTLS() (*tlsutil.Credentials, error) {
+	var hosts []string
+	if *tlsHosts != "" {
+		hosts = strings.Split(*tlsHosts, ",")
+	}
+	return tlsutil.Load(tlsutil.Options{
+		CertFile:     *tlsCert,
+		KeyFile:      *tlsKey,
+		ClientCAFile: *clientCA,
+		SelfSigned:   *tlsSelfSigned,
+		CAOut:        *tlsCAOut,
+		Hosts:        hosts,
+	})
+}
+
+// loadFaults creates the fault injector with the rules from --faults.
+func loadFaults() (*fault.Injector, error) {
+	faults := fault.NewInjector()
+	if *faultsPath == "" {
+		return faults, nil
+	}
+	rules, err := fault.Load(*faultsPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if _, err := faults.Add(rule); err != nil {
+			return nil, err
+		}
+	}
+	slog.Warn("Fault injection enabled", "path", *faultsPath, "rules", len(rules))
+	return faults, nil
+}
+
+// loadQuota creates the quota limiter from --quota and exposes its state in m.
+func loadQuota(m *metrics.Metrics, clk clock.Clock) (*quota.Limiter, error) {
+	limits, err := quota.ParseLimits(*quotaSpec)
+	if err != nil {
+		return nil, err
+	}
+	limiter := quota.NewLimiter(limits, clk)
+	if limiter.Enabled() {
+		m.RegisterQuota(limiter.Stats)
+		slog.Info("Quota enforcement enabled", "quota", *quotaSpec)
+	}
+	return limiter, nil
+}
+
+// openRecording starts recording Secret Manager calls to --record. The
+// recorder is nil, and records nothing, without it.
+func openRecording(clk clock.Clock) (*record.Recorder, error) {
+	if *recordPath == "" {
+		return nil, nil
+	}
+	recorder, err := record.Create(*recordPath, clk, *recordRedact)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Recording requests", "path", *recordPath, "redact", *recordRedact)
+	return recorder, nil
+}
+
+// openAuditLog starts writing audit entries to --audit-log. With the admin
+// API enabled, entries are kept for it even without --audit-log; otherwise
+// the logger is nil and audits nothing.
+func openAuditLog(clk clock.Clock) (*audit.Logger, error) {
+	if *auditLogPath == "" {
+		if *enableAdmin {
+			return audit.NewLogger(nil, clk), nil
+		}
+		return nil, nil
+	}
+	logger, err := audit.Create(*auditLogPath, clk)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Writing audit log", "path", *auditLogPath)
+	return logger, nil
+}
+
+// loadTokens creates the resolver deriving principals from bearer tokens,
+// with the static tokens from --tokens.
+func loadTokens(clk clock.Clock) (*token.Resolver, error) {
+	if *tokensPath == "" {
+		return token.NewResolver(clk, nil), nil
+	}
+	tokens, err := token.Load(*tokensPath)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Static bearer tokens loaded", "path", *tokensPath, "tokens", len(tokens))
+	return token.NewResolver(clk, tokens), nil
+}
+
+// newClock returns the emulator clock: running from real time, or frozen at
+// --freeze-time.
+func newClock() (*clock.Virtual, error) {
+	if *freezeTime == "" {
+		return clock.NewVirtual(), nil
+	}
+	t, err := time.Parse(time.RFC3339, *freezeTime)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Clock frozen", "time", t)
+	return clock.NewFrozen(t), nil
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
 
 func getEnv(key, defaultValue string) string {
@@ -120,3 +508,13 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvDuration returns environment variable as a duration or default
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -6,78 +6,420 @@
 // Usage:
 //
 //	gcp-secret-manager-mock --port 9090
+//	gcp-secret-manager-mock --listen unix:///tmp/sm.sock
+//
+// The bound address is printed to stdout as a JSON line, e.g.
+// {"event":"listening","protocol":"grpc","network":"tcp","address":"[::]:9090"}.
 //
 // Environment Variables:
 //
 //	GCP_MOCK_PORT        - Port to listen on (default: 9090)
+//	GCP_MOCK_LISTEN      - Address to listen on, overriding GCP_MOCK_PORT: host:port or unix:///path
 //	GCP_MOCK_LOG_LEVEL   - Log level: debug, info, warn, error (default: info)
+//	GCP_MOCK_LOG_FORMAT  - Log format: text, json (default: text)
+//	GCP_MOCK_ADMIN_PORT  - Admin HTTP port serving /metrics (default: 0, disabled)
+//	GCP_MOCK_TRACE_EXPORTER - Trace exporter: none, otlp, stdout (default: none)
+//	GCP_MOCK_TLS_CERT    - PEM server certificate; with GCP_MOCK_TLS_KEY enables TLS
+//	GCP_MOCK_TLS_KEY     - PEM server private key
+//	GCP_MOCK_CLIENT_CA   - PEM CA bundle for client certificates; enables mutual TLS
+//	GCP_MOCK_TLS_SELF_SIGNED - Set to "true" to serve TLS with a generated certificate
+//	GCP_MOCK_TLS_CA_OUT  - Where the self-signed CA bundle is written
+//	GCP_MOCK_TLS_HOSTS   - Comma-separated extra SANs for the self-signed certificate
+//	GCP_MOCK_ENABLE_ADMIN - Set to "true" to serve the admin API (reset, snapshots, stats)
+//	GCP_MOCK_SEED        - Seed file, or directory of seed files, loaded at startup
+//	GCP_MOCK_SEED_WATCH  - Set to "true" to re-apply seed files when they change
+//	GCP_MOCK_SNAPSHOT    - Snapshot file imported at startup
+//	GCP_MOCK_SNAPSHOT_OUT - Where a snapshot is written on SIGUSR1 and at shutdown
+//	GCP_MOCK_FAULTS      - YAML/JSON fault injection rules loaded at startup
+//	GCP_MOCK_QUOTA       - Per-project quotas: "default" and/or class=requests-per-minute pairs
+//	GCP_MOCK_FREEZE_TIME - RFC 3339 time to freeze the emulator clock at startup
+//	GCP_MOCK_RECORD      - JSONL file every Secret Manager call is appended to
+//	GCP_MOCK_RECORD_REDACT - Set to "true" to clear payloads from recorded calls
+//	GCP_MOCK_AUDIT_LOG   - JSONL file, or "-" for stdout, Cloud Audit Logs entries are appended to
+//	GCP_MOCK_TOKENS      - YAML/JSON file mapping bearer tokens to principals
+//	GCP_MOCK_IAM_CACHE_TTL - How long IAM decisions are cached, e.g. "30s" (default: 0, disabled); bounds how stale they get
+//	GCP_MOCK_IAM_FILTER_LISTS - Set to "true" to drop list results the caller may not get
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"log"
-	"net"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/audit"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/fault"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/health"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/listen"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/logging"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/quota"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/record"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/seed"
 	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/tlsutil"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/tracing"
 )
 
 var (
-	port     = flag.Int("port", getEnvInt("GCP_MOCK_PORT", 9090), "Port to listen on")
-	logLevel = flag.String("log-level", getEnv("GCP_MOCK_LOG_LEVEL", "info"), "Log level (debug, info, warn, error)")
-	version  = "1.1.0" // Will be updated during releases
+	port           = flag.Int("port", getEnvInt("GCP_MOCK_PORT", 9090), "Port to listen on")
+	listenAddr     = flag.String("listen", getEnv("GCP_MOCK_LISTEN", ""), "Address to listen on, overriding --port: host:port or unix:///path/to.sock")
+	logLevel       = flag.String("log-level", getEnv("GCP_MOCK_LOG_LEVEL", "info"), "Log level (debug, info, warn, error)")
+	logFormat      = flag.String("log-format", getEnv("GCP_MOCK_LOG_FORMAT", "text"), "Log format (text, json)")
+	adminPort      = flag.Int("admin-port", getEnvInt("GCP_MOCK_ADMIN_PORT", 0), "Admin HTTP port serving /metrics (0 disables)")
+	traceExporter  = flag.String("trace-exporter", getEnv("GCP_MOCK_TRACE_EXPORTER", "none"), "Trace exporter (none, otlp, stdout); otlp uses the OTEL_EXPORTER_OTLP_* environment variables")
+	tlsCert        = flag.String("tls-cert", getEnv("GCP_MOCK_TLS_CERT", ""), "PEM server certificate; enables TLS together with --tls-key")
+	tlsKey         = flag.String("tls-key", getEnv("GCP_MOCK_TLS_KEY", ""), "PEM server private key")
+	clientCA       = flag.String("client-ca", getEnv("GCP_MOCK_CLIENT_CA", ""), "PEM CA bundle trusted for client certificates; enables mutual TLS")
+	tlsSelfSigned  = flag.Bool("tls-self-signed", getEnv("GCP_MOCK_TLS_SELF_SIGNED", "") == "true", "Serve TLS with a certificate from a generated CA")
+	tlsCAOut       = flag.String("tls-ca-out", getEnv("GCP_MOCK_TLS_CA_OUT", tlsutil.DefaultCAOut), "Where --tls-self-signed writes the CA bundle for clients")
+	tlsHosts       = flag.String("tls-hosts", getEnv("GCP_MOCK_TLS_HOSTS", ""), "Comma-separated extra DNS names or IPs for the self-signed certificate")
+	enableAdmin    = flag.Bool("enable-admin", getEnv("GCP_MOCK_ENABLE_ADMIN", "") == "true", "Serve the admin API (reset, snapshots, stats); it bypasses IAM")
+	seedPath       = flag.String("seed", getEnv("GCP_MOCK_SEED", ""), "YAML/JSON seed file, or directory of seed files, to pre-populate secrets from")
+	seedWatch      = flag.Bool("seed-watch", getEnv("GCP_MOCK_SEED_WATCH", "") == "true", "Reconcile storage with --seed whenever the seed files change")
+	snapshotPath   = flag.String("snapshot", getEnv("GCP_MOCK_SNAPSHOT", ""), "Snapshot file to import at startup, as written by --snapshot-out or the admin API")
+	snapshotOut    = flag.String("snapshot-out", getEnv("GCP_MOCK_SNAPSHOT_OUT", ""), "Write a snapshot of all state to this file on SIGUSR1 and at shutdown")
+	faultsPath     = flag.String("faults", getEnv("GCP_MOCK_FAULTS", ""), "YAML/JSON file of fault injection rules for Secret Manager RPCs")
+	quotaSpec      = flag.String("quota", getEnv("GCP_MOCK_QUOTA", ""), "Per-project quotas: \"default\" for GCP's limits and/or class=requests-per-minute pairs for access, read and write")
+	freezeTime     = flag.String("freeze-time", getEnv("GCP_MOCK_FREEZE_TIME", ""), "Start with the emulator clock frozen at this RFC 3339 time; the admin API can advance it")
+	recordPath     = flag.String("record", getEnv("GCP_MOCK_RECORD", ""), "Append every Secret Manager request and response to this JSONL file, for the replay command")
+	recordRedact   = flag.Bool("record-redact", getEnv("GCP_MOCK_RECORD_REDACT", "") == "true", "Clear secret payloads and their checksums from --record")
+	auditLogPath   = flag.String("audit-log", getEnv("GCP_MOCK_AUDIT_LOG", ""), "Append Cloud Audit Logs entries for every Secret Manager call to this JSONL file, or \"-\" for stdout")
+	tokensPath     = flag.String("tokens", getEnv("GCP_MOCK_TOKENS", ""), "YAML/JSON file mapping bearer tokens to IAM principals; JWTs are decoded without it")
+	iamCacheTTL    = flag.Duration("iam-cache-ttl", getEnvDuration("GCP_MOCK_IAM_CACHE_TTL", 0), "How long strict-mode IAM decisions are cached (0 disables caching); IAM policy changes take up to this long to apply")
+	iamFilterLists = flag.Bool("iam-filter-lists", getEnv("GCP_MOCK_IAM_FILTER_LISTS", "") == "true", "Drop list results the caller may not get, checking each item with IAM; Secret Manager only checks the list permission")
+	version        = "1.1.0" // Will be updated during releases
 )
 
 func main() {
 	flag.Parse()
 
-	log.Printf("GCP Secret Manager Mock Server v%s", version)
-	log.Printf("Starting on port %d with log level: %s", *port, *logLevel)
+	logger, err := logging.New(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging configuration: %v\n", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:       *traceExporter,
+		ServiceName:    "gcp-secret-manager-emulator",
+		ServiceVersion: version,
+	})
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("Error flushing traces", "error", err)
+		}
+	}()
+
+	creds, err := loadTLS()
+	if err != nil {
+		fatal("Failed to configure TLS", err)
+	}
+	if creds != nil {
+		logger.Info("TLS enabled", "mutual_tls", creds.MutualTLS, "ca_bundle", creds.CAFile)
+	}
+
+	logger.Info("GCP Secret Manager Mock Server", "version", version, "port", *port, "log_level", *logLevel)
 
 	// Create listener
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+	addr := *listenAddr
+	if addr == "" {
+		addr = fmt.Sprintf(":%d", *port)
+	}
+	lis, err := listen.Listen(addr)
+	if err != nil {
+		fatal("Failed to listen", err)
+	}
+
+	// Inject faults from --faults and the admin API; dropping connections
+	// needs the listener wrapped
+	faults, err := loadFaults()
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+		fatal("Invalid fault rules", err)
 	}
+	lis = faults.Listener(lis)
 
-	// Create gRPC server
-	grpcServer := grpc.NewServer()
+	// Storage, quotas and the admin API share one controllable clock
+	clk, err := newClock()
+	if err != nil {
+		fatal("Invalid freeze time", err)
+	}
+
+	// Create gRPC server with request logging and metrics
+	m := metrics.New()
+	limiter, err := loadQuota(m, clk)
+	if err != nil {
+		fatal("Invalid quota", err)
+	}
+	recorder, err := openRecording(clk)
+	if err != nil {
+		fatal("Failed to open recording", err)
+	}
+	defer recorder.Close()
+	auditLog, err := openAuditLog(clk)
+	if err != nil {
+		fatal("Failed to open audit log", err)
+	}
+	defer auditLog.Close()
+	tokens, err := loadTokens(clk)
+	if err != nil {
+		fatal("Invalid tokens", err)
+	}
+	grpcServer := grpc.NewServer(append(append(creds.ServerOptions(), tokens.ServerOptions()...),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(logger), m.UnaryServerInterceptor(), recorder.UnaryServerInterceptor(), auditLog.UnaryServerInterceptor(), limiter.UnaryServerInterceptor(), faults.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor(logger), m.StreamServerInterceptor()),
+	)...)
 
 	// Create and register mock service
-	mockServer, err := server.NewServer()
+	serverOpts := []server.Option{server.WithMetrics(m), server.WithClock(clk), server.WithPermissionCache(*iamCacheTTL)}
+	if *iamFilterLists {
+		serverOpts = append(serverOpts, server.WithListFiltering())
+	}
+	mockServer, err := server.NewServer(serverOpts...)
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		fatal("Failed to create server", err)
 	}
+	mockServer.Tenants().OnDelete(limiter.ForgetTenant)
 	secretmanagerpb.RegisterSecretManagerServiceServer(grpcServer, mockServer)
 
+	// Restore state from a snapshot
+	if *snapshotPath != "" {
+		resp, err := admin.ImportSnapshotFile(*snapshotPath, mockServer.Tenants())
+		if err != nil {
+			fatal("Failed to import snapshot", err)
+		}
+		logger.Info("Snapshot imported", "path", *snapshotPath, "secrets", resp.GetImportedSecrets(), "versions", resp.GetImportedVersions())
+	}
+
+	// Pre-populate storage from seed files
+	if *seedPath != "" {
+		files, err := seed.Load(*seedPath)
+		if err != nil {
+			fatal("Invalid seed", err)
+		}
+		summary, err := seed.ApplyTenants(context.Background(), mockServer.Tenants(), files...)
+		if err != nil {
+			fatal("Failed to apply seed", err)
+		}
+		logger.Info("Seed loaded", "path", *seedPath, "files", len(files), "secrets", summary.Secrets, "versions", summary.Versions)
+
+		if *seedWatch {
+			watchCtx, stopWatch := context.WithCancel(context.Background())
+			defer stopWatch()
+			go seed.NewWatcher(*seedPath, mockServer.Tenants(), seed.DefaultWatchInterval, logger).Run(watchCtx)
+			logger.Info("Watching seed for changes", "path", *seedPath)
+		}
+	}
+
 	// Register reflection service (for grpc_cli debugging)
 	reflection.Register(grpcServer)
 
-	log.Printf("Server listening at %v", lis.Addr())
-	log.Printf("Ready to accept connections")
+	// Register grpc.health.v1; readiness tracks the IAM emulator in strict mode
+	checker := health.NewChecker()
+	checker.AddCheck("iam", mockServer.CheckIAM)
+	checker.Register(grpcServer)
+
+	// Register the admin API only when asked; it bypasses IAM
+	if *enableAdmin {
+		admin.NewServer(mockServer.Storage(), admin.WithFaults(faults), admin.WithClock(clk), admin.WithAudit(auditLog), admin.WithTenants(mockServer.Tenants()), admin.WithTokens(tokens), admin.WithPermissionCache(mockServer.PermissionCache())).Register(grpcServer)
+		logger.Warn("Admin API enabled")
+	}
+
+	logger.Info("Server listening", "addr", listen.Address(lis.Addr()))
+	if err := listen.Announce(os.Stdout, "grpc", lis); err != nil {
+		logger.Error("Failed to announce listener", "error", err)
+	}
+	logger.Info("Ready to accept connections")
 
 	// Start server in goroutine
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("Failed to serve: %v", err)
+			fatal("Failed to serve", err)
 		}
 	}()
 
+	// Start admin server (metrics)
+	var adminServer *http.Server
+	if *adminPort > 0 {
+		adminServer = newAdminServer(*adminPort, m)
+		go func() {
+			logger.Info("Admin server listening", "addr", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fatal("Failed to serve admin endpoints", err)
+			}
+		}()
+	}
+
+	// Write snapshots on demand; SIGUSR1 is not available on Windows
+	if *snapshotOut != "" {
+		snapshotCtx, stopSnapshots := context.WithCancel(context.Background())
+		defer stopSnapshots()
+		go admin.ExportSnapshotOnSignal(snapshotCtx, *snapshotOut, mockServer.Tenants(), logger)
+	}
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	logger.Info("Shutting down server...")
+	checker.Shutdown()
+	if adminServer != nil {
+		_ = adminServer.Shutdown(context.Background())
+	}
 	grpcServer.GracefulStop()
-	log.Println("Server stopped")
+
+	// Capture the final state after in-flight requests complete
+	if *snapshotOut != "" {
+		if snapshot, err := admin.ExportSnapshotFile(*snapshotOut, mockServer.Tenants()); err != nil {
+			logger.Error("Failed to write snapshot", "path", *snapshotOut, "error", err)
+		} else {
+			logger.Info("Snapshot written", "path", *snapshotOut, "secrets", len(snapshot.GetSecrets()), "tenants", len(snapshot.GetTenants()))
+		}
+	}
+
+	logger.Info("Server stopped")
+}
+
+// newAdminServer returns an HTTP server for the admin endpoints.
+func newAdminServer(port int, m *metrics.Metrics) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	return &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+}
+
+// loadTLS builds TLS credentials from flags. Nil credentials mean plaintext.
+func loadTLS() (*tlsutil.Credentials, error) {
+	var hosts []string
+	if *tlsHosts != "" {
+		hosts = strings.Split(*tlsHosts, ",")
+	}
+	return tlsutil.Load(tlsutil.Options{
+		CertFile:     *tlsCert,
+		KeyFile:      *tlsKey,
+		ClientCAFile: *clientCA,
+		SelfSigned:   *tlsSelfSigned,
+		CAOut:        *tlsCAOut,
+		Hosts:        hosts,
+	})
+}
+
+// loadFaults creates the fault injector with the rules from --faults.
+func loadFaults() (*fault.Injector, error) {
+	faults := fault.NewInjector()
+	if *faultsPath == "" {
+		return faults, nil
+	}
+	rules, err := fault.Load(*faultsPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if _, err := faults.Add(rule); err != nil {
+			return nil, err
+		}
+	}
+	slog.Warn("Fault injection enabled", "path", *faultsPath, "rules", len(rules))
+	return faults, nil
+}
+
+// loadQuota creates the quota limiter from --quota and exposes its state in m.
+func loadQuota(m *metrics.Metrics, clk clock.Clock) (*quota.Limiter, error) {
+	limits, err := quota.ParseLimits(*quotaSpec)
+	if err != nil {
+		return nil, err
+	}
+	limiter := quota.NewLimiter(limits, clk)
+	if limiter.Enabled() {
+		m.RegisterQuota(limiter.Stats)
+		slog.Info("Quota enforcement enabled", "quota", *quotaSpec)
+	}
+	return limiter, nil
+}
+
+// openRecording starts recording Secret Manager calls to --record. The
+// recorder is nil, and records nothing, without it.
+func openRecording(clk clock.Clock) (*record.Recorder, error) {
+	if *recordPath == "" {
+		return nil, nil
+	}
+	recorder, err := record.Create(*recordPath, clk, *recordRedact)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Recording requests", "path", *recordPath, "redact", *recordRedact)
+	return recorder, nil
+}
+
+// openAuditLog starts writing audit entries to --audit-log. With the admin
+// API enabled, entries are kept for it even without --audit-log; otherwise
+// the logger is nil and audits nothing.
+func openAuditLog(clk clock.Clock) (*audit.Logger, error) {
+	if *auditLogPath == "" {
+		if *enableAdmin {
+			return audit.NewLogger(nil, clk), nil
+		}
+		return nil, nil
+	}
+	logger, err := audit.Create(*auditLogPath, clk)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Writing audit log", "path", *auditLogPath)
+	return logger, nil
+}
+
+// loadTokens creates the resolver deriving principals from bearer tokens,
+// with the static tokens from --tokens.
+func loadTokens(clk clock.Clock) (*token.Resolver, error) {
+	if *tokensPath == "" {
+		return token.NewResolver(clk, nil), nil
+	}
+	tokens, err := token.Load(*tokensPath)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Static bearer tokens loaded", "path", *tokensPath, "tokens", len(tokens))
+	return token.NewResolver(clk, tokens), nil
+}
+
+// newClock returns the emulator clock: running from real time, or frozen at
+// --freeze-time.
+func newClock() (*clock.Virtual, error) {
+	if *freezeTime == "" {
+		return clock.NewVirtual(), nil
+	}
+	t, err := time.Parse(time.RFC3339, *freezeTime)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Clock frozen", "time", t)
+	return clock.NewFrozen(t), nil
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
 
 // getEnv returns environment variable value or default
@@ -98,3 +440,13 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvDuration returns environment variable as a duration or default
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
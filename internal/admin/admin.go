@@ -0,0 +1,265 @@
+// Package admin implements the emulator's admin API
+// (emulator.secretmanager.admin.v1.AdminService): reset, snapshot export and
+// import, listing, stats, fault injection rules, the emulator clock, tenants,
+// checkpoints, recent audit log entries, bearer tokens for tests and the IAM
+// decision cache.
+//
+// The admin API is not part of Google Cloud Secret Manager and is only
+// registered when explicitly enabled (--enable-admin). It bypasses IAM
+// checks, so it must not be exposed where the emulator's state matters.
+// The REST gateway serves it under /admin/v1 from the google.api.http
+// annotations in proto/admin/v1/admin.proto.
+package admin
+
+import (
+	"context"
+	"hash/crc32"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/audit"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/authz"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/fault"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
+)
+
+// crc32cTable is the Castagnoli table used for payload checksums.
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Server implements the admin API over the emulator's storage.
+type Server struct {
+	adminpb.UnimplementedAdminServiceServer
+	storage *server.Storage
+	faults  *fault.Injector
+	clock   *clock.Virtual
+	audit   *audit.Logger
+	tenants *server.Tenants
+	tokens  *token.Resolver
+	cache   *authz.DecisionCache
+}
+
+// Option configures the admin API server.
+type Option func(*Server)
+
+// WithFaults manages the rules of injector through the fault RPCs. Without
+// it they fail with FAILED_PRECONDITION.
+func WithFaults(injector *fault.Injector) Option {
+	return func(a *Server) {
+		a.faults = injector
+	}
+}
+
+// WithClock controls c through the clock RPCs. c should be the clock storage
+// uses. Without it the clock can only be read.
+func WithClock(c *clock.Virtual) Option {
+	return func(a *Server) {
+		a.clock = c
+	}
+}
+
+// WithAudit serves the entries logger keeps through the audit log RPCs.
+// Without it they fail with FAILED_PRECONDITION.
+func WithAudit(logger *audit.Logger) Option {
+	return func(a *Server) {
+		a.audit = logger
+	}
+}
+
+// WithTenants makes the RPCs act on the tenant a call selects, as the
+// Secret Manager service does, and manages tenants through the tenant RPCs.
+// Without it they act on storage and the tenant RPCs fail with
+// FAILED_PRECONDITION.
+func WithTenants(tenants *server.Tenants) Option {
+	return func(a *Server) {
+		a.tenants = tenants
+	}
+}
+
+// WithTokens mints bearer tokens that resolver maps back to their principal
+// through MintToken. Without it MintToken fails with FAILED_PRECONDITION.
+func WithTokens(resolver *token.Resolver) Option {
+	return func(a *Server) {
+		a.tokens = resolver
+	}
+}
+
+// WithPermissionCache clears cache through ClearPermissionCache. Without it,
+// or with a nil cache, ClearPermissionCache fails with FAILED_PRECONDITION.
+func WithPermissionCache(cache *authz.DecisionCache) Option {
+	return func(a *Server) {
+		a.cache = cache
+	}
+}
+
+// NewServer creates an admin API server for storage.
+func NewServer(storage *server.Storage, opts ...Option) *Server {
+	a := &Server{storage: storage}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Register registers the admin service on a gRPC server.
+func (a *Server) Register(s grpc.ServiceRegistrar) {
+	adminpb.RegisterAdminServiceServer(s, a)
+}
+
+// Reset deletes every secret, or every secret of one project.
+func (a *Server) Reset(ctx context.Context, req *adminpb.ResetRequest) (*adminpb.ResetResponse, error) {
+	if err := validateParent(req.GetParent()); err != nil {
+		return nil, err
+	}
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n := storage.Reset(req.GetParent())
+	return &adminpb.ResetResponse{DeletedSecrets: int32(n)}, nil
+}
+
+// ExportSnapshot returns every secret and version, including payloads.
+func (a *Server) ExportSnapshot(ctx context.Context, _ *adminpb.ExportSnapshotRequest) (*adminpb.Snapshot, error) {
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ToSnapshot(storage.Secrets(), storage.Clock().Now()), nil
+}
+
+// ImportSnapshot adds the secrets of a snapshot to storage.
+func (a *Server) ImportSnapshot(ctx context.Context, req *adminpb.ImportSnapshotRequest) (*adminpb.ImportSnapshotResponse, error) {
+	if len(req.GetSnapshot().GetTenants()) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot holds other tenants; import it at startup with --snapshot")
+	}
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := FromSnapshot(req.GetSnapshot(), storage.Clock().Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.Import(secrets, req.GetReplace()); err != nil {
+		return nil, err
+	}
+
+	resp := &adminpb.ImportSnapshotResponse{ImportedSecrets: int32(len(secrets))}
+	for _, stored := range secrets {
+		resp.ImportedVersions += int32(len(stored.Versions))
+	}
+	return resp, nil
+}
+
+// ListSecrets lists every secret with version and payload metadata.
+func (a *Server) ListSecrets(ctx context.Context, req *adminpb.ListSecretsRequest) (*adminpb.ListSecretsResponse, error) {
+	if err := validateParent(req.GetParent()); err != nil {
+		return nil, err
+	}
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &adminpb.ListSecretsResponse{}
+	for _, stored := range storage.Secrets() {
+		if req.GetParent() != "" && projectOf(stored.Name) != req.GetParent() {
+			continue
+		}
+		summary := &adminpb.SecretSummary{Secret: secretProto(stored)}
+		for _, version := range sortedVersions(stored) {
+			summary.Versions = append(summary.Versions, &adminpb.VersionSummary{
+				Version:          versionProto(version),
+				PayloadSizeBytes: int64(len(version.Payload)),
+				PayloadCrc32C:    int64(crc32.Checksum(version.Payload, crc32cTable)),
+			})
+		}
+		resp.Secrets = append(resp.Secrets, summary)
+	}
+	return resp, nil
+}
+
+// GetStats returns counts of projects, secrets and versions.
+func (a *Server) GetStats(ctx context.Context, _ *adminpb.GetStatsRequest) (*adminpb.Stats, error) {
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats := &adminpb.Stats{Versions: make(map[string]int32)}
+	projects := make(map[string]bool)
+	for _, stored := range storage.Secrets() {
+		projects[projectOf(stored.Name)] = true
+		stats.Secrets++
+		for _, version := range stored.Versions {
+			stats.Versions[version.State.String()]++
+			stats.PayloadBytes += int64(len(version.Payload))
+		}
+	}
+	stats.Projects = int32(len(projects))
+	return stats, nil
+}
+
+// validateParent checks that a project filter is empty or "projects/{project}".
+func validateParent(parent string) error {
+	if parent == "" {
+		return nil
+	}
+	parts := strings.Split(parent, "/")
+	if len(parts) != 2 || parts[0] != "projects" || parts[1] == "" {
+		return status.Errorf(codes.InvalidArgument, "Invalid parent %q: want projects/{project}", parent)
+	}
+	return nil
+}
+
+// projectOf returns the "projects/{project}" prefix of a resource name.
+func projectOf(name string) string {
+	parts := strings.SplitN(name, "/", 3)
+	if len(parts) < 2 {
+		return name
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// secretProto returns the metadata of a stored secret.
+func secretProto(stored *server.StoredSecret) *secretmanagerpb.Secret {
+	secret := &secretmanagerpb.Secret{
+		Name:           stored.Name,
+		CreateTime:     stored.CreateTime,
+		Labels:         stored.Labels,
+		Annotations:    stored.Annotations,
+		Replication:    stored.Replication,
+		VersionAliases: stored.VersionAliases,
+	}
+	if stored.ExpireTime != nil {
+		secret.Expiration = &secretmanagerpb.Secret_ExpireTime{ExpireTime: stored.ExpireTime}
+	}
+	return secret
+}
+
+// versionProto returns the metadata of a stored version.
+func versionProto(version *server.StoredVersion) *secretmanagerpb.SecretVersion {
+	return &secretmanagerpb.SecretVersion{
+		Name:       version.Name,
+		CreateTime: version.CreateTime,
+		State:      version.State,
+	}
+}
+
+// sortedVersions returns a secret's versions ordered by number.
+func sortedVersions(stored *server.StoredSecret) []*server.StoredVersion {
+	versions := make([]*server.StoredVersion, 0, len(stored.Versions))
+	for n := int64(1); n < stored.NextVersion; n++ {
+		if version, ok := stored.Versions[strconv.FormatInt(n, 10)]; ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
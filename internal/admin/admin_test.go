@@ -0,0 +1,601 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/audit"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/authz"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/fault"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
+)
+
+// newTestStorage returns storage with two projects: projects/a holds secret
+// "db" with three versions (2 disabled, 3 destroyed) and an alias, and
+// projects/b holds an empty secret.
+func newTestStorage(t *testing.T) *server.Storage {
+	t.Helper()
+	ctx := context.Background()
+
+	storage := server.NewStorage()
+	if _, err := storage.CreateSecret(ctx, "projects/a", "db", &secretmanagerpb.Secret{Labels: map[string]string{"env": "test"}}); err != nil {
+		t.Fatalf("CreateSecret() error = %v", err)
+	}
+	for _, payload := range []string{"one", "two", "three"} {
+		if _, err := storage.AddSecretVersion(ctx, "projects/a/secrets/db", &secretmanagerpb.SecretPayload{Data: []byte(payload)}); err != nil {
+			t.Fatalf("AddSecretVersion() error = %v", err)
+		}
+	}
+	if _, err := storage.DisableSecretVersion(ctx, "projects/a/secrets/db/versions/2"); err != nil {
+		t.Fatalf("DisableSecretVersion() error = %v", err)
+	}
+	if _, err := storage.DestroySecretVersion(ctx, "projects/a/secrets/db/versions/3"); err != nil {
+		t.Fatalf("DestroySecretVersion() error = %v", err)
+	}
+	if _, err := storage.UpdateSecret(ctx, "projects/a/secrets/db", nil, nil, map[string]int64{"current": 1}); err != nil {
+		t.Fatalf("UpdateSecret() error = %v", err)
+	}
+	if _, err := storage.CreateSecret(ctx, "projects/b", "empty", &secretmanagerpb.Secret{}); err != nil {
+		t.Fatalf("CreateSecret() error = %v", err)
+	}
+	return storage
+}
+
+func TestReset(t *testing.T) {
+	ctx := context.Background()
+	a := NewServer(newTestStorage(t))
+
+	resp, err := a.Reset(ctx, &adminpb.ResetRequest{Parent: "projects/b"})
+	if err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if resp.DeletedSecrets != 1 {
+		t.Errorf("Reset(projects/b) deleted %d secrets, want 1", resp.DeletedSecrets)
+	}
+
+	resp, err = a.Reset(ctx, &adminpb.ResetRequest{})
+	if err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if resp.DeletedSecrets != 1 {
+		t.Errorf("Reset() deleted %d secrets, want 1", resp.DeletedSecrets)
+	}
+
+	_, err = a.Reset(ctx, &adminpb.ResetRequest{Parent: "projects/a/secrets/db"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Reset(secret name) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := NewServer(newTestStorage(t))
+
+	snapshot, err := source.ExportSnapshot(ctx, &adminpb.ExportSnapshotRequest{})
+	if err != nil {
+		t.Fatalf("ExportSnapshot() error = %v", err)
+	}
+
+	// Snapshots survive serialization unchanged
+	data, err := proto.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded adminpb.Snapshot
+	if err := proto.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := server.NewStorage()
+	target := NewServer(storage)
+	resp, err := target.ImportSnapshot(ctx, &adminpb.ImportSnapshotRequest{Snapshot: &decoded})
+	if err != nil {
+		t.Fatalf("ImportSnapshot() error = %v", err)
+	}
+	if resp.ImportedSecrets != 2 || resp.ImportedVersions != 3 {
+		t.Errorf("ImportSnapshot() = %v, want 2 secrets and 3 versions", resp)
+	}
+
+	again, err := target.ExportSnapshot(ctx, &adminpb.ExportSnapshotRequest{})
+	if err != nil {
+		t.Fatalf("ExportSnapshot() error = %v", err)
+	}
+	again.CreateTime = snapshot.CreateTime
+	if !proto.Equal(again, snapshot) {
+		t.Errorf("round trip changed the snapshot:\n got %v\nwant %v", again, snapshot)
+	}
+
+	// Numbering continues where the source left off
+	version, err := storage.AddSecretVersion(ctx, "projects/a/secrets/db", &secretmanagerpb.SecretPayload{Data: []byte("four")})
+	if err != nil {
+		t.Fatalf("AddSecretVersion() error = %v", err)
+	}
+	if version.Name != "projects/a/secrets/db/versions/4" {
+		t.Errorf("AddSecretVersion() after import = %s, want versions/4", version.Name)
+	}
+
+	// Importing over existing secrets requires replace
+	_, err = target.ImportSnapshot(ctx, &adminpb.ImportSnapshotRequest{Snapshot: snapshot})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("ImportSnapshot() over existing secrets error = %v, want AlreadyExists", err)
+	}
+	if _, err := target.ImportSnapshot(ctx, &adminpb.ImportSnapshotRequest{Snapshot: snapshot, Replace: true}); err != nil {
+		t.Errorf("ImportSnapshot(replace) error = %v", err)
+	}
+}
+
+func TestSnapshotFile(t *testing.T) {
+	source := server.NewTenants(clock.Real{})
+	job, err := source.Get("job-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, storage := range []*server.Storage{source.Default(), job} {
+		secrets, err := FromSnapshot(ToSnapshot(newTestStorage(t).Secrets(), time.Now()), time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := storage.Import(secrets, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "state", "snapshot.json")
+
+	snapshot, err := ExportSnapshotFile(path, source)
+	if err != nil {
+		t.Fatalf("ExportSnapshotFile() error = %v", err)
+	}
+	if snapshot.FormatVersion != SnapshotFormatVersion {
+		t.Errorf("FormatVersion = %d, want %d", snapshot.FormatVersion, SnapshotFormatVersion)
+	}
+	if len(snapshot.Tenants) != 1 || snapshot.Tenants[0].Tenant != "job-42" {
+		t.Errorf("Tenants = %v, want job-42", snapshot.Tenants)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("snapshot file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Every tenant restored from the file is identical, timestamps and
+	// counters included
+	target := server.NewTenants(clock.Real{})
+	resp, err := ImportSnapshotFile(path, target)
+	if err != nil {
+		t.Fatalf("ImportSnapshotFile() error = %v", err)
+	}
+	if resp.ImportedSecrets != 4 || resp.ImportedVersions != 6 {
+		t.Errorf("ImportSnapshotFile() = %v, want 4 secrets and 6 versions", resp)
+	}
+	got, want := TenantsSnapshot(target), TenantsSnapshot(source)
+	got.CreateTime, want.CreateTime = nil, nil
+	if !proto.Equal(got, want) {
+		t.Errorf("restored tenants differ:\n got %v\nwant %v", got, want)
+	}
+
+	// A tenant that cannot be imported fails the import before any tenant is
+	// imported or created
+	conflicting := server.NewTenants(clock.Real{})
+	held, err := conflicting.Get("job-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := held.Import(job.Secrets(), false); err != nil {
+		t.Fatal(err)
+	}
+	snapshot.Tenants = append(snapshot.Tenants, &adminpb.TenantSnapshot{Tenant: "job-7", Secrets: snapshot.Secrets})
+	conflictPath := filepath.Join(t.TempDir(), "conflict.json")
+	data, err := protojson.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(conflictPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportSnapshotFile(conflictPath, conflicting); status.Code(errors.Unwrap(err)) != codes.AlreadyExists {
+		t.Errorf("ImportSnapshotFile() into existing secrets error = %v, want AlreadyExists", err)
+	}
+	if n := conflicting.Default().SecretCount(); n != 0 || !slices.Equal(conflicting.List(), []string{"job-42"}) {
+		t.Errorf("failed import left %d secrets in the default tenant and tenants %v", n, conflicting.List())
+	}
+
+	// The ImportSnapshot RPC acts on one tenant, so it refuses others
+	_, err = NewServer(server.NewStorage()).ImportSnapshot(context.Background(), &adminpb.ImportSnapshotRequest{Snapshot: snapshot})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ImportSnapshot() with tenants error = %v, want InvalidArgument", err)
+	}
+
+	if _, err := ImportSnapshotFile(filepath.Join(t.TempDir(), "missing.json"), server.NewTenants(clock.Real{})); err == nil {
+		t.Error("ImportSnapshotFile(missing file) error = nil")
+	}
+}
+
+func TestImportSnapshot_NewerFormat(t *testing.T) {
+	a := NewServer(server.NewStorage())
+	_, err := a.ImportSnapshot(context.Background(), &adminpb.ImportSnapshotRequest{
+		Snapshot: &adminpb.Snapshot{FormatVersion: SnapshotFormatVersion + 1},
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ImportSnapshot(newer format) error = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestImportSnapshot_Invalid(t *testing.T) {
+	ctx := context.Background()
+	a := NewServer(server.NewStorage())
+
+	tests := map[string]*adminpb.SnapshotSecret{
+		"bad secret name": {
+			Secret: &secretmanagerpb.Secret{Name: "secrets/x"},
+		},
+		"bad version name": {
+			Secret: &secretmanagerpb.Secret{Name: "projects/p/secrets/x"},
+			Versions: []*adminpb.SnapshotVersion{
+				{Version: &secretmanagerpb.SecretVersion{Name: "projects/p/secrets/x/versions/latest"}},
+			},
+		},
+		"version of another secret": {
+			Secret: &secretmanagerpb.Secret{Name: "projects/p/secrets/x"},
+			Versions: []*adminpb.SnapshotVersion{
+				{Version: &secretmanagerpb.SecretVersion{Name: "projects/p/secrets/y/versions/1"}},
+			},
+		},
+		"alias to missing version": {
+			Secret: &secretmanagerpb.Secret{Name: "projects/p/secrets/x", VersionAliases: map[string]int64{"current": 7}},
+		},
+	}
+	for name, secret := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := a.ImportSnapshot(ctx, &adminpb.ImportSnapshotRequest{
+				Snapshot: &adminpb.Snapshot{Secrets: []*adminpb.SnapshotSecret{secret}},
+			})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("ImportSnapshot() error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestListSecrets(t *testing.T) {
+	ctx := context.Background()
+	a := NewServer(newTestStorage(t))
+
+	resp, err := a.ListSecrets(ctx, &adminpb.ListSecretsRequest{Parent: "projects/a"})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if len(resp.Secrets) != 1 {
+		t.Fatalf("ListSecrets(projects/a) returned %d secrets, want 1", len(resp.Secrets))
+	}
+
+	summary := resp.Secrets[0]
+	if summary.Secret.Labels["env"] != "test" || summary.Secret.VersionAliases["current"] != 1 {
+		t.Errorf("secret metadata = %v", summary.Secret)
+	}
+	if len(summary.Versions) != 3 {
+		t.Fatalf("got %d versions, want 3", len(summary.Versions))
+	}
+	first := summary.Versions[0]
+	if first.PayloadSizeBytes != 3 || first.PayloadCrc32C == 0 {
+		t.Errorf("version 1 payload metadata = %d bytes, crc32c %d", first.PayloadSizeBytes, first.PayloadCrc32C)
+	}
+	if destroyed := summary.Versions[2]; destroyed.Version.State != secretmanagerpb.SecretVersion_DESTROYED || destroyed.PayloadSizeBytes != 0 {
+		t.Errorf("version 3 = %v, want destroyed without payload", destroyed)
+	}
+
+	resp, err = a.ListSecrets(ctx, &adminpb.ListSecretsRequest{})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if len(resp.Secrets) != 2 {
+		t.Errorf("ListSecrets() returned %d secrets, want 2", len(resp.Secrets))
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	a := NewServer(newTestStorage(t))
+
+	stats, err := a.GetStats(context.Background(), &adminpb.GetStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if stats.Projects != 2 || stats.Secrets != 2 {
+		t.Errorf("GetStats() = %d projects, %d secrets; want 2, 2", stats.Projects, stats.Secrets)
+	}
+	want := map[string]int32{"ENABLED": 1, "DISABLED": 1, "DESTROYED": 1}
+	for state, n := range want {
+		if stats.Versions[state] != n {
+			t.Errorf("Versions[%s] = %d, want %d", state, stats.Versions[state], n)
+		}
+	}
+	if stats.PayloadBytes != int64(len("one")+len("two")) {
+		t.Errorf("PayloadBytes = %d, want 6", stats.PayloadBytes)
+	}
+}
+
+func TestFaultRules(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewServer(server.NewStorage()).ListFaultRules(ctx, &adminpb.ListFaultRulesRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ListFaultRules() without injector error = %v, want FailedPrecondition", err)
+	}
+
+	a := NewServer(server.NewStorage(), WithFaults(fault.NewInjector()))
+	rule, err := a.CreateFaultRule(ctx, &adminpb.CreateFaultRuleRequest{Rule: &adminpb.FaultRule{
+		Name:       "faults/ignored",
+		Method:     "AccessSecretVersion",
+		Code:       "resource_exhausted",
+		RetryDelay: durationpb.New(time.Second),
+		Injections: 9,
+	}})
+	if err != nil {
+		t.Fatalf("CreateFaultRule() error = %v", err)
+	}
+	want := &adminpb.FaultRule{Name: "faults/1", Method: "AccessSecretVersion", Code: "RESOURCE_EXHAUSTED", RetryDelay: durationpb.New(time.Second)}
+	if !proto.Equal(rule, want) {
+		t.Errorf("CreateFaultRule() = %v, want %v", rule, want)
+	}
+
+	for name, r := range map[string]*adminpb.FaultRule{
+		"unknown code": {Code: "TEAPOT"},
+		"no action":    {Method: "GetSecret"},
+	} {
+		if _, err := a.CreateFaultRule(ctx, &adminpb.CreateFaultRuleRequest{Rule: r}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("CreateFaultRule(%s) error = %v, want InvalidArgument", name, err)
+		}
+	}
+
+	list, err := a.ListFaultRules(ctx, &adminpb.ListFaultRulesRequest{})
+	if err != nil || len(list.Rules) != 1 {
+		t.Fatalf("ListFaultRules() = %v, %v; want one rule", list, err)
+	}
+	if _, err := a.DeleteFaultRule(ctx, &adminpb.DeleteFaultRuleRequest{Name: "1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("DeleteFaultRule(bad name) error = %v, want InvalidArgument", err)
+	}
+	if _, err := a.DeleteFaultRule(ctx, &adminpb.DeleteFaultRuleRequest{Name: "faults/1"}); err != nil {
+		t.Errorf("DeleteFaultRule() error = %v", err)
+	}
+	if _, err := a.DeleteFaultRule(ctx, &adminpb.DeleteFaultRuleRequest{Name: "faults/1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("DeleteFaultRule(deleted) error = %v, want NotFound", err)
+	}
+}
+
+func TestClock(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewServer(server.NewStorage()).AdvanceClock(ctx, &adminpb.AdvanceClockRequest{Duration: durationpb.New(time.Hour)})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("AdvanceClock() without a virtual clock error = %v, want FailedPrecondition", err)
+	}
+
+	c := clock.NewVirtual()
+	storage := server.NewStorageWithClock(c)
+	a := NewServer(storage, WithClock(c))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	got, err := a.FreezeClock(ctx, &adminpb.FreezeClockRequest{Time: timestamppb.New(start)})
+	if err != nil || !got.GetFrozen() || !got.GetTime().AsTime().Equal(start) {
+		t.Fatalf("FreezeClock() = %v, %v; want frozen at %v", got, err, start)
+	}
+
+	secret, err := storage.CreateSecret(ctx, "projects/p", "s", &secretmanagerpb.Secret{
+		Expiration: &secretmanagerpb.Secret_Ttl{Ttl: durationpb.New(time.Hour)},
+	})
+	if err != nil || !secret.GetCreateTime().AsTime().Equal(start) {
+		t.Fatalf("CreateSecret() = %v, %v; want created at %v", secret, err, start)
+	}
+	got, err = a.AdvanceClock(ctx, &adminpb.AdvanceClockRequest{Duration: durationpb.New(time.Hour)})
+	if err != nil || !got.GetTime().AsTime().Equal(start.Add(time.Hour)) {
+		t.Fatalf("AdvanceClock() = %v, %v", got, err)
+	}
+	if storage.SecretCount() != 0 {
+		t.Error("secret not expired after advancing past its TTL")
+	}
+	if _, err := a.AdvanceClock(ctx, &adminpb.AdvanceClockRequest{Duration: durationpb.New(-time.Hour)}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("AdvanceClock(-1h) error = %v, want InvalidArgument", err)
+	}
+
+	got, err = a.ResumeClock(ctx, &adminpb.ResumeClockRequest{})
+	if err != nil || got.GetFrozen() || got.GetTime().AsTime().Before(start.Add(time.Hour)) {
+		t.Errorf("ResumeClock() = %v, %v; want running from where it stopped", got, err)
+	}
+	if got, err := a.GetClock(ctx, &adminpb.GetClockRequest{}); err != nil || got.GetFrozen() {
+		t.Errorf("GetClock() = %v, %v", got, err)
+	}
+}
+
+func TestAuditLogEntries(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewServer(server.NewStorage()).ListAuditLogEntries(ctx, &adminpb.ListAuditLogEntriesRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ListAuditLogEntries() without an audit logger error = %v, want FailedPrecondition", err)
+	}
+
+	logger := audit.NewLogger(nil, clock.Real{})
+	interceptor := logger.UnaryServerInterceptor()
+	handler := func(context.Context, any) (any, error) { return nil, nil }
+	for _, method := range []string{"CreateSecret", "GetSecret"} {
+		info := &grpc.UnaryServerInfo{FullMethod: "/google.cloud.secretmanager.v1.SecretManagerService/" + method}
+		_, _ = interceptor(ctx, &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/db"}, info, handler)
+	}
+
+	a := NewServer(server.NewStorage(), WithAudit(logger))
+	resp, err := a.ListAuditLogEntries(ctx, &adminpb.ListAuditLogEntriesRequest{Log: audit.DataAccessLog})
+	if err != nil || len(resp.GetEntries()) != 1 {
+		t.Fatalf("ListAuditLogEntries(data_access) = %v, %v; want one entry", resp, err)
+	}
+	payload := resp.GetEntries()[0].GetFields()["protoPayload"].GetStructValue().GetFields()
+	if payload["methodName"].GetStringValue() != "google.cloud.secretmanager.v1.SecretManagerService.GetSecret" || payload["resourceName"].GetStringValue() != "projects/p/secrets/db" {
+		t.Errorf("ListAuditLogEntries(data_access) payload = %v", payload)
+	}
+	if _, err := a.ListAuditLogEntries(ctx, &adminpb.ListAuditLogEntriesRequest{Log: "system_event"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListAuditLogEntries(system_event) error = %v, want InvalidArgument", err)
+	}
+
+	cleared, err := a.ClearAuditLogEntries(ctx, &adminpb.ClearAuditLogEntriesRequest{})
+	if err != nil || cleared.GetDeletedEntries() != 2 {
+		t.Errorf("ClearAuditLogEntries() = %v, %v; want 2 deleted", cleared, err)
+	}
+}
+
+func TestTenants(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := NewServer(server.NewStorage()).ListTenants(ctx, &adminpb.ListTenantsRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ListTenants() without tenants error = %v, want FailedPrecondition", err)
+	}
+
+	tenants := server.NewTenants(clock.Real{})
+	a := NewServer(tenants.Default(), WithTenants(tenants))
+	job := metadata.NewIncomingContext(ctx, metadata.Pairs(server.TenantMetadataKey, "job-1"))
+	snapshot := &adminpb.Snapshot{Secrets: []*adminpb.SnapshotSecret{{Secret: &secretmanagerpb.Secret{Name: "projects/p/secrets/db"}}}}
+	if _, err := a.ImportSnapshot(job, &adminpb.ImportSnapshotRequest{Snapshot: snapshot}); err != nil {
+		t.Fatalf("ImportSnapshot() in job-1 error = %v", err)
+	}
+	if stats, err := a.GetStats(ctx, &adminpb.GetStatsRequest{}); err != nil || stats.GetSecrets() != 0 {
+		t.Errorf("GetStats() in the default tenant = %v, %v; want no secrets", stats, err)
+	}
+
+	resp, err := a.ListTenants(ctx, &adminpb.ListTenantsRequest{})
+	if err != nil || len(resp.GetTenants()) != 1 || resp.GetTenants()[0].GetName() != "tenants/job-1" || resp.GetTenants()[0].GetSecrets() != 1 {
+		t.Fatalf("ListTenants() = %v, %v", resp, err)
+	}
+	if _, err := a.DeleteTenant(ctx, &adminpb.DeleteTenantRequest{Name: "tenants/job-1"}); err != nil {
+		t.Fatalf("DeleteTenant() error = %v", err)
+	}
+	if _, err := a.DeleteTenant(ctx, &adminpb.DeleteTenantRequest{Name: "tenants/job-1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("DeleteTenant() again error = %v, want NotFound", err)
+	}
+	if _, err := a.DeleteTenant(ctx, &adminpb.DeleteTenantRequest{Name: "job-1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("DeleteTenant(job-1) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	tenants := server.NewTenants(clock.Real{})
+	a := NewServer(tenants.Default(), WithTenants(tenants))
+	job := metadata.NewIncomingContext(ctx, metadata.Pairs(server.TenantMetadataKey, "job-1"))
+	snapshot := &adminpb.Snapshot{Secrets: []*adminpb.SnapshotSecret{{Secret: &secretmanagerpb.Secret{Name: "projects/p/secrets/db"}}}}
+	if _, err := a.ImportSnapshot(job, &adminpb.ImportSnapshotRequest{Snapshot: snapshot}); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := a.CreateCheckpoint(job, &adminpb.CreateCheckpointRequest{})
+	if err != nil || cp.GetName() != "checkpoints/1" || cp.GetSecrets() != 1 || cp.GetCreateTime() == nil {
+		t.Fatalf("CreateCheckpoint() = %v, %v", cp, err)
+	}
+	if _, err := a.Reset(job, &adminpb.ResetRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.RestoreCheckpoint(job, &adminpb.RestoreCheckpointRequest{Name: cp.GetName()}); err != nil {
+		t.Fatalf("RestoreCheckpoint() error = %v", err)
+	}
+	if stats, err := a.GetStats(job, &adminpb.GetStatsRequest{}); err != nil || stats.GetSecrets() != 1 {
+		t.Errorf("GetStats() after RestoreCheckpoint() = %v, %v; want 1 secret", stats, err)
+	}
+
+	// Checkpoints belong to the tenant that created them
+	if resp, err := a.ListCheckpoints(ctx, &adminpb.ListCheckpointsRequest{}); err != nil || len(resp.GetCheckpoints()) != 0 {
+		t.Errorf("ListCheckpoints() in the default tenant = %v, %v; want none", resp, err)
+	}
+	if _, err := a.RestoreCheckpoint(ctx, &adminpb.RestoreCheckpointRequest{Name: cp.GetName()}); status.Code(err) != codes.NotFound {
+		t.Errorf("RestoreCheckpoint() in the default tenant error = %v, want NotFound", err)
+	}
+	if resp, err := a.ListCheckpoints(job, &adminpb.ListCheckpointsRequest{}); err != nil || len(resp.GetCheckpoints()) != 1 {
+		t.Errorf("ListCheckpoints() in job-1 = %v, %v; want 1 checkpoint", resp, err)
+	}
+
+	if _, err := a.DeleteCheckpoint(job, &adminpb.DeleteCheckpointRequest{Name: cp.GetName()}); err != nil {
+		t.Fatalf("DeleteCheckpoint() error = %v", err)
+	}
+	if _, err := a.DeleteCheckpoint(job, &adminpb.DeleteCheckpointRequest{Name: cp.GetName()}); status.Code(err) != codes.NotFound {
+		t.Errorf("DeleteCheckpoint() again error = %v, want NotFound", err)
+	}
+	if _, err := a.RestoreCheckpoint(job, &adminpb.RestoreCheckpointRequest{Name: "1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("RestoreCheckpoint(1) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestMintToken(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := NewServer(server.NewStorage()).MintToken(ctx, &adminpb.MintTokenRequest{Principal: "user:alice@example.com"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("MintToken() without tokens error = %v, want FailedPrecondition", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resolver := token.NewResolver(clock.NewFrozen(start), nil)
+	a := NewServer(server.NewStorage(), WithTokens(resolver))
+	tok, err := a.MintToken(ctx, &adminpb.MintTokenRequest{Principal: "user:alice@example.com", Lifetime: durationpb.New(time.Minute)})
+	if err != nil {
+		t.Fatalf("MintToken() error = %v", err)
+	}
+	if !tok.GetExpireTime().AsTime().Equal(start.Add(time.Minute)) {
+		t.Errorf("MintToken() expire_time = %v, want a minute after start", tok.GetExpireTime().AsTime())
+	}
+	if principal, err := resolver.Principal(tok.GetToken()); principal != "user:alice@example.com" || err != nil {
+		t.Errorf("Principal() of minted token = %q, %v", principal, err)
+	}
+	if _, err := a.MintToken(ctx, &adminpb.MintTokenRequest{Principal: "alice@example.com"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("MintToken(alice@example.com) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestClearPermissionCache(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := NewServer(server.NewStorage()).ClearPermissionCache(ctx, &adminpb.ClearPermissionCacheRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ClearPermissionCache() without a cache error = %v, want FailedPrecondition", err)
+	}
+
+	cache := authz.NewDecisionCache(clock.Real{}, time.Minute)
+	cache.Put("", "user:alice@example.com", "projects/p/secrets/db", "secretmanager.secrets.get", true)
+	cache.Put("job-42", "user:alice@example.com", "projects/p", "secretmanager.secrets.list", false)
+	resp, err := NewServer(server.NewStorage(), WithPermissionCache(cache)).ClearPermissionCache(ctx, &adminpb.ClearPermissionCacheRequest{})
+	if err != nil || resp.GetDeletedEntries() != 2 {
+		t.Errorf("ClearPermissionCache() = %v, %v; want 2 deleted", resp, err)
+	}
+	if _, ok := cache.Get("", "user:alice@example.com", "projects/p/secrets/db", "secretmanager.secrets.get"); ok {
+		t.Error("decision still cached after ClearPermissionCache()")
+	}
+
+	// A resource drops the decisions on it and under it in the selected tenant
+	a := NewServer(server.NewStorage(), WithPermissionCache(cache))
+	for _, tenant := range []string{"", "job-42"} {
+		cache.Put(tenant, "user:alice@example.com", "projects/p", "secretmanager.secrets.list", true)
+		cache.Put(tenant, "user:alice@example.com", "projects/p/secrets/db", "secretmanager.secrets.get", true)
+		cache.Put(tenant, "user:alice@example.com", "projects/p/secrets/db/versions/1", "secretmanager.versions.access", true)
+	}
+	inTenant := metadata.NewIncomingContext(ctx, metadata.Pairs(server.TenantMetadataKey, "job-42"))
+	resp, err = a.ClearPermissionCache(inTenant, &adminpb.ClearPermissionCacheRequest{Resource: "projects/p/secrets/db"})
+	if err != nil || resp.GetDeletedEntries() != 2 {
+		t.Errorf("ClearPermissionCache(projects/p/secrets/db) = %v, %v; want 2 deleted", resp, err)
+	}
+	if _, ok := cache.Get("job-42", "user:alice@example.com", "projects/p", "secretmanager.secrets.list"); !ok {
+		t.Error("decision on the parent dropped by ClearPermissionCache(projects/p/secrets/db)")
+	}
+	if _, ok := cache.Get("", "user:alice@example.com", "projects/p/secrets/db", "secretmanager.secrets.get"); !ok {
+		t.Error("decision of another tenant dropped by ClearPermissionCache(projects/p/secrets/db)")
+	}
+}
@@ -0,0 +1,2716 @@
+// Admin API of the GCP Secret Manager emulator.
+//
+// The service is not part of Google Cloud Secret Manager. It lets tests
+// written in any language reset, snapshot and inspect emulator state. It is
+// disabled unless the emulator is started with --enable-admin.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: admin/v1/admin.proto
+
+package adminpb
+
+import (
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Request for Reset.
+type ResetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Project to reset, e.g. "projects/my-project". Empty resets everything.
+	Parent        string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetRequest) Reset() {
+	*x = ResetRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetRequest) ProtoMessage() {}
+
+func (x *ResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
+func (*ResetRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResetRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+// Response for Reset.
+type ResetResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of secrets deleted.
+	DeletedSecrets int32 `protobuf:"varint,1,opt,name=deleted_secrets,json=deletedSecrets,proto3" json:"deleted_secrets,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResetResponse) Reset() {
+	*x = ResetResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetResponse) ProtoMessage() {}
+
+func (x *ResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
+func (*ResetResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ResetResponse) GetDeletedSecrets() int32 {
+	if x != nil {
+		return x.DeletedSecrets
+	}
+	return 0
+}
+
+// Request for ExportSnapshot.
+type ExportSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportSnapshotRequest) Reset() {
+	*x = ExportSnapshotRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportSnapshotRequest) ProtoMessage() {}
+
+func (x *ExportSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*ExportSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+// A copy of emulator state. Snapshots are written and read as JSON using the
+// proto3 JSON mapping, so they can be stored as files and imported by later
+// emulator releases.
+type Snapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// When the snapshot was taken.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Secrets ordered by name.
+	Secrets []*SnapshotSecret `protobuf:"bytes,2,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	// Version of the snapshot format. Importing a snapshot written in a newer
+	// format than the emulator supports fails with FAILED_PRECONDITION. Zero
+	// means the snapshot predates format versioning and is read as version 1.
+	FormatVersion int32 `protobuf:"varint,3,opt,name=format_version,json=formatVersion,proto3" json:"format_version,omitempty"`
+	// Secrets of the tenants other than the default, ordered by tenant. Only
+	// snapshot files written by --snapshot-out hold them; ExportSnapshot
+	// snapshots the tenant its call selects into secrets.
+	Tenants       []*TenantSnapshot `protobuf:"bytes,4,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_admin_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Snapshot) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Snapshot) GetSecrets() []*SnapshotSecret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *Snapshot) GetFormatVersion() int32 {
+	if x != nil {
+		return x.FormatVersion
+	}
+	return 0
+}
+
+func (x *Snapshot) GetTenants() []*TenantSnapshot {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+// The secrets of one tenant in a snapshot.
+type TenantSnapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant name.
+	Tenant string `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// Secrets ordered by name.
+	Secrets       []*SnapshotSecret `protobuf:"bytes,2,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TenantSnapshot) Reset() {
+	*x = TenantSnapshot{}
+	mi := &file_admin_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenantSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenantSnapshot) ProtoMessage() {}
+
+func (x *TenantSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenantSnapshot.ProtoReflect.Descriptor instead.
+func (*TenantSnapshot) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TenantSnapshot) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *TenantSnapshot) GetSecrets() []*SnapshotSecret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+// A secret and its versions in a snapshot.
+type SnapshotSecret struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Secret metadata, including version aliases.
+	Secret *secretmanagerpb.Secret `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// Number the next added version receives.
+	NextVersion int64 `protobuf:"varint,2,opt,name=next_version,json=nextVersion,proto3" json:"next_version,omitempty"`
+	// Versions ordered by number.
+	Versions      []*SnapshotVersion `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotSecret) Reset() {
+	*x = SnapshotSecret{}
+	mi := &file_admin_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotSecret) ProtoMessage() {}
+
+func (x *SnapshotSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotSecret.ProtoReflect.Descriptor instead.
+func (*SnapshotSecret) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SnapshotSecret) GetSecret() *secretmanagerpb.Secret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *SnapshotSecret) GetNextVersion() int64 {
+	if x != nil {
+		return x.NextVersion
+	}
+	return 0
+}
+
+func (x *SnapshotSecret) GetVersions() []*SnapshotVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// A secret version and its payload in a snapshot.
+type SnapshotVersion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Version metadata, including state and create time.
+	Version *secretmanagerpb.SecretVersion `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Payload data. Empty for destroyed versions.
+	Payload       []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotVersion) Reset() {
+	*x = SnapshotVersion{}
+	mi := &file_admin_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotVersion) ProtoMessage() {}
+
+func (x *SnapshotVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotVersion.ProtoReflect.Descriptor instead.
+func (*SnapshotVersion) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SnapshotVersion) GetVersion() *secretmanagerpb.SecretVersion {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *SnapshotVersion) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// Request for ImportSnapshot.
+type ImportSnapshotRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Snapshot to import.
+	Snapshot *Snapshot `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// Deletes all existing secrets first. Otherwise importing a secret that
+	// already exists fails with ALREADY_EXISTS and nothing is imported.
+	Replace       bool `protobuf:"varint,2,opt,name=replace,proto3" json:"replace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSnapshotRequest) Reset() {
+	*x = ImportSnapshotRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSnapshotRequest) ProtoMessage() {}
+
+func (x *ImportSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*ImportSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ImportSnapshotRequest) GetSnapshot() *Snapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *ImportSnapshotRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+// Response for ImportSnapshot.
+type ImportSnapshotResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of secrets imported.
+	ImportedSecrets int32 `protobuf:"varint,1,opt,name=imported_secrets,json=importedSecrets,proto3" json:"imported_secrets,omitempty"`
+	// Number of versions imported.
+	ImportedVersions int32 `protobuf:"varint,2,opt,name=imported_versions,json=importedVersions,proto3" json:"imported_versions,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ImportSnapshotResponse) Reset() {
+	*x = ImportSnapshotResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSnapshotResponse) ProtoMessage() {}
+
+func (x *ImportSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*ImportSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ImportSnapshotResponse) GetImportedSecrets() int32 {
+	if x != nil {
+		return x.ImportedSecrets
+	}
+	return 0
+}
+
+func (x *ImportSnapshotResponse) GetImportedVersions() int32 {
+	if x != nil {
+		return x.ImportedVersions
+	}
+	return 0
+}
+
+// Request for ListSecrets.
+type ListSecretsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Project to list, e.g. "projects/my-project". Empty lists every project.
+	Parent        string `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSecretsRequest) Reset() {
+	*x = ListSecretsRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSecretsRequest) ProtoMessage() {}
+
+func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ListSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListSecretsRequest) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+// Response for ListSecrets.
+type ListSecretsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Secrets ordered by name.
+	Secrets       []*SecretSummary `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSecretsResponse) Reset() {
+	*x = ListSecretsResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSecretsResponse) ProtoMessage() {}
+
+func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ListSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListSecretsResponse) GetSecrets() []*SecretSummary {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+// A secret and metadata about its versions.
+type SecretSummary struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Secret metadata.
+	Secret *secretmanagerpb.Secret `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// Versions ordered by number.
+	Versions      []*VersionSummary `protobuf:"bytes,2,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SecretSummary) Reset() {
+	*x = SecretSummary{}
+	mi := &file_admin_v1_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecretSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretSummary) ProtoMessage() {}
+
+func (x *SecretSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretSummary.ProtoReflect.Descriptor instead.
+func (*SecretSummary) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SecretSummary) GetSecret() *secretmanagerpb.Secret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *SecretSummary) GetVersions() []*VersionSummary {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// Metadata about a secret version and its payload.
+type VersionSummary struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Version metadata.
+	Version *secretmanagerpb.SecretVersion `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Payload size in bytes. Zero for destroyed versions.
+	PayloadSizeBytes int64 `protobuf:"varint,2,opt,name=payload_size_bytes,json=payloadSizeBytes,proto3" json:"payload_size_bytes,omitempty"`
+	// CRC32C checksum of the payload.
+	PayloadCrc32C int64 `protobuf:"varint,3,opt,name=payload_crc32c,json=payloadCrc32c,proto3" json:"payload_crc32c,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionSummary) Reset() {
+	*x = VersionSummary{}
+	mi := &file_admin_v1_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionSummary) ProtoMessage() {}
+
+func (x *VersionSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionSummary.ProtoReflect.Descriptor instead.
+func (*VersionSummary) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *VersionSummary) GetVersion() *secretmanagerpb.SecretVersion {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *VersionSummary) GetPayloadSizeBytes() int64 {
+	if x != nil {
+		return x.PayloadSizeBytes
+	}
+	return 0
+}
+
+func (x *VersionSummary) GetPayloadCrc32C() int64 {
+	if x != nil {
+		return x.PayloadCrc32C
+	}
+	return 0
+}
+
+// Request for GetStats.
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{13}
+}
+
+// Counts describing emulator state.
+type Stats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of projects with at least one secret.
+	Projects int32 `protobuf:"varint,1,opt,name=projects,proto3" json:"projects,omitempty"`
+	// Number of secrets.
+	Secrets int32 `protobuf:"varint,2,opt,name=secrets,proto3" json:"secrets,omitempty"`
+	// Number of versions by state, e.g. "ENABLED".
+	Versions map[string]int32 `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Total size of stored payloads in bytes.
+	PayloadBytes  int64 `protobuf:"varint,4,opt,name=payload_bytes,json=payloadBytes,proto3" json:"payload_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_admin_v1_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Stats) GetProjects() int32 {
+	if x != nil {
+		return x.Projects
+	}
+	return 0
+}
+
+func (x *Stats) GetSecrets() int32 {
+	if x != nil {
+		return x.Secrets
+	}
+	return 0
+}
+
+func (x *Stats) GetVersions() map[string]int32 {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *Stats) GetPayloadBytes() int64 {
+	if x != nil {
+		return x.PayloadBytes
+	}
+	return 0
+}
+
+// A fault injection rule. A rule matches Secret Manager RPCs by method,
+// resource and principal, and fires on matching calls selected by nth and
+// probability. Firing injects latency, then an error or a dropped
+// connection. Rules are evaluated in order and the first rule that fires
+// applies.
+type FaultRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resource name of the rule, e.g. "faults/1". Assigned on creation.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// RPC to match: a method name such as "AccessSecretVersion", a full
+	// method such as "/google.cloud.secretmanager.v1.SecretManagerService/
+	// AccessSecretVersion", or a glob of either. Empty matches every RPC.
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// Glob matched against the request's resource name, e.g.
+	// "projects/*/secrets/db-*". Empty matches every resource.
+	Resource string `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	// Glob matched against the caller's principal, e.g.
+	// "serviceAccount:*@ci.iam.gserviceaccount.com". Empty matches every
+	// caller, including anonymous ones.
+	Principal string `protobuf:"bytes,4,opt,name=principal,proto3" json:"principal,omitempty"`
+	// Chance, between 0 and 1, that a selected call fires. Zero means always.
+	Probability float64 `protobuf:"fixed64,5,opt,name=probability,proto3" json:"probability,omitempty"`
+	// Selects every nth matching call, e.g. 3 selects calls 3, 6, 9 and so
+	// on. Zero or one selects every call.
+	Nth int64 `protobuf:"varint,6,opt,name=nth,proto3" json:"nth,omitempty"`
+	// Stops firing after this many injections. Zero means no limit.
+	MaxInjections int64 `protobuf:"varint,7,opt,name=max_injections,json=maxInjections,proto3" json:"max_injections,omitempty"`
+	// gRPC status code to return, e.g. "UNAVAILABLE". Empty injects no error.
+	Code string `protobuf:"bytes,8,opt,name=code,proto3" json:"code,omitempty"`
+	// Error message. Defaults to a message naming the rule.
+	Message string `protobuf:"bytes,9,opt,name=message,proto3" json:"message,omitempty"`
+	// Adds a google.rpc.RetryInfo detail with this delay to the error.
+	RetryDelay *durationpb.Duration `protobuf:"bytes,10,opt,name=retry_delay,json=retryDelay,proto3" json:"retry_delay,omitempty"`
+	// Delays the call by this long before the error is returned or the call
+	// proceeds.
+	Latency *durationpb.Duration `protobuf:"bytes,11,opt,name=latency,proto3" json:"latency,omitempty"`
+	// Closes the caller's connection instead of responding. Cannot be combined
+	// with code.
+	DropConnection bool `protobuf:"varint,12,opt,name=drop_connection,json=dropConnection,proto3" json:"drop_connection,omitempty"`
+	// Output only. Number of calls the rule matched.
+	MatchedCalls int64 `protobuf:"varint,13,opt,name=matched_calls,json=matchedCalls,proto3" json:"matched_calls,omitempty"`
+	// Output only. Number of times the rule fired.
+	Injections    int64 `protobuf:"varint,14,opt,name=injections,proto3" json:"injections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FaultRule) Reset() {
+	*x = FaultRule{}
+	mi := &file_admin_v1_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaultRule) ProtoMessage() {}
+
+func (x *FaultRule) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaultRule.ProtoReflect.Descriptor instead.
+func (*FaultRule) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FaultRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FaultRule) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *FaultRule) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *FaultRule) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *FaultRule) GetProbability() float64 {
+	if x != nil {
+		return x.Probability
+	}
+	return 0
+}
+
+func (x *FaultRule) GetNth() int64 {
+	if x != nil {
+		return x.Nth
+	}
+	return 0
+}
+
+func (x *FaultRule) GetMaxInjections() int64 {
+	if x != nil {
+		return x.MaxInjections
+	}
+	return 0
+}
+
+func (x *FaultRule) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *FaultRule) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *FaultRule) GetRetryDelay() *durationpb.Duration {
+	if x != nil {
+		return x.RetryDelay
+	}
+	return nil
+}
+
+func (x *FaultRule) GetLatency() *durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *FaultRule) GetDropConnection() bool {
+	if x != nil {
+		return x.DropConnection
+	}
+	return false
+}
+
+func (x *FaultRule) GetMatchedCalls() int64 {
+	if x != nil {
+		return x.MatchedCalls
+	}
+	return 0
+}
+
+func (x *FaultRule) GetInjections() int64 {
+	if x != nil {
+		return x.Injections
+	}
+	return 0
+}
+
+// Request for ListFaultRules.
+type ListFaultRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFaultRulesRequest) Reset() {
+	*x = ListFaultRulesRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFaultRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFaultRulesRequest) ProtoMessage() {}
+
+func (x *ListFaultRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFaultRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListFaultRulesRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{16}
+}
+
+// Response for ListFaultRules.
+type ListFaultRulesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Rules in evaluation order.
+	Rules         []*FaultRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFaultRulesResponse) Reset() {
+	*x = ListFaultRulesResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFaultRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFaultRulesResponse) ProtoMessage() {}
+
+func (x *ListFaultRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFaultRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListFaultRulesResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListFaultRulesResponse) GetRules() []*FaultRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// Request for CreateFaultRule.
+type CreateFaultRuleRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Rule to add. Its name and output fields are ignored.
+	Rule          *FaultRule `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFaultRuleRequest) Reset() {
+	*x = CreateFaultRuleRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFaultRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFaultRuleRequest) ProtoMessage() {}
+
+func (x *CreateFaultRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFaultRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreateFaultRuleRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateFaultRuleRequest) GetRule() *FaultRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// Request for DeleteFaultRule.
+type DeleteFaultRuleRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the rule, e.g. "faults/1".
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFaultRuleRequest) Reset() {
+	*x = DeleteFaultRuleRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFaultRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFaultRuleRequest) ProtoMessage() {}
+
+func (x *DeleteFaultRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFaultRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFaultRuleRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteFaultRuleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Request for ClearFaultRules.
+type ClearFaultRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearFaultRulesRequest) Reset() {
+	*x = ClearFaultRulesRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearFaultRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearFaultRulesRequest) ProtoMessage() {}
+
+func (x *ClearFaultRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearFaultRulesRequest.ProtoReflect.Descriptor instead.
+func (*ClearFaultRulesRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{20}
+}
+
+// Response for ClearFaultRules.
+type ClearFaultRulesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of rules deleted.
+	DeletedRules  int32 `protobuf:"varint,1,opt,name=deleted_rules,json=deletedRules,proto3" json:"deleted_rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearFaultRulesResponse) Reset() {
+	*x = ClearFaultRulesResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearFaultRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearFaultRulesResponse) ProtoMessage() {}
+
+func (x *ClearFaultRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearFaultRulesResponse.ProtoReflect.Descriptor instead.
+func (*ClearFaultRulesResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ClearFaultRulesResponse) GetDeletedRules() int32 {
+	if x != nil {
+		return x.DeletedRules
+	}
+	return 0
+}
+
+// The emulator's clock, which create times, secret expiration and quotas
+// follow.
+type Clock struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Current emulator time.
+	Time *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Whether the clock is frozen.
+	Frozen        bool `protobuf:"varint,2,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Clock) Reset() {
+	*x = Clock{}
+	mi := &file_admin_v1_admin_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Clock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Clock) ProtoMessage() {}
+
+func (x *Clock) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Clock.ProtoReflect.Descriptor instead.
+func (*Clock) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Clock) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Clock) GetFrozen() bool {
+	if x != nil {
+		return x.Frozen
+	}
+	return false
+}
+
+// Request for GetClock.
+type GetClockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClockRequest) Reset() {
+	*x = GetClockRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClockRequest) ProtoMessage() {}
+
+func (x *GetClockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClockRequest.ProtoReflect.Descriptor instead.
+func (*GetClockRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{23}
+}
+
+// Request for FreezeClock.
+type FreezeClockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Time to freeze the clock at, forward or back. Defaults to the current
+	// emulator time.
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeClockRequest) Reset() {
+	*x = FreezeClockRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeClockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeClockRequest) ProtoMessage() {}
+
+func (x *FreezeClockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeClockRequest.ProtoReflect.Descriptor instead.
+func (*FreezeClockRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *FreezeClockRequest) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// Request for AdvanceClock.
+type AdvanceClockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// How far to move the clock forward. Must not be negative.
+	Duration      *durationpb.Duration `protobuf:"bytes,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdvanceClockRequest) Reset() {
+	*x = AdvanceClockRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdvanceClockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdvanceClockRequest) ProtoMessage() {}
+
+func (x *AdvanceClockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdvanceClockRequest.ProtoReflect.Descriptor instead.
+func (*AdvanceClockRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AdvanceClockRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+// Request for ResumeClock.
+type ResumeClockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeClockRequest) Reset() {
+	*x = ResumeClockRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeClockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeClockRequest) ProtoMessage() {}
+
+func (x *ResumeClockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeClockRequest.ProtoReflect.Descriptor instead.
+func (*ResumeClockRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{26}
+}
+
+// An isolated namespace of secrets, selected by the x-emulator-tenant
+// metadata key, the X-Emulator-Tenant header or a /tenants/{tenant} REST URL
+// prefix.
+type Tenant struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resource name, e.g. "tenants/job-42".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Number of secrets in the tenant.
+	Secrets       int32 `protobuf:"varint,2,opt,name=secrets,proto3" json:"secrets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_admin_v1_admin_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Tenant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tenant) GetSecrets() int32 {
+	if x != nil {
+		return x.Secrets
+	}
+	return 0
+}
+
+// Request for ListTenants.
+type ListTenantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsRequest) Reset() {
+	*x = ListTenantsRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsRequest) ProtoMessage() {}
+
+func (x *ListTenantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{28}
+}
+
+// Response for ListTenants.
+type ListTenantsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenants sorted by name.
+	Tenants       []*Tenant `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsResponse) Reset() {
+	*x = ListTenantsResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsResponse) ProtoMessage() {}
+
+func (x *ListTenantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsResponse.ProtoReflect.Descriptor instead.
+func (*ListTenantsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListTenantsResponse) GetTenants() []*Tenant {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+// Request for DeleteTenant.
+type DeleteTenantRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the tenant, e.g. "tenants/job-42".
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTenantRequest) Reset() {
+	*x = DeleteTenantRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTenantRequest) ProtoMessage() {}
+
+func (x *DeleteTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTenantRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTenantRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteTenantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Request for ListAuditLogEntries. Empty fields match any entry.
+type ListAuditLogEntriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Audit log: "activity" for Admin Activity or "data_access" for Data
+	// Access.
+	Log string `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
+	// RPC name, e.g. "AccessSecretVersion", or full method name, e.g.
+	// "google.cloud.secretmanager.v1.SecretManagerService.AccessSecretVersion".
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// IAM principal of the caller, e.g. "user:alice@example.com".
+	Principal string `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	// Resource name the call acted on, e.g. "projects/p/secrets/s".
+	Resource      string `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogEntriesRequest) Reset() {
+	*x = ListAuditLogEntriesRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogEntriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogEntriesRequest) ProtoMessage() {}
+
+func (x *ListAuditLogEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogEntriesRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditLogEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListAuditLogEntriesRequest) GetLog() string {
+	if x != nil {
+		return x.Log
+	}
+	return ""
+}
+
+func (x *ListAuditLogEntriesRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ListAuditLogEntriesRequest) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *ListAuditLogEntriesRequest) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+// Response for ListAuditLogEntries.
+type ListAuditLogEntriesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Matching entries in the LogEntry JSON shape of Cloud Logging, with a
+	// google.cloud.audit.AuditLog protoPayload.
+	Entries       []*structpb.Struct `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogEntriesResponse) Reset() {
+	*x = ListAuditLogEntriesResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogEntriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogEntriesResponse) ProtoMessage() {}
+
+func (x *ListAuditLogEntriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogEntriesResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditLogEntriesResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListAuditLogEntriesResponse) GetEntries() []*structpb.Struct {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// Request for ClearAuditLogEntries.
+type ClearAuditLogEntriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearAuditLogEntriesRequest) Reset() {
+	*x = ClearAuditLogEntriesRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearAuditLogEntriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearAuditLogEntriesRequest) ProtoMessage() {}
+
+func (x *ClearAuditLogEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearAuditLogEntriesRequest.ProtoReflect.Descriptor instead.
+func (*ClearAuditLogEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{33}
+}
+
+// Response for ClearAuditLogEntries.
+type ClearAuditLogEntriesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of entries dropped.
+	DeletedEntries int32 `protobuf:"varint,1,opt,name=deleted_entries,json=deletedEntries,proto3" json:"deleted_entries,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClearAuditLogEntriesResponse) Reset() {
+	*x = ClearAuditLogEntriesResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearAuditLogEntriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearAuditLogEntriesResponse) ProtoMessage() {}
+
+func (x *ClearAuditLogEntriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearAuditLogEntriesResponse.ProtoReflect.Descriptor instead.
+func (*ClearAuditLogEntriesResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ClearAuditLogEntriesResponse) GetDeletedEntries() int32 {
+	if x != nil {
+		return x.DeletedEntries
+	}
+	return 0
+}
+
+// A saved state of a tenant's secrets.
+type Checkpoint struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resource name, e.g. "checkpoints/1".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// When the checkpoint was created, by the emulator's clock.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Number of secrets saved.
+	Secrets       int32 `protobuf:"varint,3,opt,name=secrets,proto3" json:"secrets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Checkpoint) Reset() {
+	*x = Checkpoint{}
+	mi := &file_admin_v1_admin_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Checkpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Checkpoint) ProtoMessage() {}
+
+func (x *Checkpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Checkpoint.ProtoReflect.Descriptor instead.
+func (*Checkpoint) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Checkpoint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Checkpoint) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Checkpoint) GetSecrets() int32 {
+	if x != nil {
+		return x.Secrets
+	}
+	return 0
+}
+
+// Request for CreateCheckpoint.
+type CreateCheckpointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCheckpointRequest) Reset() {
+	*x = CreateCheckpointRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCheckpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCheckpointRequest) ProtoMessage() {}
+
+func (x *CreateCheckpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCheckpointRequest.ProtoReflect.Descriptor instead.
+func (*CreateCheckpointRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{36}
+}
+
+// Request for ListCheckpoints.
+type ListCheckpointsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCheckpointsRequest) Reset() {
+	*x = ListCheckpointsRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCheckpointsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCheckpointsRequest) ProtoMessage() {}
+
+func (x *ListCheckpointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCheckpointsRequest.ProtoReflect.Descriptor instead.
+func (*ListCheckpointsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{37}
+}
+
+// Response for ListCheckpoints.
+type ListCheckpointsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Checkpoints, oldest first.
+	Checkpoints   []*Checkpoint `protobuf:"bytes,1,rep,name=checkpoints,proto3" json:"checkpoints,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCheckpointsResponse) Reset() {
+	*x = ListCheckpointsResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCheckpointsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCheckpointsResponse) ProtoMessage() {}
+
+func (x *ListCheckpointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCheckpointsResponse.ProtoReflect.Descriptor instead.
+func (*ListCheckpointsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListCheckpointsResponse) GetCheckpoints() []*Checkpoint {
+	if x != nil {
+		return x.Checkpoints
+	}
+	return nil
+}
+
+// Request for RestoreCheckpoint.
+type RestoreCheckpointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the checkpoint, e.g. "checkpoints/1".
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreCheckpointRequest) Reset() {
+	*x = RestoreCheckpointRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreCheckpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreCheckpointRequest) ProtoMessage() {}
+
+func (x *RestoreCheckpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreCheckpointRequest.ProtoReflect.Descriptor instead.
+func (*RestoreCheckpointRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RestoreCheckpointRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Request for DeleteCheckpoint.
+type DeleteCheckpointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the checkpoint, e.g. "checkpoints/1".
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCheckpointRequest) Reset() {
+	*x = DeleteCheckpointRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCheckpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCheckpointRequest) ProtoMessage() {}
+
+func (x *DeleteCheckpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCheckpointRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCheckpointRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeleteCheckpointRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Request for MintToken.
+type MintTokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// IAM principal, e.g. "user:alice@example.com" or
+	// "serviceAccount:ci@my-project.iam.gserviceaccount.com".
+	Principal string `protobuf:"bytes,1,opt,name=principal,proto3" json:"principal,omitempty"`
+	// How long the token is valid for. Defaults to an hour.
+	Lifetime      *durationpb.Duration `protobuf:"bytes,2,opt,name=lifetime,proto3" json:"lifetime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MintTokenRequest) Reset() {
+	*x = MintTokenRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MintTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MintTokenRequest) ProtoMessage() {}
+
+func (x *MintTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MintTokenRequest.ProtoReflect.Descriptor instead.
+func (*MintTokenRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *MintTokenRequest) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *MintTokenRequest) GetLifetime() *durationpb.Duration {
+	if x != nil {
+		return x.Lifetime
+	}
+	return nil
+}
+
+// A bearer token minted by the emulator.
+type Token struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unsigned JWT whose email or sub claim names the principal.
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Principal the token authenticates as.
+	Principal string `protobuf:"bytes,2,opt,name=principal,proto3" json:"principal,omitempty"`
+	// When the token expires, by the emulator's clock.
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_admin_v1_admin_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *Token) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Token) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *Token) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+// Request for ClearPermissionCache.
+type ClearPermissionCacheRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resource whose policy changed, e.g. "projects/my-project" or
+	// "projects/my-project/secrets/db". Decisions on it and the resources under
+	// it are dropped in the selected tenant. Empty drops every decision of
+	// every tenant.
+	Resource      string `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearPermissionCacheRequest) Reset() {
+	*x = ClearPermissionCacheRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearPermissionCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearPermissionCacheRequest) ProtoMessage() {}
+
+func (x *ClearPermissionCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearPermissionCacheRequest.ProtoReflect.Descriptor instead.
+func (*ClearPermissionCacheRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ClearPermissionCacheRequest) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+// Response for ClearPermissionCache.
+type ClearPermissionCacheResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of cached decisions dropped.
+	DeletedEntries int32 `protobuf:"varint,1,opt,name=deleted_entries,json=deletedEntries,proto3" json:"deleted_entries,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClearPermissionCacheResponse) Reset() {
+	*x = ClearPermissionCacheResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearPermissionCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearPermissionCacheResponse) ProtoMessage() {}
+
+func (x *ClearPermissionCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearPermissionCacheResponse.ProtoReflect.Descriptor instead.
+func (*ClearPermissionCacheResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ClearPermissionCacheResponse) GetDeletedEntries() int32 {
+	if x != nil {
+		return x.DeletedEntries
+	}
+	return 0
+}
+
+var File_admin_v1_admin_proto protoreflect.FileDescriptor
+
+const file_admin_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x14admin/v1/admin.proto\x12\x1femulator.secretmanager.admin.v1\x1a\x1cgoogle/api/annotations.proto\x1a-google/cloud/secretmanager/v1/resources.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"&\n" +
+	"\fResetRequest\x12\x16\n" +
+	"\x06parent\x18\x01 \x01(\tR\x06parent\"8\n" +
+	"\rResetResponse\x12'\n" +
+	"\x0fdeleted_secrets\x18\x01 \x01(\x05R\x0edeletedSecrets\"\x17\n" +
+	"\x15ExportSnapshotRequest\"\x84\x02\n" +
+	"\bSnapshot\x12;\n" +
+	"\vcreate_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12I\n" +
+	"\asecrets\x18\x02 \x03(\v2/.emulator.secretmanager.admin.v1.SnapshotSecretR\asecrets\x12%\n" +
+	"\x0eformat_version\x18\x03 \x01(\x05R\rformatVersion\x12I\n" +
+	"\atenants\x18\x04 \x03(\v2/.emulator.secretmanager.admin.v1.TenantSnapshotR\atenants\"s\n" +
+	"\x0eTenantSnapshot\x12\x16\n" +
+	"\x06tenant\x18\x01 \x01(\tR\x06tenant\x12I\n" +
+	"\asecrets\x18\x02 \x03(\v2/.emulator.secretmanager.admin.v1.SnapshotSecretR\asecrets\"\xc0\x01\n" +
+	"\x0eSnapshotSecret\x12=\n" +
+	"\x06secret\x18\x01 \x01(\v2%.google.cloud.secretmanager.v1.SecretR\x06secret\x12!\n" +
+	"\fnext_version\x18\x02 \x01(\x03R\vnextVersion\x12L\n" +
+	"\bversions\x18\x03 \x03(\v20.emulator.secretmanager.admin.v1.SnapshotVersionR\bversions\"s\n" +
+	"\x0fSnapshotVersion\x12F\n" +
+	"\aversion\x18\x01 \x01(\v2,.google.cloud.secretmanager.v1.SecretVersionR\aversion\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\fR\apayload\"x\n" +
+	"\x15ImportSnapshotRequest\x12E\n" +
+	"\bsnapshot\x18\x01 \x01(\v2).emulator.secretmanager.admin.v1.SnapshotR\bsnapshot\x12\x18\n" +
+	"\areplace\x18\x02 \x01(\bR\areplace\"p\n" +
+	"\x16ImportSnapshotResponse\x12)\n" +
+	"\x10imported_secrets\x18\x01 \x01(\x05R\x0fimportedSecrets\x12+\n" +
+	"\x11imported_versions\x18\x02 \x01(\x05R\x10importedVersions\",\n" +
+	"\x12ListSecretsRequest\x12\x16\n" +
+	"\x06parent\x18\x01 \x01(\tR\x06parent\"_\n" +
+	"\x13ListSecretsResponse\x12H\n" +
+	"\asecrets\x18\x01 \x03(\v2..emulator.secretmanager.admin.v1.SecretSummaryR\asecrets\"\x9b\x01\n" +
+	"\rSecretSummary\x12=\n" +
+	"\x06secret\x18\x01 \x01(\v2%.google.cloud.secretmanager.v1.SecretR\x06secret\x12K\n" +
+	"\bversions\x18\x02 \x03(\v2/.emulator.secretmanager.admin.v1.VersionSummaryR\bversions\"\xad\x01\n" +
+	"\x0eVersionSummary\x12F\n" +
+	"\aversion\x18\x01 \x01(\v2,.google.cloud.secretmanager.v1.SecretVersionR\aversion\x12,\n" +
+	"\x12payload_size_bytes\x18\x02 \x01(\x03R\x10payloadSizeBytes\x12%\n" +
+	"\x0epayload_crc32c\x18\x03 \x01(\x03R\rpayloadCrc32c\"\x11\n" +
+	"\x0fGetStatsRequest\"\xf1\x01\n" +
+	"\x05Stats\x12\x1a\n" +
+	"\bprojects\x18\x01 \x01(\x05R\bprojects\x12\x18\n" +
+	"\asecrets\x18\x02 \x01(\x05R\asecrets\x12P\n" +
+	"\bversions\x18\x03 \x03(\v24.emulator.secretmanager.admin.v1.Stats.VersionsEntryR\bversions\x12#\n" +
+	"\rpayload_bytes\x18\x04 \x01(\x03R\fpayloadBytes\x1a;\n" +
+	"\rVersionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xd9\x03\n" +
+	"\tFaultRule\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x1a\n" +
+	"\bresource\x18\x03 \x01(\tR\bresource\x12\x1c\n" +
+	"\tprincipal\x18\x04 \x01(\tR\tprincipal\x12 \n" +
+	"\vprobability\x18\x05 \x01(\x01R\vprobability\x12\x10\n" +
+	"\x03nth\x18\x06 \x01(\x03R\x03nth\x12%\n" +
+	"\x0emax_injections\x18\a \x01(\x03R\rmaxInjections\x12\x12\n" +
+	"\x04code\x18\b \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\t \x01(\tR\amessage\x12:\n" +
+	"\vretry_delay\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryDelay\x123\n" +
+	"\alatency\x18\v \x01(\v2\x19.google.protobuf.DurationR\alatency\x12'\n" +
+	"\x0fdrop_connection\x18\f \x01(\bR\x0edropConnection\x12#\n" +
+	"\rmatched_calls\x18\r \x01(\x03R\fmatchedCalls\x12\x1e\n" +
+	"\n" +
+	"injections\x18\x0e \x01(\x03R\n" +
+	"injections\"\x17\n" +
+	"\x15ListFaultRulesRequest\"Z\n" +
+	"\x16ListFaultRulesResponse\x12@\n" +
+	"\x05rules\x18\x01 \x03(\v2*.emulator.secretmanager.admin.v1.FaultRuleR\x05rules\"X\n" +
+	"\x16CreateFaultRuleRequest\x12>\n" +
+	"\x04rule\x18\x01 \x01(\v2*.emulator.secretmanager.admin.v1.FaultRuleR\x04rule\",\n" +
+	"\x16DeleteFaultRuleRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x18\n" +
+	"\x16ClearFaultRulesRequest\">\n" +
+	"\x17ClearFaultRulesResponse\x12#\n" +
+	"\rdeleted_rules\x18\x01 \x01(\x05R\fdeletedRules\"O\n" +
+	"\x05Clock\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x16\n" +
+	"\x06frozen\x18\x02 \x01(\bR\x06frozen\"\x11\n" +
+	"\x0fGetClockRequest\"D\n" +
+	"\x12FreezeClockRequest\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"L\n" +
+	"\x13AdvanceClockRequest\x125\n" +
+	"\bduration\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\bduration\"\x14\n" +
+	"\x12ResumeClockRequest\"6\n" +
+	"\x06Tenant\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\asecrets\x18\x02 \x01(\x05R\asecrets\"\x14\n" +
+	"\x12ListTenantsRequest\"X\n" +
+	"\x13ListTenantsResponse\x12A\n" +
+	"\atenants\x18\x01 \x03(\v2'.emulator.secretmanager.admin.v1.TenantR\atenants\")\n" +
+	"\x13DeleteTenantRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x80\x01\n" +
+	"\x1aListAuditLogEntriesRequest\x12\x10\n" +
+	"\x03log\x18\x01 \x01(\tR\x03log\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x1c\n" +
+	"\tprincipal\x18\x03 \x01(\tR\tprincipal\x12\x1a\n" +
+	"\bresource\x18\x04 \x01(\tR\bresource\"P\n" +
+	"\x1bListAuditLogEntriesResponse\x121\n" +
+	"\aentries\x18\x01 \x03(\v2\x17.google.protobuf.StructR\aentries\"\x1d\n" +
+	"\x1bClearAuditLogEntriesRequest\"G\n" +
+	"\x1cClearAuditLogEntriesResponse\x12'\n" +
+	"\x0fdeleted_entries\x18\x01 \x01(\x05R\x0edeletedEntries\"w\n" +
+	"\n" +
+	"Checkpoint\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12;\n" +
+	"\vcreate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12\x18\n" +
+	"\asecrets\x18\x03 \x01(\x05R\asecrets\"\x19\n" +
+	"\x17CreateCheckpointRequest\"\x18\n" +
+	"\x16ListCheckpointsRequest\"h\n" +
+	"\x17ListCheckpointsResponse\x12M\n" +
+	"\vcheckpoints\x18\x01 \x03(\v2+.emulator.secretmanager.admin.v1.CheckpointR\vcheckpoints\".\n" +
+	"\x18RestoreCheckpointRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"-\n" +
+	"\x17DeleteCheckpointRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"g\n" +
+	"\x10MintTokenRequest\x12\x1c\n" +
+	"\tprincipal\x18\x01 \x01(\tR\tprincipal\x125\n" +
+	"\blifetime\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\blifetime\"x\n" +
+	"\x05Token\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1c\n" +
+	"\tprincipal\x18\x02 \x01(\tR\tprincipal\x12;\n" +
+	"\vexpire_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"expireTime\"9\n" +
+	"\x1bClearPermissionCacheRequest\x12\x1a\n" +
+	"\bresource\x18\x01 \x01(\tR\bresource\"G\n" +
+	"\x1cClearPermissionCacheResponse\x12'\n" +
+	"\x0fdeleted_entries\x18\x01 \x01(\x05R\x0edeletedEntries2\xb6\x1c\n" +
+	"\fAdminService\x12\xac\x01\n" +
+	"\x05Reset\x12-.emulator.secretmanager.admin.v1.ResetRequest\x1a..emulator.secretmanager.admin.v1.ResetResponse\"D\x82\xd3\xe4\x93\x02>:\x01*Z(:\x01*\"#/admin/v1/{parent=projects/*}:reset\"\x0f/admin/v1:reset\x12\x8f\x01\n" +
+	"\x0eExportSnapshot\x126.emulator.secretmanager.admin.v1.ExportSnapshotRequest\x1a).emulator.secretmanager.admin.v1.Snapshot\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/admin/v1/snapshot\x12\xae\x01\n" +
+	"\x0eImportSnapshot\x126.emulator.secretmanager.admin.v1.ImportSnapshotRequest\x1a7.emulator.secretmanager.admin.v1.ImportSnapshotResponse\"+\x82\xd3\xe4\x93\x02%:\bsnapshot\"\x19/admin/v1/snapshot:import\x12\xbc\x01\n" +
+	"\vListSecrets\x123.emulator.secretmanager.admin.v1.ListSecretsRequest\x1a4.emulator.secretmanager.admin.v1.ListSecretsResponse\"B\x82\xd3\xe4\x93\x02<Z'\x12%/admin/v1/{parent=projects/*}/secrets\x12\x11/admin/v1/secrets\x12}\n" +
+	"\bGetStats\x120.emulator.secretmanager.admin.v1.GetStatsRequest\x1a&.emulator.secretmanager.admin.v1.Stats\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/admin/v1/stats\x12\x9b\x01\n" +
+	"\x0eListFaultRules\x126.emulator.secretmanager.admin.v1.ListFaultRulesRequest\x1a7.emulator.secretmanager.admin.v1.ListFaultRulesResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/admin/v1/faults\x12\x96\x01\n" +
+	"\x0fCreateFaultRule\x127.emulator.secretmanager.admin.v1.CreateFaultRuleRequest\x1a*.emulator.secretmanager.admin.v1.FaultRule\"\x1e\x82\xd3\xe4\x93\x02\x18:\x04rule\"\x10/admin/v1/faults\x12\x85\x01\n" +
+	"\x0fDeleteFaultRule\x127.emulator.secretmanager.admin.v1.DeleteFaultRuleRequest\x1a\x16.google.protobuf.Empty\"!\x82\xd3\xe4\x93\x02\x1b*\x19/admin/v1/{name=faults/*}\x12\xa7\x01\n" +
+	"\x0fClearFaultRules\x127.emulator.secretmanager.admin.v1.ClearFaultRulesRequest\x1a8.emulator.secretmanager.admin.v1.ClearFaultRulesResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/admin/v1/faults:clear\x12}\n" +
+	"\bGetClock\x120.emulator.secretmanager.admin.v1.GetClockRequest\x1a&.emulator.secretmanager.admin.v1.Clock\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/admin/v1/clock\x12\x8d\x01\n" +
+	"\vFreezeClock\x123.emulator.secretmanager.admin.v1.FreezeClockRequest\x1a&.emulator.secretmanager.admin.v1.Clock\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/admin/v1/clock:freeze\x12\x90\x01\n" +
+	"\fAdvanceClock\x124.emulator.secretmanager.admin.v1.AdvanceClockRequest\x1a&.emulator.secretmanager.admin.v1.Clock\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/admin/v1/clock:advance\x12\x8d\x01\n" +
+	"\vResumeClock\x123.emulator.secretmanager.admin.v1.ResumeClockRequest\x1a&.emulator.secretmanager.admin.v1.Clock\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/admin/v1/clock:resume\x12\x93\x01\n" +
+	"\vListTenants\x123.emulator.secretmanager.admin.v1.ListTenantsRequest\x1a4.emulator.secretmanager.admin.v1.ListTenantsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/admin/v1/tenants\x12\x80\x01\n" +
+	"\fDeleteTenant\x124.emulator.secretmanager.admin.v1.DeleteTenantRequest\x1a\x16.google.protobuf.Empty\"\"\x82\xd3\xe4\x93\x02\x1c*\x1a/admin/v1/{name=tenants/*}\x12\xb3\x01\n" +
+	"\x13ListAuditLogEntries\x12;.emulator.secretmanager.admin.v1.ListAuditLogEntriesRequest\x1a<.emulator.secretmanager.admin.v1.ListAuditLogEntriesResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/admin/v1/auditLogEntries\x12\xbf\x01\n" +
+	"\x14ClearAuditLogEntries\x12<.emulator.secretmanager.admin.v1.ClearAuditLogEntriesRequest\x1a=.emulator.secretmanager.admin.v1.ClearAuditLogEntriesResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/admin/v1/auditLogEntries:clear\x12\x9b\x01\n" +
+	"\x10CreateCheckpoint\x128.emulator.secretmanager.admin.v1.CreateCheckpointRequest\x1a+.emulator.secretmanager.admin.v1.Checkpoint\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/admin/v1/checkpoints\x12\xa3\x01\n" +
+	"\x0fListCheckpoints\x127.emulator.secretmanager.admin.v1.ListCheckpointsRequest\x1a8.emulator.secretmanager.admin.v1.ListCheckpointsResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/admin/v1/checkpoints\x12\xae\x01\n" +
+	"\x11RestoreCheckpoint\x129.emulator.secretmanager.admin.v1.RestoreCheckpointRequest\x1a+.emulator.secretmanager.admin.v1.Checkpoint\"1\x82\xd3\xe4\x93\x02+:\x01*\"&/admin/v1/{name=checkpoints/*}:restore\x12\x8c\x01\n" +
+	"\x10DeleteCheckpoint\x128.emulator.secretmanager.admin.v1.DeleteCheckpointRequest\x1a\x16.google.protobuf.Empty\"&\x82\xd3\xe4\x93\x02 *\x1e/admin/v1/{name=checkpoints/*}\x12\x83\x01\n" +
+	"\tMintToken\x121.emulator.secretmanager.admin.v1.MintTokenRequest\x1a&.emulator.secretmanager.admin.v1.Token\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/admin/v1/tokens\x12\xbf\x01\n" +
+	"\x14ClearPermissionCache\x12<.emulator.secretmanager.admin.v1.ClearPermissionCacheRequest\x1a=.emulator.secretmanager.admin.v1.ClearPermissionCacheResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/admin/v1/permissionCache:clearBYZWgithub.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb;adminpbb\x06proto3"
+
+var (
+	file_admin_v1_admin_proto_rawDescOnce sync.Once
+	file_admin_v1_admin_proto_rawDescData []byte
+)
+
+func file_admin_v1_admin_proto_rawDescGZIP() []byte {
+	file_admin_v1_admin_proto_rawDescOnce.Do(func() {
+		file_admin_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_v1_admin_proto_rawDesc), len(file_admin_v1_admin_proto_rawDesc)))
+	})
+	return file_admin_v1_admin_proto_rawDescData
+}
+
+var file_admin_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
+var file_admin_v1_admin_proto_goTypes = []any{
+	(*ResetRequest)(nil),                  // 0: emulator.secretmanager.admin.v1.ResetRequest
+	(*ResetResponse)(nil),                 // 1: emulator.secretmanager.admin.v1.ResetResponse
+	(*ExportSnapshotRequest)(nil),         // 2: emulator.secretmanager.admin.v1.ExportSnapshotRequest
+	(*Snapshot)(nil),                      // 3: emulator.secretmanager.admin.v1.Snapshot
+	(*TenantSnapshot)(nil),                // 4: emulator.secretmanager.admin.v1.TenantSnapshot
+	(*SnapshotSecret)(nil),                // 5: emulator.secretmanager.admin.v1.SnapshotSecret
+	(*SnapshotVersion)(nil),               // 6: emulator.secretmanager.admin.v1.SnapshotVersion
+	(*ImportSnapshotRequest)(nil),         // 7: emulator.secretmanager.admin.v1.ImportSnapshotRequest
+	(*ImportSnapshotResponse)(nil),        // 8: emulator.secretmanager.admin.v1.ImportSnapshotResponse
+	(*ListSecretsRequest)(nil),            // 9: emulator.secretmanager.admin.v1.ListSecretsRequest
+	(*ListSecretsResponse)(nil),           // 10: emulator.secretmanager.admin.v1.ListSecretsResponse
+	(*SecretSummary)(nil),                 // 11: emulator.secretmanager.admin.v1.SecretSummary
+	(*VersionSummary)(nil),                // 12: emulator.secretmanager.admin.v1.VersionSummary
+	(*GetStatsRequest)(nil),               // 13: emulator.secretmanager.admin.v1.GetStatsRequest
+	(*Stats)(nil),                         // 14: emulator.secretmanager.admin.v1.Stats
+	(*FaultRule)(nil),                     // 15: emulator.secretmanager.admin.v1.FaultRule
+	(*ListFaultRulesRequest)(nil),         // 16: emulator.secretmanager.admin.v1.ListFaultRulesRequest
+	(*ListFaultRulesResponse)(nil),        // 17: emulator.secretmanager.admin.v1.ListFaultRulesResponse
+	(*CreateFaultRuleRequest)(nil),        // 18: emulator.secretmanager.admin.v1.CreateFaultRuleRequest
+	(*DeleteFaultRuleRequest)(nil),        // 19: emulator.secretmanager.admin.v1.DeleteFaultRuleRequest
+	(*ClearFaultRulesRequest)(nil),        // 20: emulator.secretmanager.admin.v1.ClearFaultRulesRequest
+	(*ClearFaultRulesResponse)(nil),       // 21: emulator.secretmanager.admin.v1.ClearFaultRulesResponse
+	(*Clock)(nil),                         // 22: emulator.secretmanager.admin.v1.Clock
+	(*GetClockRequest)(nil),               // 23: emulator.secretmanager.admin.v1.GetClockRequest
+	(*FreezeClockRequest)(nil),            // 24: emulator.secretmanager.admin.v1.FreezeClockRequest
+	(*AdvanceClockRequest)(nil),           // 25: emulator.secretmanager.admin.v1.AdvanceClockRequest
+	(*ResumeClockRequest)(nil),            // 26: emulator.secretmanager.admin.v1.ResumeClockRequest
+	(*Tenant)(nil),                        // 27: emulator.secretmanager.admin.v1.Tenant
+	(*ListTenantsRequest)(nil),            // 28: emulator.secretmanager.admin.v1.ListTenantsRequest
+	(*ListTenantsResponse)(nil),           // 29: emulator.secretmanager.admin.v1.ListTenantsResponse
+	(*DeleteTenantRequest)(nil),           // 30: emulator.secretmanager.admin.v1.DeleteTenantRequest
+	(*ListAuditLogEntriesRequest)(nil),    // 31: emulator.secretmanager.admin.v1.ListAuditLogEntriesRequest
+	(*ListAuditLogEntriesResponse)(nil),   // 32: emulator.secretmanager.admin.v1.ListAuditLogEntriesResponse
+	(*ClearAuditLogEntriesRequest)(nil),   // 33: emulator.secretmanager.admin.v1.ClearAuditLogEntriesRequest
+	(*ClearAuditLogEntriesResponse)(nil),  // 34: emulator.secretmanager.admin.v1.ClearAuditLogEntriesResponse
+	(*Checkpoint)(nil),                    // 35: emulator.secretmanager.admin.v1.Checkpoint
+	(*CreateCheckpointRequest)(nil),       // 36: emulator.secretmanager.admin.v1.CreateCheckpointRequest
+	(*ListCheckpointsRequest)(nil),        // 37: emulator.secretmanager.admin.v1.ListCheckpointsRequest
+	(*ListCheckpointsResponse)(nil),       // 38: emulator.secretmanager.admin.v1.ListCheckpointsResponse
+	(*RestoreCheckpointRequest)(nil),      // 39: emulator.secretmanager.admin.v1.RestoreCheckpointRequest
+	(*DeleteCheckpointRequest)(nil),       // 40: emulator.secretmanager.admin.v1.DeleteCheckpointRequest
+	(*MintTokenRequest)(nil),              // 41: emulator.secretmanager.admin.v1.MintTokenRequest
+	(*Token)(nil),                         // 42: emulator.secretmanager.admin.v1.Token
+	(*ClearPermissionCacheRequest)(nil),   // 43: emulator.secretmanager.admin.v1.ClearPermissionCacheRequest
+	(*ClearPermissionCacheResponse)(nil),  // 44: emulator.secretmanager.admin.v1.ClearPermissionCacheResponse
+	nil,                                   // 45: emulator.secretmanager.admin.v1.Stats.VersionsEntry
+	(*timestamppb.Timestamp)(nil),         // 46: google.protobuf.Timestamp
+	(*secretmanagerpb.Secret)(nil),        // 47: google.cloud.secretmanager.v1.Secret
+	(*secretmanagerpb.SecretVersion)(nil), // 48: google.cloud.secretmanager.v1.SecretVersion
+	(*durationpb.Duration)(nil),           // 49: google.protobuf.Duration
+	(*structpb.Struct)(nil),               // 50: google.protobuf.Struct
+	(*emptypb.Empty)(nil),                 // 51: google.protobuf.Empty
+}
+var file_admin_v1_admin_proto_depIdxs = []int32{
+	46, // 0: emulator.secretmanager.admin.v1.Snapshot.create_time:type_name -> google.protobuf.Timestamp
+	5,  // 1: emulator.secretmanager.admin.v1.Snapshot.secrets:type_name -> emulator.secretmanager.admin.v1.SnapshotSecret
+	4,  // 2: emulator.secretmanager.admin.v1.Snapshot.tenants:type_name -> emulator.secretmanager.admin.v1.TenantSnapshot
+	5,  // 3: emulator.secretmanager.admin.v1.TenantSnapshot.secrets:type_name -> emulator.secretmanager.admin.v1.SnapshotSecret
+	47, // 4: emulator.secretmanager.admin.v1.SnapshotSecret.secret:type_name -> google.cloud.secretmanager.v1.Secret
+	6,  // 5: emulator.secretmanager.admin.v1.SnapshotSecret.versions:type_name -> emulator.secretmanager.admin.v1.SnapshotVersion
+	48, // 6: emulator.secretmanager.admin.v1.SnapshotVersion.version:type_name -> google.cloud.secretmanager.v1.SecretVersion
+	3,  // 7: emulator.secretmanager.admin.v1.ImportSnapshotRequest.snapshot:type_name -> emulator.secretmanager.admin.v1.Snapshot
+	11, // 8: emulator.secretmanager.admin.v1.ListSecretsResponse.secrets:type_name -> emulator.secretmanager.admin.v1.SecretSummary
+	47, // 9: emulator.secretmanager.admin.v1.SecretSummary.secret:type_name -> google.cloud.secretmanager.v1.Secret
+	12, // 10: emulator.secretmanager.admin.v1.SecretSummary.versions:type_name -> emulator.secretmanager.admin.v1.VersionSummary
+	48, // 11: emulator.secretmanager.admin.v1.VersionSummary.version:type_name -> google.cloud.secretmanager.v1.SecretVersion
+	45, // 12: emulator.secretmanager.admin.v1.Stats.versions:type_name -> emulator.secretmanager.admin.v1.Stats.VersionsEntry
+	49, // 13: emulator.secretmanager.admin.v1.FaultRule.retry_delay:type_name -> google.protobuf.Duration
+	49, // 14: emulator.secretmanager.admin.v1.FaultRule.latency:type_name -> google.protobuf.Duration
+	15, // 15: emulator.secretmanager.admin.v1.ListFaultRulesResponse.rules:type_name -> emulator.secretmanager.admin.v1.FaultRule
+	15, // 16: emulator.secretmanager.admin.v1.CreateFaultRuleRequest.rule:type_name -> emulator.secretmanager.admin.v1.FaultRule
+	46, // 17: emulator.secretmanager.admin.v1.Clock.time:type_name -> google.protobuf.Timestamp
+	46, // 18: emulator.secretmanager.admin.v1.FreezeClockRequest.time:type_name -> google.protobuf.Timestamp
+	49, // 19: emulator.secretmanager.admin.v1.AdvanceClockRequest.duration:type_name -> google.protobuf.Duration
+	27, // 20: emulator.secretmanager.admin.v1.ListTenantsResponse.tenants:type_name -> emulator.secretmanager.admin.v1.Tenant
+	50, // 21: emulator.secretmanager.admin.v1.ListAuditLogEntriesResponse.entries:type_name -> google.protobuf.Struct
+	46, // 22: emulator.secretmanager.admin.v1.Checkpoint.create_time:type_name -> google.protobuf.Timestamp
+	35, // 23: emulator.secretmanager.admin.v1.ListCheckpointsResponse.checkpoints:type_name -> emulator.secretmanager.admin.v1.Checkpoint
+	49, // 24: emulator.secretmanager.admin.v1.MintTokenRequest.lifetime:type_name -> google.protobuf.Duration
+	46, // 25: emulator.secretmanager.admin.v1.Token.expire_time:type_name -> google.protobuf.Timestamp
+	0,  // 26: emulator.secretmanager.admin.v1.AdminService.Reset:input_type -> emulator.secretmanager.admin.v1.ResetRequest
+	2,  // 27: emulator.secretmanager.admin.v1.AdminService.ExportSnapshot:input_type -> emulator.secretmanager.admin.v1.ExportSnapshotRequest
+	7,  // 28: emulator.secretmanager.admin.v1.AdminService.ImportSnapshot:input_type -> emulator.secretmanager.admin.v1.ImportSnapshotRequest
+	9,  // 29: emulator.secretmanager.admin.v1.AdminService.ListSecrets:input_type -> emulator.secretmanager.admin.v1.ListSecretsRequest
+	13, // 30: emulator.secretmanager.admin.v1.AdminService.GetStats:input_type -> emulator.secretmanager.admin.v1.GetStatsRequest
+	16, // 31: emulator.secretmanager.admin.v1.AdminService.ListFaultRules:input_type -> emulator.secretmanager.admin.v1.ListFaultRulesRequest
+	18, // 32: emulator.secretmanager.admin.v1.AdminService.CreateFaultRule:input_type -> emulator.secretmanager.admin.v1.CreateFaultRuleRequest
+	19, // 33: emulator.secretmanager.admin.v1.AdminService.DeleteFaultRule:input_type -> emulator.secretmanager.admin.v1.DeleteFaultRuleRequest
+	20, // 34: emulator.secretmanager.admin.v1.AdminService.ClearFaultRules:input_type -> emulator.secretmanager.admin.v1.ClearFaultRulesRequest
+	23, // 35: emulator.secretmanager.admin.v1.AdminService.GetClock:input_type -> emulator.secretmanager.admin.v1.GetClockRequest
+	24, // 36: emulator.secretmanager.admin.v1.AdminService.FreezeClock:input_type -> emulator.secretmanager.admin.v1.FreezeClockRequest
+	25, // 37: emulator.secretmanager.admin.v1.AdminService.AdvanceClock:input_type -> emulator.secretmanager.admin.v1.AdvanceClockRequest
+	26, // 38: emulator.secretmanager.admin.v1.AdminService.ResumeClock:input_type -> emulator.secretmanager.admin.v1.ResumeClockRequest
+	28, // 39: emulator.secretmanager.admin.v1.AdminService.ListTenants:input_type -> emulator.secretmanager.admin.v1.ListTenantsRequest
+	30, // 40: emulator.secretmanager.admin.v1.AdminService.DeleteTenant:input_type -> emulator.secretmanager.admin.v1.DeleteTenantRequest
+	31, // 41: emulator.secretmanager.admin.v1.AdminService.ListAuditLogEntries:input_type -> emulator.secretmanager.admin.v1.ListAuditLogEntriesRequest
+	33, // 42: emulator.secretmanager.admin.v1.AdminService.ClearAuditLogEntries:input_type -> emulator.secretmanager.admin.v1.ClearAuditLogEntriesRequest
+	36, // 43: emulator.secretmanager.admin.v1.AdminService.CreateCheckpoint:input_type -> emulator.secretmanager.admin.v1.CreateCheckpointRequest
+	37, // 44: emulator.secretmanager.admin.v1.AdminService.ListCheckpoints:input_type -> emulator.secretmanager.admin.v1.ListCheckpointsRequest
+	39, // 45: emulator.secretmanager.admin.v1.AdminService.RestoreCheckpoint:input_type -> emulator.secretmanager.admin.v1.RestoreCheckpointRequest
+	40, // 46: emulator.secretmanager.admin.v1.AdminService.DeleteCheckpoint:input_type -> emulator.secretmanager.admin.v1.DeleteCheckpointRequest
+	41, // 47: emulator.secretmanager.admin.v1.AdminService.MintToken:input_type -> emulator.secretmanager.admin.v1.MintTokenRequest
+	43, // 48: emulator.secretmanager.admin.v1.AdminService.ClearPermissionCache:input_type -> emulator.secretmanager.admin.v1.ClearPermissionCacheRequest
+	1,  // 49: emulator.secretmanager.admin.v1.AdminService.Reset:output_type -> emulator.secretmanager.admin.v1.ResetResponse
+	3,  // 50: emulator.secretmanager.admin.v1.AdminService.ExportSnapshot:output_type -> emulator.secretmanager.admin.v1.Snapshot
+	8,  // 51: emulator.secretmanager.admin.v1.AdminService.ImportSnapshot:output_type -> emulator.secretmanager.admin.v1.ImportSnapshotResponse
+	10, // 52: emulator.secretmanager.admin.v1.AdminService.ListSecrets:output_type -> emulator.secretmanager.admin.v1.ListSecretsResponse
+	14, // 53: emulator.secretmanager.admin.v1.AdminService.GetStats:output_type -> emulator.secretmanager.admin.v1.Stats
+	17, // 54: emulator.secretmanager.admin.v1.AdminService.ListFaultRules:output_type -> emulator.secretmanager.admin.v1.ListFaultRulesResponse
+	15, // 55: emulator.secretmanager.admin.v1.AdminService.CreateFaultRule:output_type -> emulator.secretmanager.admin.v1.FaultRule
+	51, // 56: emulator.secretmanager.admin.v1.AdminService.DeleteFaultRule:output_type -> google.protobuf.Empty
+	21, // 57: emulator.secretmanager.admin.v1.AdminService.ClearFaultRules:output_type -> emulator.secretmanager.admin.v1.ClearFaultRulesResponse
+	22, // 58: emulator.secretmanager.admin.v1.AdminService.GetClock:output_type -> emulator.secretmanager.admin.v1.Clock
+	22, // 59: emulator.secretmanager.admin.v1.AdminService.FreezeClock:output_type -> emulator.secretmanager.admin.v1.Clock
+	22, // 60: emulator.secretmanager.admin.v1.AdminService.AdvanceClock:output_type -> emulator.secretmanager.admin.v1.Clock
+	22, // 61: emulator.secretmanager.admin.v1.AdminService.ResumeClock:output_type -> emulator.secretmanager.admin.v1.Clock
+	29, // 62: emulator.secretmanager.admin.v1.AdminService.ListTenants:output_type -> emulator.secretmanager.admin.v1.ListTenantsResponse
+	51, // 63: emulator.secretmanager.admin.v1.AdminService.DeleteTenant:output_type -> google.protobuf.Empty
+	32, // 64: emulator.secretmanager.admin.v1.AdminService.ListAuditLogEntries:output_type -> emulator.secretmanager.admin.v1.ListAuditLogEntriesResponse
+	34, // 65: emulator.secretmanager.admin.v1.AdminService.ClearAuditLogEntries:output_type -> emulator.secretmanager.admin.v1.ClearAuditLogEntriesResponse
+	35, // 66: emulator.secretmanager.admin.v1.AdminService.CreateCheckpoint:output_type -> emulator.secretmanager.admin.v1.Checkpoint
+	38, // 67: emulator.secretmanager.admin.v1.AdminService.ListCheckpoints:output_type -> emulator.secretmanager.admin.v1.ListCheckpointsResponse
+	35, // 68: emulator.secretmanager.admin.v1.AdminService.RestoreCheckpoint:output_type -> emulator.secretmanager.admin.v1.Checkpoint
+	51, // 69: emulator.secretmanager.admin.v1.AdminService.DeleteCheckpoint:output_type -> google.protobuf.Empty
+	42, // 70: emulator.secretmanager.admin.v1.AdminService.MintToken:output_type -> emulator.secretmanager.admin.v1.Token
+	44, // 71: emulator.secretmanager.admin.v1.AdminService.ClearPermissionCache:output_type -> emulator.secretmanager.admin.v1.ClearPermissionCacheResponse
+	49, // [49:72] is the sub-list for method output_type
+	26, // [26:49] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_admin_v1_admin_proto_init() }
+func file_admin_v1_admin_proto_init() {
+	if File_admin_v1_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_v1_admin_proto_rawDesc), len(file_admin_v1_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   46,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_v1_admin_proto_goTypes,
+		DependencyIndexes: file_admin_v1_admin_proto_depIdxs,
+		MessageInfos:      file_admin_v1_admin_proto_msgTypes,
+	}.Build()
+	File_admin_v1_admin_proto = out.File
+	file_admin_v1_admin_proto_goTypes = nil
+	file_admin_v1_admin_proto_depIdxs = nil
+}
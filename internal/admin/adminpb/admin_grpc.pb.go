@@ -0,0 +1,1032 @@
+// Admin API of the GCP Secret Manager emulator.
+//
+// The service is not part of Google Cloud Secret Manager. It lets tests
+// written in any language reset, snapshot and inspect emulator state. It is
+// disabled unless the emulator is started with --enable-admin.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: admin/v1/admin.proto
+
+package adminpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_Reset_FullMethodName                = "/emulator.secretmanager.admin.v1.AdminService/Reset"
+	AdminService_ExportSnapshot_FullMethodName       = "/emulator.secretmanager.admin.v1.AdminService/ExportSnapshot"
+	AdminService_ImportSnapshot_FullMethodName       = "/emulator.secretmanager.admin.v1.AdminService/ImportSnapshot"
+	AdminService_ListSecrets_FullMethodName          = "/emulator.secretmanager.admin.v1.AdminService/ListSecrets"
+	AdminService_GetStats_FullMethodName             = "/emulator.secretmanager.admin.v1.AdminService/GetStats"
+	AdminService_ListFaultRules_FullMethodName       = "/emulator.secretmanager.admin.v1.AdminService/ListFaultRules"
+	AdminService_CreateFaultRule_FullMethodName      = "/emulator.secretmanager.admin.v1.AdminService/CreateFaultRule"
+	AdminService_DeleteFaultRule_FullMethodName      = "/emulator.secretmanager.admin.v1.AdminService/DeleteFaultRule"
+	AdminService_ClearFaultRules_FullMethodName      = "/emulator.secretmanager.admin.v1.AdminService/ClearFaultRules"
+	AdminService_GetClock_FullMethodName             = "/emulator.secretmanager.admin.v1.AdminService/GetClock"
+	AdminService_FreezeClock_FullMethodName          = "/emulator.secretmanager.admin.v1.AdminService/FreezeClock"
+	AdminService_AdvanceClock_FullMethodName         = "/emulator.secretmanager.admin.v1.AdminService/AdvanceClock"
+	AdminService_ResumeClock_FullMethodName          = "/emulator.secretmanager.admin.v1.AdminService/ResumeClock"
+	AdminService_ListTenants_FullMethodName          = "/emulator.secretmanager.admin.v1.AdminService/ListTenants"
+	AdminService_DeleteTenant_FullMethodName         = "/emulator.secretmanager.admin.v1.AdminService/DeleteTenant"
+	AdminService_ListAuditLogEntries_FullMethodName  = "/emulator.secretmanager.admin.v1.AdminService/ListAuditLogEntries"
+	AdminService_ClearAuditLogEntries_FullMethodName = "/emulator.secretmanager.admin.v1.AdminService/ClearAuditLogEntries"
+	AdminService_CreateCheckpoint_FullMethodName     = "/emulator.secretmanager.admin.v1.AdminService/CreateCheckpoint"
+	AdminService_ListCheckpoints_FullMethodName      = "/emulator.secretmanager.admin.v1.AdminService/ListCheckpoints"
+	AdminService_RestoreCheckpoint_FullMethodName    = "/emulator.secretmanager.admin.v1.AdminService/RestoreCheckpoint"
+	AdminService_DeleteCheckpoint_FullMethodName     = "/emulator.secretmanager.admin.v1.AdminService/DeleteCheckpoint"
+	AdminService_MintToken_FullMethodName            = "/emulator.secretmanager.admin.v1.AdminService/MintToken"
+	AdminService_ClearPermissionCache_FullMethodName = "/emulator.secretmanager.admin.v1.AdminService/ClearPermissionCache"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService manages the state of the emulator.
+type AdminServiceClient interface {
+	// Deletes every secret, or every secret of one project.
+	Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error)
+	// Exports every secret and version, including payloads.
+	ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error)
+	// Imports secrets and versions from a snapshot.
+	ImportSnapshot(ctx context.Context, in *ImportSnapshotRequest, opts ...grpc.CallOption) (*ImportSnapshotResponse, error)
+	// Lists every secret with its versions and payload metadata. Payloads are
+	// not returned.
+	ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error)
+	// Returns counts of projects, secrets and versions.
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error)
+	// Lists fault injection rules in evaluation order, with how often each
+	// matched and fired.
+	ListFaultRules(ctx context.Context, in *ListFaultRulesRequest, opts ...grpc.CallOption) (*ListFaultRulesResponse, error)
+	// Adds a fault injection rule after the existing rules.
+	CreateFaultRule(ctx context.Context, in *CreateFaultRuleRequest, opts ...grpc.CallOption) (*FaultRule, error)
+	// Deletes a fault injection rule.
+	DeleteFaultRule(ctx context.Context, in *DeleteFaultRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Deletes every fault injection rule.
+	ClearFaultRules(ctx context.Context, in *ClearFaultRulesRequest, opts ...grpc.CallOption) (*ClearFaultRulesResponse, error)
+	// Returns the emulator's clock.
+	GetClock(ctx context.Context, in *GetClockRequest, opts ...grpc.CallOption) (*Clock, error)
+	// Stops the clock, optionally at a given time.
+	FreezeClock(ctx context.Context, in *FreezeClockRequest, opts ...grpc.CallOption) (*Clock, error)
+	// Moves the clock forward, firing timers such as secret expirations that
+	// fall due.
+	AdvanceClock(ctx context.Context, in *AdvanceClockRequest, opts ...grpc.CallOption) (*Clock, error)
+	// Lets a frozen clock run again from the time it shows.
+	ResumeClock(ctx context.Context, in *ResumeClockRequest, opts ...grpc.CallOption) (*Clock, error)
+	// Lists the tenants other than the default one. Tenants are created by the
+	// first call that selects them.
+	ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error)
+	// Deletes a tenant and all its secrets.
+	DeleteTenant(ctx context.Context, in *DeleteTenantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Lists recent audit log entries, oldest first.
+	ListAuditLogEntries(ctx context.Context, in *ListAuditLogEntriesRequest, opts ...grpc.CallOption) (*ListAuditLogEntriesResponse, error)
+	// Drops the audit log entries kept for ListAuditLogEntries. Entries
+	// already written to the audit log file are kept.
+	ClearAuditLogEntries(ctx context.Context, in *ClearAuditLogEntriesRequest, opts ...grpc.CallOption) (*ClearAuditLogEntriesResponse, error)
+	// Saves the state of the caller's tenant and returns a checkpoint to
+	// restore it from. Checkpoints share unchanged state, so they are cheap.
+	CreateCheckpoint(ctx context.Context, in *CreateCheckpointRequest, opts ...grpc.CallOption) (*Checkpoint, error)
+	// Lists the checkpoints of the caller's tenant, oldest first.
+	ListCheckpoints(ctx context.Context, in *ListCheckpointsRequest, opts ...grpc.CallOption) (*ListCheckpointsResponse, error)
+	// Resets the caller's tenant to a checkpoint. The checkpoint is kept, so
+	// it can be restored again.
+	RestoreCheckpoint(ctx context.Context, in *RestoreCheckpointRequest, opts ...grpc.CallOption) (*Checkpoint, error)
+	// Deletes a checkpoint.
+	DeleteCheckpoint(ctx context.Context, in *DeleteCheckpointRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Mints an unsigned JWT for a principal. Sent as an "Authorization: Bearer"
+	// token, it makes the principal the caller of IAM checks.
+	MintToken(ctx context.Context, in *MintTokenRequest, opts ...grpc.CallOption) (*Token, error)
+	// Drops cached IAM decisions, e.g. after changing policies in the IAM
+	// emulator: every decision, or those on one resource of the selected tenant.
+	ClearPermissionCache(ctx context.Context, in *ClearPermissionCacheRequest, opts ...grpc.CallOption) (*ClearPermissionCacheResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetResponse)
+	err := c.cc.Invoke(ctx, AdminService_Reset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Snapshot)
+	err := c.cc.Invoke(ctx, AdminService_ExportSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ImportSnapshot(ctx context.Context, in *ImportSnapshotRequest, opts ...grpc.CallOption) (*ImportSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportSnapshotResponse)
+	err := c.cc.Invoke(ctx, AdminService_ImportSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSecretsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, AdminService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListFaultRules(ctx context.Context, in *ListFaultRulesRequest, opts ...grpc.CallOption) (*ListFaultRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFaultRulesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListFaultRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateFaultRule(ctx context.Context, in *CreateFaultRuleRequest, opts ...grpc.CallOption) (*FaultRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FaultRule)
+	err := c.cc.Invoke(ctx, AdminService_CreateFaultRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteFaultRule(ctx context.Context, in *DeleteFaultRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AdminService_DeleteFaultRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ClearFaultRules(ctx context.Context, in *ClearFaultRulesRequest, opts ...grpc.CallOption) (*ClearFaultRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearFaultRulesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ClearFaultRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetClock(ctx context.Context, in *GetClockRequest, opts ...grpc.CallOption) (*Clock, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Clock)
+	err := c.cc.Invoke(ctx, AdminService_GetClock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) FreezeClock(ctx context.Context, in *FreezeClockRequest, opts ...grpc.CallOption) (*Clock, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Clock)
+	err := c.cc.Invoke(ctx, AdminService_FreezeClock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) AdvanceClock(ctx context.Context, in *AdvanceClockRequest, opts ...grpc.CallOption) (*Clock, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Clock)
+	err := c.cc.Invoke(ctx, AdminService_AdvanceClock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ResumeClock(ctx context.Context, in *ResumeClockRequest, opts ...grpc.CallOption) (*Clock, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Clock)
+	err := c.cc.Invoke(ctx, AdminService_ResumeClock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTenantsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListTenants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteTenant(ctx context.Context, in *DeleteTenantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AdminService_DeleteTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAuditLogEntries(ctx context.Context, in *ListAuditLogEntriesRequest, opts ...grpc.CallOption) (*ListAuditLogEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditLogEntriesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAuditLogEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ClearAuditLogEntries(ctx context.Context, in *ClearAuditLogEntriesRequest, opts ...grpc.CallOption) (*ClearAuditLogEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearAuditLogEntriesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ClearAuditLogEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateCheckpoint(ctx context.Context, in *CreateCheckpointRequest, opts ...grpc.CallOption) (*Checkpoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Checkpoint)
+	err := c.cc.Invoke(ctx, AdminService_CreateCheckpoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListCheckpoints(ctx context.Context, in *ListCheckpointsRequest, opts ...grpc.CallOption) (*ListCheckpointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCheckpointsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListCheckpoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RestoreCheckpoint(ctx context.Context, in *RestoreCheckpointRequest, opts ...grpc.CallOption) (*Checkpoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Checkpoint)
+	err := c.cc.Invoke(ctx, AdminService_RestoreCheckpoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteCheckpoint(ctx context.Context, in *DeleteCheckpointRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AdminService_DeleteCheckpoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) MintToken(ctx context.Context, in *MintTokenRequest, opts ...grpc.CallOption) (*Token, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Token)
+	err := c.cc.Invoke(ctx, AdminService_MintToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ClearPermissionCache(ctx context.Context, in *ClearPermissionCacheRequest, opts ...grpc.CallOption) (*ClearPermissionCacheResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearPermissionCacheResponse)
+	err := c.cc.Invoke(ctx, AdminService_ClearPermissionCache_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService manages the state of the emulator.
+type AdminServiceServer interface {
+	// Deletes every secret, or every secret of one project.
+	Reset(context.Context, *ResetRequest) (*ResetResponse, error)
+	// Exports every secret and version, including payloads.
+	ExportSnapshot(context.Context, *ExportSnapshotRequest) (*Snapshot, error)
+	// Imports secrets and versions from a snapshot.
+	ImportSnapshot(context.Context, *ImportSnapshotRequest) (*ImportSnapshotResponse, error)
+	// Lists every secret with its versions and payload metadata. Payloads are
+	// not returned.
+	ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error)
+	// Returns counts of projects, secrets and versions.
+	GetStats(context.Context, *GetStatsRequest) (*Stats, error)
+	// Lists fault injection rules in evaluation order, with how often each
+	// matched and fired.
+	ListFaultRules(context.Context, *ListFaultRulesRequest) (*ListFaultRulesResponse, error)
+	// Adds a fault injection rule after the existing rules.
+	CreateFaultRule(context.Context, *CreateFaultRuleRequest) (*FaultRule, error)
+	// Deletes a fault injection rule.
+	DeleteFaultRule(context.Context, *DeleteFaultRuleRequest) (*emptypb.Empty, error)
+	// Deletes every fault injection rule.
+	ClearFaultRules(context.Context, *ClearFaultRulesRequest) (*ClearFaultRulesResponse, error)
+	// Returns the emulator's clock.
+	GetClock(context.Context, *GetClockRequest) (*Clock, error)
+	// Stops the clock, optionally at a given time.
+	FreezeClock(context.Context, *FreezeClockRequest) (*Clock, error)
+	// Moves the clock forward, firing timers such as secret expirations that
+	// fall due.
+	AdvanceClock(context.Context, *AdvanceClockRequest) (*Clock, error)
+	// Lets a frozen clock run again from the time it shows.
+	ResumeClock(context.Context, *ResumeClockRequest) (*Clock, error)
+	// Lists the tenants other than the default one. Tenants are created by the
+	// first call that selects them.
+	ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error)
+	// Deletes a tenant and all its secrets.
+	DeleteTenant(context.Context, *DeleteTenantRequest) (*emptypb.Empty, error)
+	// Lists recent audit log entries, oldest first.
+	ListAuditLogEntries(context.Context, *ListAuditLogEntriesRequest) (*ListAuditLogEntriesResponse, error)
+	// Drops the audit log entries kept for ListAuditLogEntries. Entries
+	// already written to the audit log file are kept.
+	ClearAuditLogEntries(context.Context, *ClearAuditLogEntriesRequest) (*ClearAuditLogEntriesResponse, error)
+	// Saves the state of the caller's tenant and returns a checkpoint to
+	// restore it from. Checkpoints share unchanged state, so they are cheap.
+	CreateCheckpoint(context.Context, *CreateCheckpointRequest) (*Checkpoint, error)
+	// Lists the checkpoints of the caller's tenant, oldest first.
+	ListCheckpoints(context.Context, *ListCheckpointsRequest) (*ListCheckpointsResponse, error)
+	// Resets the caller's tenant to a checkpoint. The checkpoint is kept, so
+	// it can be restored again.
+	RestoreCheckpoint(context.Context, *RestoreCheckpointRequest) (*Checkpoint, error)
+	// Deletes a checkpoint.
+	DeleteCheckpoint(context.Context, *DeleteCheckpointRequest) (*emptypb.Empty, error)
+	// Mints an unsigned JWT for a principal. Sent as an "Authorization: Bearer"
+	// token, it makes the principal the caller of IAM checks.
+	MintToken(context.Context, *MintTokenRequest) (*Token, error)
+	// Drops cached IAM decisions, e.g. after changing policies in the IAM
+	// emulator: every decision, or those on one resource of the selected tenant.
+	ClearPermissionCache(context.Context, *ClearPermissionCacheRequest) (*ClearPermissionCacheResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) Reset(context.Context, *ResetRequest) (*ResetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reset not implemented")
+}
+func (UnimplementedAdminServiceServer) ExportSnapshot(context.Context, *ExportSnapshotRequest) (*Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSnapshot not implemented")
+}
+func (UnimplementedAdminServiceServer) ImportSnapshot(context.Context, *ImportSnapshotRequest) (*ImportSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportSnapshot not implemented")
+}
+func (UnimplementedAdminServiceServer) ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecrets not implemented")
+}
+func (UnimplementedAdminServiceServer) GetStats(context.Context, *GetStatsRequest) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedAdminServiceServer) ListFaultRules(context.Context, *ListFaultRulesRequest) (*ListFaultRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFaultRules not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateFaultRule(context.Context, *CreateFaultRuleRequest) (*FaultRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFaultRule not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteFaultRule(context.Context, *DeleteFaultRuleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFaultRule not implemented")
+}
+func (UnimplementedAdminServiceServer) ClearFaultRules(context.Context, *ClearFaultRulesRequest) (*ClearFaultRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearFaultRules not implemented")
+}
+func (UnimplementedAdminServiceServer) GetClock(context.Context, *GetClockRequest) (*Clock, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClock not implemented")
+}
+func (UnimplementedAdminServiceServer) FreezeClock(context.Context, *FreezeClockRequest) (*Clock, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeClock not implemented")
+}
+func (UnimplementedAdminServiceServer) AdvanceClock(context.Context, *AdvanceClockRequest) (*Clock, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdvanceClock not implemented")
+}
+func (UnimplementedAdminServiceServer) ResumeClock(context.Context, *ResumeClockRequest) (*Clock, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeClock not implemented")
+}
+func (UnimplementedAdminServiceServer) ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenants not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteTenant(context.Context, *DeleteTenantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTenant not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAuditLogEntries(context.Context, *ListAuditLogEntriesRequest) (*ListAuditLogEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLogEntries not implemented")
+}
+func (UnimplementedAdminServiceServer) ClearAuditLogEntries(context.Context, *ClearAuditLogEntriesRequest) (*ClearAuditLogEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearAuditLogEntries not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateCheckpoint(context.Context, *CreateCheckpointRequest) (*Checkpoint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCheckpoint not implemented")
+}
+func (UnimplementedAdminServiceServer) ListCheckpoints(context.Context, *ListCheckpointsRequest) (*ListCheckpointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCheckpoints not implemented")
+}
+func (UnimplementedAdminServiceServer) RestoreCheckpoint(context.Context, *RestoreCheckpointRequest) (*Checkpoint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCheckpoint not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteCheckpoint(context.Context, *DeleteCheckpointRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCheckpoint not implemented")
+}
+func (UnimplementedAdminServiceServer) MintToken(context.Context, *MintTokenRequest) (*Token, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MintToken not implemented")
+}
+func (UnimplementedAdminServiceServer) ClearPermissionCache(context.Context, *ClearPermissionCacheRequest) (*ClearPermissionCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearPermissionCache not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Reset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Reset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Reset(ctx, req.(*ResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExportSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExportSnapshot(ctx, req.(*ExportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ImportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ImportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ImportSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ImportSnapshot(ctx, req.(*ImportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSecrets(ctx, req.(*ListSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListFaultRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFaultRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListFaultRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListFaultRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListFaultRules(ctx, req.(*ListFaultRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateFaultRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFaultRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateFaultRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateFaultRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateFaultRule(ctx, req.(*CreateFaultRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteFaultRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFaultRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteFaultRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteFaultRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteFaultRule(ctx, req.(*DeleteFaultRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ClearFaultRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearFaultRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ClearFaultRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ClearFaultRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ClearFaultRules(ctx, req.(*ClearFaultRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetClock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetClock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetClock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetClock(ctx, req.(*GetClockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_FreezeClock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeClockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).FreezeClock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_FreezeClock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).FreezeClock(ctx, req.(*FreezeClockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_AdvanceClock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdvanceClockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).AdvanceClock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_AdvanceClock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).AdvanceClock(ctx, req.(*AdvanceClockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ResumeClock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeClockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ResumeClock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ResumeClock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ResumeClock(ctx, req.(*ResumeClockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListTenants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListTenants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListTenants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListTenants(ctx, req.(*ListTenantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteTenant(ctx, req.(*DeleteTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAuditLogEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAuditLogEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAuditLogEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAuditLogEntries(ctx, req.(*ListAuditLogEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ClearAuditLogEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearAuditLogEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ClearAuditLogEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ClearAuditLogEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ClearAuditLogEntries(ctx, req.(*ClearAuditLogEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateCheckpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateCheckpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateCheckpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateCheckpoint(ctx, req.(*CreateCheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListCheckpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCheckpointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListCheckpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListCheckpoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListCheckpoints(ctx, req.(*ListCheckpointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RestoreCheckpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreCheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RestoreCheckpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RestoreCheckpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RestoreCheckpoint(ctx, req.(*RestoreCheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteCheckpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteCheckpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteCheckpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteCheckpoint(ctx, req.(*DeleteCheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_MintToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MintTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).MintToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_MintToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).MintToken(ctx, req.(*MintTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ClearPermissionCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearPermissionCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ClearPermissionCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ClearPermissionCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ClearPermissionCache(ctx, req.(*ClearPermissionCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "emulator.secretmanager.admin.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Reset",
+			Handler:    _AdminService_Reset_Handler,
+		},
+		{
+			MethodName: "ExportSnapshot",
+			Handler:    _AdminService_ExportSnapshot_Handler,
+		},
+		{
+			MethodName: "ImportSnapshot",
+			Handler:    _AdminService_ImportSnapshot_Handler,
+		},
+		{
+			MethodName: "ListSecrets",
+			Handler:    _AdminService_ListSecrets_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _AdminService_GetStats_Handler,
+		},
+		{
+			MethodName: "ListFaultRules",
+			Handler:    _AdminService_ListFaultRules_Handler,
+		},
+		{
+			MethodName: "CreateFaultRule",
+			Handler:    _AdminService_CreateFaultRule_Handler,
+		},
+		{
+			MethodName: "DeleteFaultRule",
+			Handler:    _AdminService_DeleteFaultRule_Handler,
+		},
+		{
+			MethodName: "ClearFaultRules",
+			Handler:    _AdminService_ClearFaultRules_Handler,
+		},
+		{
+			MethodName: "GetClock",
+			Handler:    _AdminService_GetClock_Handler,
+		},
+		{
+			MethodName: "FreezeClock",
+			Handler:    _AdminService_FreezeClock_Handler,
+		},
+		{
+			MethodName: "AdvanceClock",
+			Handler:    _AdminService_AdvanceClock_Handler,
+		},
+		{
+			MethodName: "ResumeClock",
+			Handler:    _AdminService_ResumeClock_Handler,
+		},
+		{
+			MethodName: "ListTenants",
+			Handler:    _AdminService_ListTenants_Handler,
+		},
+		{
+			MethodName: "DeleteTenant",
+			Handler:    _AdminService_DeleteTenant_Handler,
+		},
+		{
+			MethodName: "ListAuditLogEntries",
+			Handler:    _AdminService_ListAuditLogEntries_Handler,
+		},
+		{
+			MethodName: "ClearAuditLogEntries",
+			Handler:    _AdminService_ClearAuditLogEntries_Handler,
+		},
+		{
+			MethodName: "CreateCheckpoint",
+			Handler:    _AdminService_CreateCheckpoint_Handler,
+		},
+		{
+			MethodName: "ListCheckpoints",
+			Handler:    _AdminService_ListCheckpoints_Handler,
+		},
+		{
+			MethodName: "RestoreCheckpoint",
+			Handler:    _AdminService_RestoreCheckpoint_Handler,
+		},
+		{
+			MethodName: "DeleteCheckpoint",
+			Handler:    _AdminService_DeleteCheckpoint_Handler,
+		},
+		{
+			MethodName: "MintToken",
+			Handler:    _AdminService_MintToken_Handler,
+		},
+		{
+			MethodName: "ClearPermissionCache",
+			Handler:    _AdminService_ClearPermissionCache_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin/v1/admin.proto",
+}
@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/audit"
+)
+
+// ListAuditLogEntries lists the audit log entries kept in memory that match
+// the request.
+func (a *Server) ListAuditLogEntries(_ context.Context, req *adminpb.ListAuditLogEntriesRequest) (*adminpb.ListAuditLogEntriesResponse, error) {
+	if err := a.checkAudit(); err != nil {
+		return nil, err
+	}
+	switch req.GetLog() {
+	case "", audit.ActivityLog, audit.DataAccessLog:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid log %q: want %q or %q", req.GetLog(), audit.ActivityLog, audit.DataAccessLog)
+	}
+
+	resp := &adminpb.ListAuditLogEntriesResponse{}
+	for _, e := range a.audit.Entries(audit.Filter{
+		Log:       req.GetLog(),
+		Method:    req.GetMethod(),
+		Principal: req.GetPrincipal(),
+		Resource:  req.GetResource(),
+	}) {
+		entry, err := auditEntryProto(e)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to encode audit log entry: %v", err)
+		}
+		resp.Entries = append(resp.Entries, entry)
+	}
+	return resp, nil
+}
+
+// ClearAuditLogEntries drops the audit log entries kept in memory.
+func (a *Server) ClearAuditLogEntries(_ context.Context, _ *adminpb.ClearAuditLogEntriesRequest) (*adminpb.ClearAuditLogEntriesResponse, error) {
+	if err := a.checkAudit(); err != nil {
+		return nil, err
+	}
+	return &adminpb.ClearAuditLogEntriesResponse{DeletedEntries: int32(a.audit.Clear())}, nil
+}
+
+// checkAudit fails if the server was created without an audit logger.
+func (a *Server) checkAudit() error {
+	if a.audit == nil {
+		return status.Error(codes.FailedPrecondition, "Audit logging is not enabled")
+	}
+	return nil
+}
+
+// auditEntryProto returns an entry as the JSON object it is logged as.
+func auditEntryProto(e audit.Entry) (*structpb.Struct, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	s := &structpb.Struct{}
+	if err := protojson.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// checkpointPrefix starts the resource name of every checkpoint.
+const checkpointPrefix = "checkpoints/"
+
+// CreateCheckpoint saves the state of the caller's tenant.
+func (a *Server) CreateCheckpoint(ctx context.Context, _ *adminpb.CreateCheckpointRequest) (*adminpb.Checkpoint, error) {
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return findCheckpoint(storage, storage.Checkpoint())
+}
+
+// ListCheckpoints lists the checkpoints of the caller's tenant.
+func (a *Server) ListCheckpoints(ctx context.Context, _ *adminpb.ListCheckpointsRequest) (*adminpb.ListCheckpointsResponse, error) {
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &adminpb.ListCheckpointsResponse{}
+	for _, cp := range storage.Checkpoints() {
+		resp.Checkpoints = append(resp.Checkpoints, checkpointProto(cp))
+	}
+	return resp, nil
+}
+
+// RestoreCheckpoint resets the caller's tenant to a checkpoint.
+func (a *Server) RestoreCheckpoint(ctx context.Context, req *adminpb.RestoreCheckpointRequest) (*adminpb.Checkpoint, error) {
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := checkpointID(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.Restore(id); err != nil {
+		return nil, err
+	}
+	return findCheckpoint(storage, id)
+}
+
+// DeleteCheckpoint deletes a checkpoint of the caller's tenant.
+func (a *Server) DeleteCheckpoint(ctx context.Context, req *adminpb.DeleteCheckpointRequest) (*emptypb.Empty, error) {
+	storage, err := a.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := checkpointID(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.DeleteCheckpoint(id); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// checkpointID returns the ID of a checkpoint resource name.
+func checkpointID(name string) (string, error) {
+	id, ok := strings.CutPrefix(name, checkpointPrefix)
+	if !ok || id == "" || strings.Contains(id, "/") {
+		return "", status.Errorf(codes.InvalidArgument, "Invalid checkpoint name %q: want checkpoints/{checkpoint}", name)
+	}
+	return id, nil
+}
+
+// findCheckpoint returns a checkpoint of storage by ID.
+func findCheckpoint(storage *server.Storage, id string) (*adminpb.Checkpoint, error) {
+	for _, cp := range storage.Checkpoints() {
+		if cp.ID == id {
+			return checkpointProto(cp), nil
+		}
+	}
+	// Deleted concurrently
+	return nil, status.Errorf(codes.NotFound, "Checkpoint [%s%s] not found", checkpointPrefix, id)
+}
+
+func checkpointProto(cp server.Checkpoint) *adminpb.Checkpoint {
+	return &adminpb.Checkpoint{
+		Name:       checkpointPrefix + cp.ID,
+		CreateTime: timestamppb.New(cp.CreateTime),
+		Secrets:    int32(cp.Secrets),
+	}
+}
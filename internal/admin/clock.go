@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+)
+
+// GetClock returns the time storage reads from its clock.
+func (a *Server) GetClock(_ context.Context, _ *adminpb.GetClockRequest) (*adminpb.Clock, error) {
+	return a.clockProto(), nil
+}
+
+// FreezeClock stops the clock, at the requested time if one is given.
+func (a *Server) FreezeClock(_ context.Context, req *adminpb.FreezeClockRequest) (*adminpb.Clock, error) {
+	if err := a.checkClock(); err != nil {
+		return nil, err
+	}
+	if req.GetTime() != nil {
+		if err := req.GetTime().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid time: %v", err)
+		}
+	}
+
+	a.clock.Freeze()
+	if req.GetTime() != nil {
+		a.clock.Set(req.GetTime().AsTime())
+	}
+	return a.clockProto(), nil
+}
+
+// AdvanceClock moves the clock forward, firing the timers that fall due.
+func (a *Server) AdvanceClock(_ context.Context, req *adminpb.AdvanceClockRequest) (*adminpb.Clock, error) {
+	if err := a.checkClock(); err != nil {
+		return nil, err
+	}
+	if err := req.GetDuration().CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid duration: %v", err)
+	}
+	if err := a.clock.Advance(req.GetDuration().AsDuration()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid duration: %v", err)
+	}
+	return a.clockProto(), nil
+}
+
+// ResumeClock lets a frozen clock run again.
+func (a *Server) ResumeClock(_ context.Context, _ *adminpb.ResumeClockRequest) (*adminpb.Clock, error) {
+	if err := a.checkClock(); err != nil {
+		return nil, err
+	}
+	a.clock.Resume()
+	return a.clockProto(), nil
+}
+
+// checkClock fails if the server was created without a virtual clock.
+func (a *Server) checkClock() error {
+	if a.clock == nil {
+		return status.Error(codes.FailedPrecondition, "The emulator clock is not controllable")
+	}
+	return nil
+}
+
+// clockProto returns the current state of the clock.
+func (a *Server) clockProto() *adminpb.Clock {
+	if a.clock == nil {
+		return &adminpb.Clock{Time: timestamppb.New(a.storage.Clock().Now())}
+	}
+	return &adminpb.Clock{
+		Time:   timestamppb.New(a.clock.Now()),
+		Frozen: a.clock.Frozen(),
+	}
+}
@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/fault"
+)
+
+// faultRulePrefix starts the resource name of every fault rule.
+const faultRulePrefix = "faults/"
+
+// ListFaultRules lists fault injection rules with their counters.
+func (a *Server) ListFaultRules(_ context.Context, _ *adminpb.ListFaultRulesRequest) (*adminpb.ListFaultRulesResponse, error) {
+	if err := a.checkFaults(); err != nil {
+		return nil, err
+	}
+	resp := &adminpb.ListFaultRulesResponse{}
+	for _, rule := range a.faults.Rules() {
+		resp.Rules = append(resp.Rules, faultRuleProto(rule))
+	}
+	return resp, nil
+}
+
+// CreateFaultRule adds a fault injection rule after the existing rules.
+func (a *Server) CreateFaultRule(_ context.Context, req *adminpb.CreateFaultRuleRequest) (*adminpb.FaultRule, error) {
+	if err := a.checkFaults(); err != nil {
+		return nil, err
+	}
+	rule, err := faultRuleFromProto(req.GetRule())
+	if err != nil {
+		return nil, err
+	}
+	rule, err = a.faults.Add(rule)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid fault rule: %v", err)
+	}
+	return faultRuleProto(rule), nil
+}
+
+// DeleteFaultRule deletes a fault injection rule.
+func (a *Server) DeleteFaultRule(_ context.Context, req *adminpb.DeleteFaultRuleRequest) (*emptypb.Empty, error) {
+	if err := a.checkFaults(); err != nil {
+		return nil, err
+	}
+	id, ok := strings.CutPrefix(req.GetName(), faultRulePrefix)
+	if !ok || id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid fault rule name %q: want faults/{id}", req.GetName())
+	}
+	if !a.faults.Delete(id) {
+		return nil, status.Errorf(codes.NotFound, "Fault rule [%s] not found", req.GetName())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ClearFaultRules deletes every fault injection rule.
+func (a *Server) ClearFaultRules(_ context.Context, _ *adminpb.ClearFaultRulesRequest) (*adminpb.ClearFaultRulesResponse, error) {
+	if err := a.checkFaults(); err != nil {
+		return nil, err
+	}
+	return &adminpb.ClearFaultRulesResponse{DeletedRules: int32(a.faults.Clear())}, nil
+}
+
+// checkFaults fails if the server was created without an injector.
+func (a *Server) checkFaults() error {
+	if a.faults == nil {
+		return status.Error(codes.FailedPrecondition, "Fault injection is not enabled")
+	}
+	return nil
+}
+
+// faultRuleProto returns the API representation of a rule.
+func faultRuleProto(rule fault.Rule) *adminpb.FaultRule {
+	r := &adminpb.FaultRule{
+		Name:           faultRulePrefix + rule.ID,
+		Method:         rule.Method,
+		Resource:       rule.Resource,
+		Principal:      rule.Principal,
+		Probability:    rule.Probability,
+		Nth:            rule.Nth,
+		MaxInjections:  rule.MaxInjections,
+		Code:           fault.CodeName(rule.Code),
+		Message:        rule.Message,
+		DropConnection: rule.Drop,
+		MatchedCalls:   rule.Matched,
+		Injections:     rule.Injected,
+	}
+	if rule.RetryDelay > 0 {
+		r.RetryDelay = durationpb.New(rule.RetryDelay)
+	}
+	if rule.Latency > 0 {
+		r.Latency = durationpb.New(rule.Latency)
+	}
+	return r
+}
+
+// faultRuleFromProto converts a rule from the API, ignoring output fields.
+func faultRuleFromProto(r *adminpb.FaultRule) (fault.Rule, error) {
+	c, err := fault.ParseCode(r.GetCode())
+	if err != nil {
+		return fault.Rule{}, status.Errorf(codes.InvalidArgument, "Invalid fault rule: %v", err)
+	}
+	return fault.Rule{
+		Method:        r.GetMethod(),
+		Resource:      r.GetResource(),
+		Principal:     r.GetPrincipal(),
+		Probability:   r.GetProbability(),
+		Nth:           r.GetNth(),
+		MaxInjections: r.GetMaxInjections(),
+		Code:          c,
+		Message:       r.GetMessage(),
+		RetryDelay:    r.GetRetryDelay().AsDuration(),
+		Latency:       r.GetLatency().AsDuration(),
+		Drop:          r.GetDropConnection(),
+	}, nil
+}
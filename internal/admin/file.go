@@ -0,0 +1,181 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// ExportSnapshotFile writes a snapshot of every tenant to path as JSON, the
+// same encoding ExportSnapshot returns over REST. The file is replaced
+// atomically and is only readable by the current user, since it contains
+// payloads.
+func ExportSnapshotFile(path string, tenants *server.Tenants) (*adminpb.Snapshot, error) {
+	snapshot := TenantsSnapshot(tenants)
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// ImportSnapshotFile imports the snapshot written to path by ExportSnapshotFile
+// or exported through the admin API, creating the tenants it holds. The
+// tenants must not already contain any of its secrets.
+func ImportSnapshotFile(path string, tenants *server.Tenants) (*adminpb.ImportSnapshotResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	snapshot := &adminpb.Snapshot{}
+	if err := protojson.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("%s: invalid snapshot: %w", path, err)
+	}
+
+	resp, err := importTenants(snapshot, tenants)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return resp, nil
+}
+
+// TenantsSnapshot returns a snapshot of the default tenant's secrets and of
+// every other tenant.
+func TenantsSnapshot(tenants *server.Tenants) *adminpb.Snapshot {
+	storage := tenants.Default()
+	snapshot := ToSnapshot(storage.Secrets(), storage.Clock().Now())
+	for _, name := range tenants.List() {
+		if storage, ok := tenants.Lookup(name); ok {
+			snapshot.Tenants = append(snapshot.Tenants, &adminpb.TenantSnapshot{
+				Tenant:  name,
+				Secrets: ToSnapshot(storage.Secrets(), storage.Clock().Now()).GetSecrets(),
+			})
+		}
+	}
+	return snapshot
+}
+
+// importTenants imports a snapshot into the default tenant and the tenants
+// it holds, in name order. Every tenant's secrets are validated before any
+// tenant is created or imported into.
+func importTenants(snapshot *adminpb.Snapshot, tenants *server.Tenants) (*adminpb.ImportSnapshotResponse, error) {
+	now := tenants.Default().Clock().Now()
+	imports := make(map[string][]*server.StoredSecret)
+	secrets, err := FromSnapshot(&adminpb.Snapshot{FormatVersion: snapshot.GetFormatVersion(), Secrets: snapshot.GetSecrets()}, now)
+	if err != nil {
+		return nil, err
+	}
+	imports[server.DefaultTenant] = secrets
+	for _, t := range snapshot.GetTenants() {
+		if err := server.ValidateTenant(t.GetTenant()); err != nil {
+			return nil, err
+		}
+		if _, ok := imports[t.GetTenant()]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Tenant %s appears more than once", t.GetTenant())
+		}
+		secrets, err := FromSnapshot(&adminpb.Snapshot{FormatVersion: snapshot.GetFormatVersion(), Secrets: t.GetSecrets()}, now)
+		if err != nil {
+			return nil, tenantError(t.GetTenant(), err)
+		}
+		imports[t.GetTenant()] = secrets
+	}
+
+	names := slices.Sorted(maps.Keys(imports))
+	for _, tenant := range names {
+		storage, _ := tenants.Lookup(tenant) // nil if the tenant does not exist yet
+		if err := storage.ValidateImport(imports[tenant], false); err != nil {
+			return nil, tenantError(tenant, err)
+		}
+	}
+
+	resp := &adminpb.ImportSnapshotResponse{}
+	for _, tenant := range names {
+		secrets := imports[tenant]
+		storage, err := tenants.Get(tenant)
+		if err != nil {
+			return nil, err
+		}
+		if err := storage.Import(secrets, false); err != nil {
+			return nil, tenantError(tenant, err)
+		}
+		resp.ImportedSecrets += int32(len(secrets))
+		for _, stored := range secrets {
+			resp.ImportedVersions += int32(len(stored.Versions))
+		}
+	}
+	return resp, nil
+}
+
+// tenantError names the tenant err occurred in, unless it is the default
+// tenant.
+func tenantError(tenant string, err error) error {
+	if tenant == server.DefaultTenant {
+		return err
+	}
+	return fmt.Errorf("tenant %s: %w", tenant, err)
+}
+
+// ExportSnapshotOnSignal writes a snapshot of every tenant to path each time
+// the process receives SIGUSR1, until ctx is canceled. On platforms without
+// SIGUSR1 it returns immediately.
+func ExportSnapshotOnSignal(ctx context.Context, path string, tenants *server.Tenants, logger *slog.Logger) {
+	if snapshotSignal == nil {
+		return
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, snapshotSignal)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			logSnapshotFile(logger, path, tenants)
+		}
+	}
+}
+
+// logSnapshotFile writes a snapshot of every tenant to path and logs the
+// outcome.
+func logSnapshotFile(logger *slog.Logger, path string, tenants *server.Tenants) {
+	snapshot, err := ExportSnapshotFile(path, tenants)
+	if err != nil {
+		logger.Error("Failed to write snapshot", "path", path, "error", err)
+		return
+	}
+	logger.Info("Snapshot written", "path", path, "secrets", len(snapshot.GetSecrets()), "tenants", len(snapshot.GetTenants()))
+}
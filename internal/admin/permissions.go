@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// ClearPermissionCache drops the cached IAM decisions on a resource, and the
+// resources under it, in the tenant the call selects, or every cached
+// decision when no resource is given.
+func (a *Server) ClearPermissionCache(ctx context.Context, req *adminpb.ClearPermissionCacheRequest) (*adminpb.ClearPermissionCacheResponse, error) {
+	if a.cache == nil {
+		return nil, status.Error(codes.FailedPrecondition, "IAM decision caching is not enabled")
+	}
+	if req.GetResource() == "" {
+		return &adminpb.ClearPermissionCacheResponse{DeletedEntries: int32(a.cache.Clear())}, nil
+	}
+	deleted := a.cache.Invalidate(server.TenantFromContext(ctx), strings.TrimSuffix(req.GetResource(), "/"))
+	return &adminpb.ClearPermissionCacheResponse{DeletedEntries: int32(deleted)}, nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package admin
+
+import "os"
+
+// snapshotSignal is nil where SIGUSR1 does not exist; snapshots are written
+// on shutdown or through the admin API instead.
+var snapshotSignal os.Signal
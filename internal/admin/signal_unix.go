@@ -0,0 +1,11 @@
+//go:build unix
+
+package admin
+
+import (
+	"os"
+	"syscall"
+)
+
+// snapshotSignal asks a running emulator to write a snapshot.
+var snapshotSignal os.Signal = syscall.SIGUSR1
@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// SnapshotFormatVersion is the snapshot format written by ToSnapshot. It is
+// incremented when a change to the Snapshot message would make older
+// emulators import a snapshot incorrectly.
+const SnapshotFormatVersion = 1
+
+// ToSnapshot converts stored secrets, as returned by Storage.Secrets, to a
+// snapshot taken at now.
+func ToSnapshot(secrets []*server.StoredSecret, now time.Time) *adminpb.Snapshot {
+	snapshot := &adminpb.Snapshot{
+		CreateTime:    timestamppb.New(now),
+		FormatVersion: SnapshotFormatVersion,
+	}
+	for _, stored := range secrets {
+		secret := &adminpb.SnapshotSecret{
+			Secret:      secretProto(stored),
+			NextVersion: stored.NextVersion,
+		}
+		for _, version := range sortedVersions(stored) {
+			secret.Versions = append(secret.Versions, &adminpb.SnapshotVersion{
+				Version: versionProto(version),
+				Payload: version.Payload,
+			})
+		}
+		snapshot.Secrets = append(snapshot.Secrets, secret)
+	}
+	return snapshot
+}
+
+// FromSnapshot converts a snapshot to secrets for Storage.Import. Version
+// numbers are taken from the version names; Storage.Import validates them.
+// Secrets without a create time are given now.
+func FromSnapshot(snapshot *adminpb.Snapshot, now time.Time) ([]*server.StoredSecret, error) {
+	if v := snapshot.GetFormatVersion(); v > SnapshotFormatVersion {
+		return nil, status.Errorf(codes.FailedPrecondition, "Snapshot format version %d is newer than the supported version %d", v, SnapshotFormatVersion)
+	}
+
+	var secrets []*server.StoredSecret
+	for _, s := range snapshot.GetSecrets() {
+		secret := s.GetSecret()
+		stored := &server.StoredSecret{
+			Name:           secret.GetName(),
+			CreateTime:     secret.GetCreateTime(),
+			Labels:         secret.GetLabels(),
+			Annotations:    secret.GetAnnotations(),
+			Replication:    secret.GetReplication(),
+			ExpireTime:     secret.GetExpireTime(),
+			VersionAliases: secret.GetVersionAliases(),
+			Versions:       make(map[string]*server.StoredVersion),
+			NextVersion:    s.GetNextVersion(),
+		}
+		if stored.CreateTime == nil {
+			stored.CreateTime = timestamppb.New(now)
+		}
+
+		for _, v := range s.GetVersions() {
+			version := v.GetVersion()
+			id := version.GetName()[strings.LastIndex(version.GetName(), "/")+1:]
+			if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "Invalid version name: %s", version.GetName())
+			}
+			if _, dup := stored.Versions[id]; dup {
+				return nil, status.Errorf(codes.InvalidArgument, "Version [%s] appears more than once", version.GetName())
+			}
+			stored.Versions[id] = &server.StoredVersion{
+				Name:       version.GetName(),
+				CreateTime: version.GetCreateTime(),
+				State:      version.GetState(),
+				Payload:    v.GetPayload(),
+			}
+		}
+
+		// Older or hand-written snapshots may omit the counter
+		if stored.NextVersion == 0 {
+			stored.NextVersion = int64(len(stored.Versions)) + 1
+			for id := range stored.Versions {
+				n, _ := strconv.ParseInt(id, 10, 64)
+				stored.NextVersion = max(stored.NextVersion, n+1)
+			}
+		}
+		secrets = append(secrets, stored)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// tenantPrefix starts the resource name of every tenant.
+const tenantPrefix = "tenants/"
+
+// ListTenants lists the tenants other than the default one.
+func (a *Server) ListTenants(_ context.Context, _ *adminpb.ListTenantsRequest) (*adminpb.ListTenantsResponse, error) {
+	if err := a.checkTenants(); err != nil {
+		return nil, err
+	}
+	resp := &adminpb.ListTenantsResponse{}
+	for _, name := range a.tenants.List() {
+		storage, ok := a.tenants.Lookup(name)
+		if !ok {
+			continue // Deleted since listed
+		}
+		resp.Tenants = append(resp.Tenants, &adminpb.Tenant{
+			Name:    tenantPrefix + name,
+			Secrets: int32(storage.SecretCount()),
+		})
+	}
+	return resp, nil
+}
+
+// DeleteTenant deletes a tenant and all its secrets.
+func (a *Server) DeleteTenant(_ context.Context, req *adminpb.DeleteTenantRequest) (*emptypb.Empty, error) {
+	if err := a.checkTenants(); err != nil {
+		return nil, err
+	}
+	name, ok := strings.CutPrefix(req.GetName(), tenantPrefix)
+	if !ok || server.ValidateTenant(name) != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid tenant name %q: want tenants/{tenant}", req.GetName())
+	}
+	if !a.tenants.Delete(name) {
+		return nil, status.Errorf(codes.NotFound, "Tenant [%s] not found", req.GetName())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// checkTenants fails if the server was created without tenants.
+func (a *Server) checkTenants() error {
+	if a.tenants == nil {
+		return status.Error(codes.FailedPrecondition, "Tenants are not enabled")
+	}
+	return nil
+}
+
+// tenantStorage returns the storage of the tenant an incoming call selects,
+// creating the tenant if needed, or the server's storage without tenants.
+func (a *Server) tenantStorage(ctx context.Context) (*server.Storage, error) {
+	if a.tenants == nil {
+		return a.storage, nil
+	}
+	return a.tenants.FromContext(ctx)
+}
@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+)
+
+// MintToken mints an unsigned JWT for a principal.
+func (a *Server) MintToken(_ context.Context, req *adminpb.MintTokenRequest) (*adminpb.Token, error) {
+	if a.tokens == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Bearer tokens are not enabled")
+	}
+	if req.GetLifetime() != nil && req.GetLifetime().AsDuration() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid lifetime %v: must be positive", req.GetLifetime().AsDuration())
+	}
+	tok, expiry, err := a.tokens.Mint(req.GetPrincipal(), req.GetLifetime().AsDuration())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &adminpb.Token{
+		Token:      tok,
+		Principal:  req.GetPrincipal(),
+		ExpireTime: timestamppb.New(expiry),
+	}, nil
+}
@@ -0,0 +1,385 @@
+// Package audit writes Cloud Audit Logs entries for Secret Manager calls.
+//
+// A Logger is a gRPC interceptor that turns every Secret Manager RPC into a
+// log entry in the shape Cloud Logging exports: a LogEntry whose protoPayload
+// is a google.cloud.audit.AuditLog. Calls that change secrets go to the Admin
+// Activity log (cloudaudit.googleapis.com%2Factivity); calls that read
+// metadata or payloads go to the Data Access log
+// (cloudaudit.googleapis.com%2Fdata_access). Unlike Google Cloud, Data Access
+// entries are always written.
+//
+// The IAM checks the service makes while handling a call are reported with
+// RecordAuthorization and appear in the entry's authorizationInfo. Entries
+// are written as JSON lines and the most recent ones are kept in memory for
+// the admin API.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	auditpb "google.golang.org/genproto/googleapis/cloud/audit"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/logging"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/origin"
+)
+
+// serviceMethodPrefix limits auditing to Secret Manager RPCs; health checks
+// and the admin API are not audited.
+const serviceMethodPrefix = "/google.cloud.secretmanager.v1.SecretManagerService/"
+
+// serviceName is the service audit entries are attributed to.
+const serviceName = "secretmanager.googleapis.com"
+
+// serviceMethodName starts the method name of every audit entry.
+const serviceMethodName = "google.cloud.secretmanager.v1.SecretManagerService."
+
+// Audit logs an entry belongs to.
+const (
+	ActivityLog   = "activity"
+	DataAccessLog = "data_access"
+)
+
+// MaxEntries is the number of recent entries a Logger keeps in memory.
+const MaxEntries = 1000
+
+// activityMethods are the RPCs audited in the Admin Activity log; all other
+// Secret Manager RPCs are Data Access.
+var activityMethods = map[string]bool{
+	"CreateSecret":         true,
+	"UpdateSecret":         true,
+	"DeleteSecret":         true,
+	"AddSecretVersion":     true,
+	"EnableSecretVersion":  true,
+	"DisableSecretVersion": true,
+	"DestroySecretVersion": true,
+	"SetIamPolicy":         true,
+}
+
+// Entry is one audit log entry, in the LogEntry JSON shape of Cloud Logging.
+type Entry struct {
+	// LogName is "projects/{project}/logs/cloudaudit.googleapis.com%2F{log}".
+	LogName string `json:"logName"`
+	// Resource is the monitored resource the entry is attributed to.
+	Resource Resource `json:"resource"`
+	// ProtoPayload is the audit record.
+	ProtoPayload Payload `json:"protoPayload"`
+	// InsertID is unique among the entries of a Logger.
+	InsertID string `json:"insertId"`
+	// Timestamp is the emulator clock's time when the call arrived.
+	Timestamp time.Time `json:"timestamp"`
+	// Severity is NOTICE for Admin Activity, INFO for Data Access and ERROR
+	// for failed calls.
+	Severity string `json:"severity"`
+}
+
+// Resource is a monitored resource of type "audited_resource".
+type Resource struct {
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels"`
+}
+
+// Payload is an AuditLog that encodes as a protoPayload, with its @type.
+type Payload struct {
+	*auditpb.AuditLog
+}
+
+// MarshalJSON encodes the audit record as a google.protobuf.Any.
+func (p Payload) MarshalJSON() ([]byte, error) {
+	a, err := anypb.New(p.AuditLog)
+	if err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(a)
+}
+
+// UnmarshalJSON decodes an audit record encoded by MarshalJSON.
+func (p *Payload) UnmarshalJSON(data []byte) error {
+	var a anypb.Any
+	if err := protojson.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	p.AuditLog = &auditpb.AuditLog{}
+	return a.UnmarshalTo(p.AuditLog)
+}
+
+// Log returns the audit log the entry belongs to: ActivityLog or
+// DataAccessLog.
+func (e Entry) Log() string {
+	_, log, _ := strings.Cut(e.LogName, "cloudaudit.googleapis.com%2F")
+	return log
+}
+
+// Filter selects entries; empty fields match any entry.
+type Filter struct {
+	// Log is ActivityLog or DataAccessLog.
+	Log string
+	// Method is an RPC name such as "AccessSecretVersion" or a full method
+	// name such as "google.cloud.secretmanager.v1.SecretManagerService.AccessSecretVersion".
+	Method string
+	// Principal is an IAM principal such as "user:alice@example.com".
+	Principal string
+	// Resource is the resource name the call acted on.
+	Resource string
+}
+
+// Match reports whether e is selected by the filter.
+func (f Filter) Match(e Entry) bool {
+	p := e.ProtoPayload.AuditLog
+	switch {
+	case f.Log != "" && e.Log() != f.Log:
+		return false
+	case f.Method != "" && p.GetMethodName() != f.Method && !strings.HasSuffix(p.GetMethodName(), "."+f.Method):
+		return false
+	case f.Principal != "" && p.GetAuthenticationInfo().GetPrincipalSubject() != f.Principal:
+		return false
+	case f.Resource != "" && p.GetResourceName() != f.Resource:
+		return false
+	}
+	return true
+}
+
+// Logger writes audit entries as JSON lines and keeps the most recent
+// MaxEntries in memory. It is safe for concurrent use. A nil *Logger audits
+// nothing.
+type Logger struct {
+	clock clock.Clock
+
+	mu      sync.Mutex
+	w       io.Writer // nil keeps entries in memory only
+	enc     *json.Encoder
+	entries []Entry
+	seq     int64
+}
+
+// NewLogger creates a logger writing to w, stamping entries with c. A nil w
+// keeps entries in memory only.
+func NewLogger(w io.Writer, c clock.Clock) *Logger {
+	l := &Logger{clock: c, w: w}
+	if w != nil {
+		l.enc = json.NewEncoder(w)
+	}
+	return l
+}
+
+// Create creates a logger appending to the file at path, which is created
+// with owner-only permissions if it does not exist. The path "-" writes to
+// standard output.
+func Create(path string, c clock.Clock) (*Logger, error) {
+	if path == "-" {
+		return NewLogger(os.Stdout, c), nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewLogger(f, c), nil
+}
+
+// Log writes an entry, assigning its insert ID.
+func (l *Logger) Log(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	e.InsertID = strconv.FormatInt(l.seq, 10)
+	if len(l.entries) == MaxEntries {
+		l.entries = append(l.entries[:0], l.entries[1:]...)
+	}
+	l.entries = append(l.entries, e)
+	if l.enc == nil {
+		return nil
+	}
+	return l.enc.Encode(e)
+}
+
+// Entries returns the entries in memory selected by f, oldest first.
+func (l *Logger) Entries(f Filter) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var entries []Entry
+	for _, e := range l.entries {
+		if f.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Clear drops the entries in memory and returns how many there were.
+func (l *Logger) Clear() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := len(l.entries)
+	l.entries = nil
+	return n
+}
+
+// Close closes the underlying writer if it is an io.Closer other than
+// standard output.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok := l.w.(io.Closer); ok && l.w != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
+
+// UnaryServerInterceptor audits Secret Manager RPCs, including calls rejected
+// by interceptors later in the chain.
+func (l *Logger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method, ok := strings.CutPrefix(info.FullMethod, serviceMethodPrefix)
+		if l == nil || !ok {
+			return handler(ctx, req)
+		}
+		now := l.clock.Now()
+		t := &trail{}
+		resp, err := handler(context.WithValue(ctx, trailKey{}, t), req)
+
+		e := l.entry(ctx, method, logging.ResourceName(req), err)
+		e.Timestamp = now
+		e.ProtoPayload.AuthorizationInfo = t.infos()
+		if logErr := l.Log(e); logErr != nil {
+			slog.Error("Failed to write audit log entry", "method", info.FullMethod, "error", logErr)
+		}
+		return resp, err
+	}
+}
+
+// entry builds the entry for a call to method on resource that returned err.
+func (l *Logger) entry(ctx context.Context, method, resource string, err error) Entry {
+	log, severity := DataAccessLog, "INFO"
+	if activityMethods[method] {
+		log, severity = ActivityLog, "NOTICE"
+	}
+	if err != nil {
+		severity = "ERROR"
+	}
+	project := projectID(resource)
+	fullMethod := serviceMethodName + method
+
+	payload := &auditpb.AuditLog{
+		ServiceName:        serviceName,
+		MethodName:         fullMethod,
+		ResourceName:       resource,
+		Status:             &spb.Status{},
+		AuthenticationInfo: authenticationInfo(emulatorauth.ExtractPrincipalFromContext(ctx)),
+		RequestMetadata:    requestMetadata(ctx),
+	}
+	if err != nil {
+		payload.Status = status.Convert(err).Proto()
+	}
+	logProject := project
+	if logProject == "" {
+		logProject = "-"
+	}
+	return Entry{
+		LogName: "projects/" + logProject + "/logs/cloudaudit.googleapis.com%2F" + log,
+		Resource: Resource{
+			Type: "audited_resource",
+			Labels: map[string]string{
+				"service":    serviceName,
+				"method":     fullMethod,
+				"project_id": project,
+			},
+		},
+		ProtoPayload: Payload{payload},
+		Severity:     severity,
+	}
+}
+
+// authenticationInfo identifies principal, giving the email of users and
+// service accounts.
+func authenticationInfo(principal string) *auditpb.AuthenticationInfo {
+	if principal == "" {
+		return nil
+	}
+	info := &auditpb.AuthenticationInfo{PrincipalSubject: principal}
+	if kind, email, ok := strings.Cut(principal, ":"); ok && (kind == "user" || kind == "serviceAccount") {
+		info.PrincipalEmail = email
+	}
+	return info
+}
+
+// requestMetadata describes the caller of a gRPC call. Calls forwarded by the
+// REST gateway have none, since the gateway is the gRPC caller.
+func requestMetadata(ctx context.Context) *auditpb.RequestMetadata {
+	if origin.FromGateway(ctx) {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	rm := &auditpb.RequestMetadata{}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		rm.CallerIp = p.Addr.String()
+		if host, _, err := net.SplitHostPort(rm.CallerIp); err == nil {
+			rm.CallerIp = host
+		}
+	}
+	if ua := md.Get("user-agent"); len(ua) > 0 {
+		rm.CallerSuppliedUserAgent = ua[0]
+	}
+	return rm
+}
+
+// projectID returns the project of a resource name such as
+// "projects/p/secrets/s", or "" if it has none.
+func projectID(resource string) string {
+	parts := strings.SplitN(resource, "/", 3)
+	if len(parts) < 2 || parts[0] != "projects" {
+		return ""
+	}
+	return parts[1]
+}
+
+// trailKey is the context key of the authorization trail of a call.
+type trailKey struct{}
+
+// trail collects the IAM checks made while handling a call.
+type trail struct {
+	mu    sync.Mutex
+	authz []*auditpb.AuthorizationInfo
+}
+
+func (t *trail) infos() []*auditpb.AuthorizationInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.authz
+}
+
+// RecordAuthorization adds the outcome of an IAM check of permission on
+// resource to the audit entry of the call ctx belongs to. It does nothing if
+// the call is not audited.
+func RecordAuthorization(ctx context.Context, resource, permission string, granted bool) {
+	t, ok := ctx.Value(trailKey{}).(*trail)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.authz = append(t.authz, &auditpb.AuthorizationInfo{
+		Resource:   resource,
+		Permission: permission,
+		Granted:    granted,
+	})
+}
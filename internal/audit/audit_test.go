@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/origin"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// call runs method through the logger's interceptor with a handler that
+// records an IAM check of permission and returns err.
+func call(l *Logger, ctx context.Context, method string, req any, permission string, err error) {
+	info := &grpc.UnaryServerInfo{FullMethod: serviceMethodPrefix + method}
+	_, _ = l.UnaryServerInterceptor()(ctx, req, info, func(ctx context.Context, _ any) (any, error) {
+		if permission != "" {
+			RecordAuthorization(ctx, "projects/p/secrets/db", permission, err == nil)
+		}
+		return "ok", err
+	})
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, clock.NewFrozen(start))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		emulatorauth.PrincipalMetadataKey, "serviceAccount:ci@p.iam.gserviceaccount.com",
+		"user-agent", "test-client",
+	))
+
+	call(l, ctx, "CreateSecret", &secretmanagerpb.CreateSecretRequest{Parent: "projects/p", SecretId: "db"}, "secretmanager.secrets.create", nil)
+	call(l, ctx, "AccessSecretVersion", &secretmanagerpb.AccessSecretVersionRequest{Name: "projects/p/secrets/db/versions/1"},
+		"secretmanager.versions.access", status.Error(codes.PermissionDenied, "Permission denied"))
+	marked, _ := metadata.FromOutgoingContext(origin.MarkGateway(context.Background()))
+	restCtx := metadata.NewIncomingContext(context.Background(), marked)
+	call(l, restCtx, "ListSecrets", &secretmanagerpb.ListSecretsRequest{Parent: "projects/p"}, "", nil)
+	_, _ = l.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"},
+		func(context.Context, any) (any, error) { return nil, nil })
+
+	entries := l.Entries(Filter{})
+	if len(entries) != 3 {
+		t.Fatalf("logged %d entries, want 3", len(entries))
+	}
+	create, access, list := entries[0], entries[1], entries[2]
+
+	p := create.ProtoPayload
+	if create.LogName != "projects/p/logs/cloudaudit.googleapis.com%2Factivity" || create.Severity != "NOTICE" || !create.Timestamp.Equal(start) {
+		t.Errorf("CreateSecret entry = %+v", create)
+	}
+	if p.GetMethodName() != "google.cloud.secretmanager.v1.SecretManagerService.CreateSecret" || p.GetResourceName() != "projects/p" ||
+		p.GetAuthenticationInfo().GetPrincipalEmail() != "ci@p.iam.gserviceaccount.com" || p.GetStatus().GetCode() != 0 {
+		t.Errorf("CreateSecret payload = %v", p.AuditLog)
+	}
+	if p.GetRequestMetadata().GetCallerSuppliedUserAgent() != "test-client" {
+		t.Errorf("CreateSecret requestMetadata = %v", p.GetRequestMetadata())
+	}
+
+	p = access.ProtoPayload
+	if access.Log() != DataAccessLog || access.Severity != "ERROR" || p.GetStatus().GetCode() != int32(codes.PermissionDenied) {
+		t.Errorf("AccessSecretVersion entry = %+v, payload = %v", access, p.AuditLog)
+	}
+	if authz := p.GetAuthorizationInfo(); len(authz) != 1 || authz[0].GetPermission() != "secretmanager.versions.access" || authz[0].GetGranted() {
+		t.Errorf("AccessSecretVersion authorizationInfo = %v", authz)
+	}
+
+	p = list.ProtoPayload
+	if list.Log() != DataAccessLog || p.GetAuthenticationInfo() != nil || p.GetRequestMetadata() != nil || len(p.GetAuthorizationInfo()) != 0 {
+		t.Errorf("ListSecrets from the gateway payload = %v", p.AuditLog)
+	}
+
+	// Entries are written as JSON lines in the LogEntry shape
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("wrote %d lines, want 3", len(lines))
+	}
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &raw); err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := raw["protoPayload"].(map[string]any)
+	if payload["@type"] != "type.googleapis.com/google.cloud.audit.AuditLog" || payload["serviceName"] != "secretmanager.googleapis.com" || payload["status"] == nil || raw["insertId"] != "1" {
+		t.Errorf("CreateSecret line = %s", lines[0])
+	}
+	var decoded Entry
+	if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil {
+		t.Fatalf("Unmarshal(entry) error = %v", err)
+	}
+	if decoded.ProtoPayload.GetAuthorizationInfo()[0].GetResource() != "projects/p/secrets/db" {
+		t.Errorf("decoded AccessSecretVersion entry = %+v", decoded)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	l := NewLogger(nil, clock.NewFrozen(start))
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:alice@example.com"))
+	bob := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:bob@example.com"))
+	call(l, alice, "AddSecretVersion", &secretmanagerpb.AddSecretVersionRequest{Parent: "projects/p/secrets/db"}, "", nil)
+	call(l, alice, "AccessSecretVersion", &secretmanagerpb.AccessSecretVersionRequest{Name: "projects/p/secrets/db/versions/1"}, "", nil)
+	call(l, bob, "AccessSecretVersion", &secretmanagerpb.AccessSecretVersionRequest{Name: "projects/p/secrets/db/versions/1"}, "", nil)
+
+	for _, tt := range []struct {
+		filter Filter
+		want   int
+	}{
+		{Filter{}, 3},
+		{Filter{Log: ActivityLog}, 1},
+		{Filter{Log: DataAccessLog, Principal: "user:alice@example.com"}, 1},
+		{Filter{Method: "AccessSecretVersion"}, 2},
+		{Filter{Method: "google.cloud.secretmanager.v1.SecretManagerService.AddSecretVersion"}, 1},
+		{Filter{Method: "SecretVersion"}, 0},
+		{Filter{Resource: "projects/p/secrets/db"}, 1},
+	} {
+		if got := len(l.Entries(tt.filter)); got != tt.want {
+			t.Errorf("Entries(%+v) = %d entries, want %d", tt.filter, got, tt.want)
+		}
+	}
+
+	if n := l.Clear(); n != 3 || len(l.Entries(Filter{})) != 0 {
+		t.Errorf("Clear() = %d, want 3 and no entries left", n)
+	}
+	call(l, bob, "GetSecret", &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/db"}, "", nil)
+	if e := l.Entries(Filter{}); len(e) != 1 || e[0].InsertID != "4" {
+		t.Errorf("entry after Clear() = %+v, want insert ID 4", e)
+	}
+}
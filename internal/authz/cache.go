@@ -0,0 +1,138 @@
+package authz
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+)
+
+// maxCachedDecisions bounds the cache; it is emptied when full of live entries.
+const maxCachedDecisions = 10000
+
+// DecisionCache caches IAM permission check decisions for a short TTL, so
+// repeated checks skip the round trip to the IAM emulator. Decisions are
+// keyed by tenant, principal, resource and permission. A nil cache caches
+// nothing.
+type DecisionCache struct {
+	clock clock.Clock
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[decisionKey]decision
+	hits    int64
+	misses  int64
+}
+
+type decisionKey struct {
+	tenant, principal, resource, permission string
+}
+
+type decision struct {
+	allowed bool
+	expires time.Time
+}
+
+// CacheStats reports how a DecisionCache has been used.
+type CacheStats struct {
+	Hits    int64 // lookups answered from the cache
+	Misses  int64 // lookups that went to IAM
+	Entries int   // decisions cached, including expired ones not yet dropped
+}
+
+// NewDecisionCache creates a cache keeping decisions for ttl by c.
+func NewDecisionCache(c clock.Clock, ttl time.Duration) *DecisionCache {
+	return &DecisionCache{clock: c, ttl: ttl, entries: make(map[decisionKey]decision)}
+}
+
+// Get returns the cached decision of a check, counting a hit or a miss.
+func (c *DecisionCache) Get(tenant, principal, resource, permission string) (allowed, ok bool) {
+	if c == nil {
+		return false, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := decisionKey{tenant, principal, resource, permission}
+	d, ok := c.entries[key]
+	if ok && !c.clock.Now().Before(d.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		return false, false
+	}
+	c.hits++
+	return d.allowed, true
+}
+
+// Put caches the decision of a check. Only decisions IAM actually made
+// should be cached, not errors.
+func (c *DecisionCache) Put(tenant, principal, resource, permission string, allowed bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	if len(c.entries) >= maxCachedDecisions {
+		for key, d := range c.entries {
+			if !now.Before(d.expires) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= maxCachedDecisions {
+			clear(c.entries)
+		}
+	}
+	c.entries[decisionKey{tenant, principal, resource, permission}] = decision{allowed: allowed, expires: now.Add(c.ttl)}
+}
+
+// Invalidate drops the decisions in tenant on resource and the resources
+// under it, e.g. the versions of a secret, or every decision in tenant when
+// resource is empty. It returns the number of decisions dropped.
+func (c *DecisionCache) Invalidate(tenant, resource string) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for key := range c.entries {
+		if key.tenant != tenant {
+			continue
+		}
+		if resource == "" || key.resource == resource || strings.HasPrefix(key.resource, resource+"/") {
+			delete(c.entries, key)
+			n++
+		}
+	}
+	return n
+}
+
+// Clear drops every decision and returns how many there were.
+func (c *DecisionCache) Clear() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := len(c.entries)
+	clear(c.entries)
+	return n
+}
+
+// Stats returns the cache's hit and miss counts and size.
+func (c *DecisionCache) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Entries: len(c.entries)}
+}
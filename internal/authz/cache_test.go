@@ -0,0 +1,66 @@
+package authz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+)
+
+func TestDecisionCache(t *testing.T) {
+	c := clock.NewFrozen(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := NewDecisionCache(c, time.Second)
+	const alice, perm = "user:alice@example.com", "secretmanager.versions.access"
+
+	if _, ok := cache.Get("", alice, "projects/p/secrets/db", perm); ok {
+		t.Fatal("Get() on an empty cache hit")
+	}
+	cache.Put("", alice, "projects/p/secrets/db", perm, true)
+	cache.Put("", alice, "projects/p/secrets/db/versions/1", perm, false)
+	cache.Put("", alice, "projects/p/secrets/dbx", perm, true)
+
+	if allowed, ok := cache.Get("", alice, "projects/p/secrets/db", perm); !ok || !allowed {
+		t.Errorf("Get() = %v, %v; want a cached allow", allowed, ok)
+	}
+	if allowed, ok := cache.Get("", alice, "projects/p/secrets/db/versions/1", perm); !ok || allowed {
+		t.Errorf("Get(version) = %v, %v; want a cached deny", allowed, ok)
+	}
+	if _, ok := cache.Get("", "user:bob@example.com", "projects/p/secrets/db", perm); ok {
+		t.Error("Get() for another principal hit")
+	}
+	if _, ok := cache.Get("job-42", alice, "projects/p/secrets/db", perm); ok {
+		t.Error("Get() in another tenant hit")
+	}
+
+	// Invalidating a secret drops its versions but not other secrets
+	cache.Put("job-42", alice, "projects/p/secrets/db", perm, true)
+	if n := cache.Invalidate("", "projects/p/secrets/db"); n != 2 {
+		t.Errorf("Invalidate() = %d, want 2", n)
+	}
+	if _, ok := cache.Get("", alice, "projects/p/secrets/dbx", perm); !ok {
+		t.Error("Get(dbx) after invalidating db missed")
+	}
+	if _, ok := cache.Get("job-42", alice, "projects/p/secrets/db", perm); !ok {
+		t.Error("Get() in another tenant after invalidating db missed")
+	}
+	if n := cache.Clear(); n != 2 {
+		t.Errorf("Clear() = %d, want 2", n)
+	}
+	cache.Put("", alice, "projects/p/secrets/dbx", perm, true)
+
+	// Decisions expire after the TTL
+	_ = c.Advance(time.Second)
+	if _, ok := cache.Get("", alice, "projects/p/secrets/dbx", perm); ok {
+		t.Error("Get() after the TTL hit")
+	}
+
+	if got, want := cache.Stats(), (CacheStats{Hits: 4, Misses: 4, Entries: 0}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	var none *DecisionCache
+	none.Put("", alice, "projects/p", perm, true)
+	if _, ok := none.Get("", alice, "projects/p", perm); ok {
+		t.Error("nil cache hit")
+	}
+}
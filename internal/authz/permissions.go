@@ -14,59 +14,61 @@ type PermissionCheck struct {
 	Target     ResourceTarget
 }
 
-// OperationPermissions maps Secret Manager operations to their required permissions
-var OperationPermissions = map[string]PermissionCheck{
+// Permission is a Secret Manager IAM permission and the operations that require it
+type Permission struct {
+	Name       string
+	Operations []string // RPC method names, e.g. "GetSecret"
+	Target     ResourceTarget
+}
+
+// Catalog lists every Secret Manager IAM permission with the operations that
+// require it. Operations the emulator does not implement yet are listed too,
+// so implementing them cannot bypass strict mode.
+var Catalog = []Permission{
+	// Location operations (google.cloud.location.Locations)
+	{Name: "secretmanager.locations.get", Operations: []string{"GetLocation"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.locations.list", Operations: []string{"ListLocations"}, Target: ResourceTargetParent},
+
 	// Secret operations
-	"CreateSecret": {
-		Permission: "secretmanager.secrets.create",
-		Target:     ResourceTargetParent,
-	},
-	"GetSecret": {
-		Permission: "secretmanager.secrets.get",
-		Target:     ResourceTargetSelf,
-	},
-	"UpdateSecret": {
-		Permission: "secretmanager.secrets.update",
-		Target:     ResourceTargetSelf,
-	},
-	"DeleteSecret": {
-		Permission: "secretmanager.secrets.delete",
-		Target:     ResourceTargetSelf,
-	},
-	"ListSecrets": {
-		Permission: "secretmanager.secrets.list",
-		Target:     ResourceTargetParent,
-	},
+	{Name: "secretmanager.secrets.create", Operations: []string{"CreateSecret"}, Target: ResourceTargetParent},
+	{Name: "secretmanager.secrets.delete", Operations: []string{"DeleteSecret"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.secrets.get", Operations: []string{"GetSecret"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.secrets.getIamPolicy", Operations: []string{"GetIamPolicy"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.secrets.list", Operations: []string{"ListSecrets"}, Target: ResourceTargetParent},
+	{Name: "secretmanager.secrets.setIamPolicy", Operations: []string{"SetIamPolicy"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.secrets.update", Operations: []string{"UpdateSecret"}, Target: ResourceTargetSelf},
+
+	// Secret version operations, checked against the version or its secret
+	{Name: "secretmanager.versions.access", Operations: []string{"AccessSecretVersion"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.versions.add", Operations: []string{"AddSecretVersion"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.versions.destroy", Operations: []string{"DestroySecretVersion"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.versions.disable", Operations: []string{"DisableSecretVersion"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.versions.enable", Operations: []string{"EnableSecretVersion"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.versions.get", Operations: []string{"GetSecretVersion"}, Target: ResourceTargetSelf},
+	{Name: "secretmanager.versions.list", Operations: []string{"ListSecretVersions"}, Target: ResourceTargetSelf},
+}
+
+// UncheckedOperations are the operations GCP allows without any permission.
+// TestIamPermissions reports the caller's own permissions.
+var UncheckedOperations = map[string]bool{
+	"TestIamPermissions": true,
+}
+
+// OperationPermissions maps Secret Manager operations to their required
+// permissions. It is derived from Catalog.
+var OperationPermissions = operationPermissions(Catalog)
 
-	// Secret version operations
-	"AddSecretVersion": {
-		Permission: "secretmanager.versions.add",
-		Target:     ResourceTargetSelf, // Check against secret
-	},
-	"AccessSecretVersion": {
-		Permission: "secretmanager.versions.access",
-		Target:     ResourceTargetSelf, // Check against version or secret
-	},
-	"GetSecretVersion": {
-		Permission: "secretmanager.versions.get",
-		Target:     ResourceTargetSelf, // Check against version or secret
-	},
-	"ListSecretVersions": {
-		Permission: "secretmanager.versions.list",
-		Target:     ResourceTargetSelf, // Check against secret
-	},
-	"EnableSecretVersion": {
-		Permission: "secretmanager.versions.enable",
-		Target:     ResourceTargetSelf, // Check against version or secret
-	},
-	"DisableSecretVersion": {
-		Permission: "secretmanager.versions.disable",
-		Target:     ResourceTargetSelf, // Check against version or secret
-	},
-	"DestroySecretVersion": {
-		Permission: "secretmanager.versions.destroy",
-		Target:     ResourceTargetSelf, // Check against version or secret
-	},
+func operationPermissions(catalog []Permission) map[string]PermissionCheck {
+	ops := make(map[string]PermissionCheck)
+	for _, perm := range catalog {
+		for _, op := range perm.Operations {
+			if _, dup := ops[op]; dup {
+				panic("authz: operation " + op + " requires more than one permission")
+			}
+			ops[op] = PermissionCheck{Permission: perm.Name, Target: perm.Target}
+		}
+	}
+	return ops
 }
 
 // GetPermission returns the permission check for an operation
@@ -5,14 +5,32 @@ import "strings"
 // NormalizeSecretResource extracts the secret path from a full resource name.
 // Input: projects/{p}/secrets/{s}/versions/{v}
 // Output: projects/{p}/secrets/{s}
+//
+// Regional names (projects/{p}/locations/{l}/secrets/{s}/...) are handled the same way.
 func NormalizeSecretResource(name string) string {
 	parts := strings.Split(name, "/")
 	if len(parts) >= 4 && parts[0] == "projects" && parts[2] == "secrets" {
 		return strings.Join(parts[:4], "/")
 	}
+	if len(parts) >= 6 && parts[0] == "projects" && parts[2] == "locations" && parts[4] == "secrets" {
+		return strings.Join(parts[:6], "/")
+	}
 	return name
 }
 
+// TenantResource returns the name IAM knows a resource of tenant by, so that
+// each tenant has its own policies in the IAM emulator.
+// Input: job-42, projects/{p}/secrets/{s}
+// Output: tenants/job-42/projects/{p}/secrets/{s}
+//
+// Resources of the default tenant ("") keep their names.
+func TenantResource(tenant, resource string) string {
+	if tenant == "" {
+		return resource
+	}
+	return "tenants/" + tenant + "/" + resource
+}
+
 // NormalizeSecretVersionResource preserves the full version path.
 // Input: projects/{p}/secrets/{s}/versions/{v}
 // Output: projects/{p}/secrets/{s}/versions/{v}
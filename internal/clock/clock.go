@@ -0,0 +1,210 @@
+// Package clock provides the time source for the emulator's time-based
+// behavior: create times, secret expiration and quota refill.
+//
+// Production code uses Real. Tests and the admin API use a Virtual clock,
+// which runs with real time until it is frozen and can be moved forward
+// without waiting, firing any timers that fall due. A clock frozen at a fixed
+// instant makes create times, and everything derived from them, reproducible.
+package clock
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and schedules timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls f once d has elapsed, like time.AfterFunc. f is never
+	// called from within AfterFunc, so the caller may hold locks f takes.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer scheduled with Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the timer from firing, reporting whether it was pending.
+	Stop() bool
+}
+
+// Real is the system clock.
+type Real struct{}
+
+// Now returns time.Now().
+func (Real) Now() time.Time { return time.Now() }
+
+// AfterFunc wraps time.AfterFunc.
+func (Real) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// Virtual is a controllable clock. While running it follows real time,
+// shifted by however far it has been advanced or set; while frozen it stands
+// still. Timers fire when the virtual time reaches them, whether by real time
+// passing or by Advance and Set. It is safe for concurrent use.
+type Virtual struct {
+	mu     sync.Mutex
+	offset time.Duration // added to real time while running
+	frozen bool
+	at     time.Time // the time while frozen
+
+	timers []*virtualTimer // pending, by due time
+	wakeup *time.Timer     // fires the earliest pending timer while running
+}
+
+// NewVirtual returns a running virtual clock showing real time.
+func NewVirtual() *Virtual {
+	return &Virtual{}
+}
+
+// NewFrozen returns a virtual clock frozen at t.
+func NewFrozen(t time.Time) *Virtual {
+	return &Virtual{frozen: true, at: t}
+}
+
+// Now returns the virtual time.
+func (v *Virtual) Now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now()
+}
+
+func (v *Virtual) now() time.Time {
+	if v.frozen {
+		return v.at
+	}
+	return time.Now().Add(v.offset)
+}
+
+// Frozen reports whether the clock is frozen.
+func (v *Virtual) Frozen() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.frozen
+}
+
+// Freeze stops the clock at the current virtual time.
+func (v *Virtual) Freeze() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.frozen {
+		v.at = v.now()
+		v.frozen = true
+		v.schedule()
+	}
+}
+
+// Resume lets a frozen clock run again from the time it shows.
+func (v *Virtual) Resume() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.frozen {
+		v.offset = v.at.Sub(time.Now())
+		v.frozen = false
+		v.schedule()
+	}
+}
+
+// Advance moves the clock forward by d and fires the timers that fall due,
+// in order, before returning.
+func (v *Virtual) Advance(d time.Duration) error {
+	if d < 0 {
+		return errors.New("clock cannot be advanced by a negative duration")
+	}
+	v.mu.Lock()
+	if v.frozen {
+		v.at = v.at.Add(d)
+	} else {
+		v.offset += d
+	}
+	v.mu.Unlock()
+	v.fire()
+	return nil
+}
+
+// Set moves the clock to t, forward or back, keeping it frozen or running,
+// and fires the timers that fall due before returning. Timers that already
+// fired are not undone by moving back.
+func (v *Virtual) Set(t time.Time) {
+	v.mu.Lock()
+	if v.frozen {
+		v.at = t
+	} else {
+		v.offset = t.Sub(time.Now())
+	}
+	v.mu.Unlock()
+	v.fire()
+}
+
+// AfterFunc calls f once the virtual time is d past now: from Advance or
+// Set when they move the clock past it, or in its own goroutine when real
+// time does. Timers due immediately fire right away in their own goroutine.
+func (v *Virtual) AfterFunc(d time.Duration, f func()) Timer {
+	v.mu.Lock()
+	t := &virtualTimer{clock: v, due: v.now().Add(d), f: f}
+	v.timers = append(v.timers, t)
+	sort.SliceStable(v.timers, func(i, j int) bool { return v.timers[i].due.Before(v.timers[j].due) })
+	v.mu.Unlock()
+
+	if d <= 0 {
+		go v.fire()
+	} else {
+		v.mu.Lock()
+		v.schedule()
+		v.mu.Unlock()
+	}
+	return t
+}
+
+// fire runs the timers that are due and reschedules the wakeup for the rest.
+// The callbacks run without v.mu held, one after another; they may use the
+// clock.
+func (v *Virtual) fire() {
+	v.mu.Lock()
+	now := v.now()
+	n := 0
+	for n < len(v.timers) && !v.timers[n].due.After(now) {
+		n++
+	}
+	due := v.timers[:n:n]
+	v.timers = v.timers[n:]
+	v.schedule()
+	v.mu.Unlock()
+
+	for _, t := range due {
+		t.f()
+	}
+}
+
+// schedule arranges for fire to run in real time when the earliest pending
+// timer falls due, if the clock is running. v.mu must be held.
+func (v *Virtual) schedule() {
+	if v.wakeup != nil {
+		v.wakeup.Stop()
+		v.wakeup = nil
+	}
+	if v.frozen || len(v.timers) == 0 {
+		return
+	}
+	v.wakeup = time.AfterFunc(v.timers[0].due.Sub(v.now()), v.fire)
+}
+
+type virtualTimer struct {
+	clock *Virtual
+	due   time.Time
+	f     func()
+}
+
+func (t *virtualTimer) Stop() bool {
+	v := t.clock
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for n, pending := range v.timers {
+		if pending == t {
+			v.timers = append(v.timers[:n], v.timers[n+1:]...)
+			v.schedule()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestVirtual_Frozen(t *testing.T) {
+	c := NewFrozen(start)
+	var fired []string
+	c.AfterFunc(2*time.Hour, func() { fired = append(fired, "2h") })
+	c.AfterFunc(time.Hour, func() { fired = append(fired, "1h") })
+	stopped := c.AfterFunc(90*time.Minute, func() { fired = append(fired, "90m") })
+
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("Stop() = false on a pending timer, or true twice")
+	}
+	if err := c.Advance(59 * time.Minute); err != nil || len(fired) != 0 {
+		t.Fatalf("Advance(59m) = %v, fired %v", err, fired)
+	}
+	if !c.Now().Equal(start.Add(59 * time.Minute)) {
+		t.Errorf("Now() = %v, want 59m past start", c.Now())
+	}
+
+	// Timers fire in due order before Advance returns
+	if err := c.Advance(2 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if len(fired) != 2 || fired[0] != "1h" || fired[1] != "2h" {
+		t.Errorf("fired %v, want [1h 2h]", fired)
+	}
+
+	// Moving back does not fire anything again
+	c.Set(start)
+	if !c.Now().Equal(start) || len(fired) != 2 {
+		t.Errorf("Set(start): Now() = %v, fired %v", c.Now(), fired)
+	}
+	if err := c.Advance(-time.Second); err == nil {
+		t.Error("Advance(-1s) error = nil")
+	}
+}
+
+func TestVirtual_Running(t *testing.T) {
+	c := NewVirtual()
+	if d := time.Since(c.Now()); d < 0 || d > time.Second {
+		t.Errorf("Now() is %v from real time", d)
+	}
+
+	// Advancing a running clock shifts it and keeps it running
+	if err := c.Advance(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if d := c.Now().Sub(time.Now()); d < 59*time.Minute {
+		t.Errorf("Now() after Advance(1h) is %v ahead, want about 1h", d)
+	}
+
+	c.Freeze()
+	frozenAt := c.Now()
+	fired := make(chan struct{})
+	c.AfterFunc(10*time.Millisecond, func() { close(fired) })
+	time.Sleep(20 * time.Millisecond)
+	if !c.Frozen() || !c.Now().Equal(frozenAt) {
+		t.Fatalf("frozen clock moved: %v, was %v", c.Now(), frozenAt)
+	}
+	select {
+	case <-fired:
+		t.Fatal("timer fired while the clock was frozen")
+	default:
+	}
+
+	// Once resumed, real time passing fires the timer
+	c.Resume()
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire after Resume")
+	}
+	if c.Frozen() || c.Now().Before(frozenAt) {
+		t.Errorf("resumed clock: frozen %v, Now() = %v", c.Frozen(), c.Now())
+	}
+}
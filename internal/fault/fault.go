@@ -0,0 +1,231 @@
+// Package fault injects errors, latency and dropped connections into Secret
+// Manager RPCs so clients can test their retry and backoff behavior.
+//
+// An Injector holds an ordered list of rules. Each rule matches calls by
+// method, resource name and principal, selects matching calls by nth-call
+// and probability, and then injects latency followed by an error or a
+// dropped connection. Rules are loaded at startup with Load and managed at
+// runtime through the admin API.
+//
+// Faults are injected by a gRPC interceptor. REST requests reach it through
+// the gateway's backend connection, so the same rules and counters apply to
+// both; a dropped REST call aborts the caller's HTTP connection.
+package fault
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Rule is a fault injection rule.
+type Rule struct {
+	// ID identifies the rule; assigned by Injector.Add.
+	ID string
+
+	// Method matches the RPC: a method name such as "AccessSecretVersion",
+	// a full method name, or a glob of either. Empty matches every RPC.
+	Method string
+	// Resource is a glob matched against the request's resource name.
+	// Empty matches every resource.
+	Resource string
+	// Principal is a glob matched against the caller's principal. Empty
+	// matches every caller.
+	Principal string
+
+	// Probability is the chance, in [0, 1], that a selected call fires.
+	// Zero means always.
+	Probability float64
+	// Nth selects every nth matching call. Zero or one selects every call.
+	Nth int64
+	// MaxInjections stops the rule after firing this many times. Zero
+	// means no limit.
+	MaxInjections int64
+
+	// Code is the error returned; codes.OK injects no error.
+	Code codes.Code
+	// Message is the error message; defaults to one naming the rule.
+	Message string
+	// RetryDelay adds a google.rpc.RetryInfo detail to the error.
+	RetryDelay time.Duration
+	// Latency delays the call before the error or the call proceeds.
+	Latency time.Duration
+	// Drop closes the caller's connection instead of responding.
+	Drop bool
+
+	// Matched and Injected count the calls the rule matched and fired on.
+	// They are set by Injector.Rules and ignored by Injector.Add.
+	Matched  int64
+	Injected int64
+}
+
+// Validate checks that the rule's patterns and values are well formed and
+// that it injects something.
+func (r *Rule) Validate() error {
+	for _, pattern := range []string{r.Method, r.Resource, r.Principal} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	switch {
+	case r.Probability < 0 || r.Probability > 1:
+		return fmt.Errorf("probability %v must be between 0 and 1", r.Probability)
+	case r.Nth < 0:
+		return fmt.Errorf("nth %d must not be negative", r.Nth)
+	case r.MaxInjections < 0:
+		return fmt.Errorf("max injections %d must not be negative", r.MaxInjections)
+	case r.RetryDelay < 0 || r.Latency < 0:
+		return fmt.Errorf("durations must not be negative")
+	case r.Drop && r.Code != codes.OK:
+		return fmt.Errorf("a rule cannot both drop the connection and return %s", r.Code)
+	case r.RetryDelay > 0 && r.Code == codes.OK:
+		return fmt.Errorf("retry delay requires an error code")
+	case r.Code == codes.OK && r.Latency == 0 && !r.Drop:
+		return fmt.Errorf("rule must inject an error, latency or a dropped connection")
+	}
+	return nil
+}
+
+// matches reports whether the rule applies to a call.
+func (r *Rule) matches(fullMethod, resource, principal string) bool {
+	if r.Method != "" && !match(r.Method, fullMethod) && !match(r.Method, fullMethod[strings.LastIndex(fullMethod, "/")+1:]) {
+		return false
+	}
+	if r.Resource != "" && !match(r.Resource, resource) {
+		return false
+	}
+	return r.Principal == "" || match(r.Principal, principal)
+}
+
+// match reports whether name matches the glob pattern. Patterns were
+// validated when the rule was added.
+func match(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// Err returns the error the rule injects, or nil if it injects none.
+func (r *Rule) Err() error {
+	if r.Code == codes.OK {
+		return nil
+	}
+	msg := r.Message
+	if msg == "" {
+		msg = fmt.Sprintf("Fault injected by rule %s", r.ID)
+	}
+	st := status.New(r.Code, msg)
+	if r.RetryDelay > 0 {
+		if withDetails, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(r.RetryDelay)}); err == nil {
+			st = withDetails
+		}
+	}
+	return st.Err()
+}
+
+// Injector evaluates fault injection rules. It is safe for concurrent use.
+// The zero value is not usable; create one with NewInjector.
+type Injector struct {
+	mu     sync.Mutex
+	rules  []*Rule
+	nextID int
+	rand   func() float64 // returns a value in [0, 1)
+
+	conns *connTracker
+}
+
+// NewInjector creates an injector without rules.
+func NewInjector() *Injector {
+	return &Injector{
+		nextID: 1,
+		rand:   rand.Float64,
+		conns:  newConnTracker(),
+	}
+}
+
+// Add validates r and appends it to the rules, returning it with its ID.
+func (i *Injector) Add(r Rule) (Rule, error) {
+	if err := r.Validate(); err != nil {
+		return Rule{}, err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	r.ID = strconv.Itoa(i.nextID)
+	r.Matched, r.Injected = 0, 0
+	i.nextID++
+	i.rules = append(i.rules, &r)
+	return r, nil
+}
+
+// Delete removes the rule with the given ID, reporting whether it existed.
+func (i *Injector) Delete(id string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for n, r := range i.rules {
+		if r.ID == id {
+			i.rules = append(i.rules[:n], i.rules[n+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes every rule and returns how many there were.
+func (i *Injector) Clear() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	n := len(i.rules)
+	i.rules = nil
+	return n
+}
+
+// Rules returns copies of the rules in evaluation order, with their counters.
+func (i *Injector) Rules() []Rule {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	rules := make([]Rule, len(i.rules))
+	for n, r := range i.rules {
+		rules[n] = *r
+	}
+	return rules
+}
+
+// Evaluate counts a call against every rule it matches and returns a copy
+// of the first rule that fires, or nil if none does.
+func (i *Injector) Evaluate(fullMethod, resource, principal string) *Rule {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var fired *Rule
+	for _, r := range i.rules {
+		if !r.matches(fullMethod, resource, principal) {
+			continue
+		}
+		r.Matched++
+		if fired != nil {
+			continue
+		}
+		if r.MaxInjections > 0 && r.Injected >= r.MaxInjections {
+			continue
+		}
+		if r.Nth > 1 && r.Matched%r.Nth != 0 {
+			continue
+		}
+		if r.Probability > 0 && i.rand() >= r.Probability {
+			continue
+		}
+		r.Injected++
+		rule := *r
+		fired = &rule
+	}
+	return fired
+}
@@ -0,0 +1,233 @@
+package fault
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/origin"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+const accessMethod = "/google.cloud.secretmanager.v1.SecretManagerService/AccessSecretVersion"
+
+func mustAdd(t *testing.T, i *Injector, r Rule) Rule {
+	t.Helper()
+	r, err := i.Add(r)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	return r
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      Rule
+		method    string
+		resource  string
+		principal string
+		want      bool
+	}{
+		{"short method", Rule{Method: "AccessSecretVersion", Code: codes.Internal}, accessMethod, "", "", true},
+		{"full method", Rule{Method: accessMethod, Code: codes.Internal}, accessMethod, "", "", true},
+		{"method glob", Rule{Method: "*SecretVersion", Code: codes.Internal}, accessMethod, "", "", true},
+		{"other method", Rule{Method: "GetSecret", Code: codes.Internal}, accessMethod, "", "", false},
+		{"resource glob", Rule{Resource: "projects/*/secrets/db-*", Code: codes.Internal}, accessMethod, "projects/p/secrets/db-main", "", true},
+		{"resource glob stops at slash", Rule{Resource: "projects/*/secrets/db-*", Code: codes.Internal}, accessMethod, "projects/p/secrets/db-main/versions/1", "", false},
+		{"principal", Rule{Principal: "serviceAccount:*@ci.iam.gserviceaccount.com", Code: codes.Internal}, accessMethod, "", "serviceAccount:job@ci.iam.gserviceaccount.com", true},
+		{"other principal", Rule{Principal: "user:alice@example.com", Code: codes.Internal}, accessMethod, "", "user:bob@example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := NewInjector()
+			mustAdd(t, i, tt.rule)
+			if got := i.Evaluate(tt.method, tt.resource, tt.principal) != nil; got != tt.want {
+				t.Errorf("Evaluate() fired = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Selection(t *testing.T) {
+	i := NewInjector()
+	nth := mustAdd(t, i, Rule{Method: "AccessSecretVersion", Nth: 3, MaxInjections: 2, Code: codes.Unavailable})
+
+	var fired []int
+	for call := 1; call <= 12; call++ {
+		if rule := i.Evaluate(accessMethod, "", ""); rule != nil {
+			fired = append(fired, call)
+		}
+	}
+	if len(fired) != 2 || fired[0] != 3 || fired[1] != 6 {
+		t.Errorf("nth=3 max=2 fired on calls %v, want [3 6]", fired)
+	}
+	if got := i.Rules()[0]; got.Matched != 12 || got.Injected != 2 {
+		t.Errorf("counters = matched %d, injected %d; want 12, 2", got.Matched, got.Injected)
+	}
+
+	// The first rule that fires wins; probability is drawn per selected call
+	i.Delete(nth.ID)
+	i.rand = func() float64 { return 0.5 }
+	mustAdd(t, i, Rule{Probability: 0.25, Code: codes.Internal})
+	second := mustAdd(t, i, Rule{Probability: 0.75, Code: codes.DeadlineExceeded})
+	if rule := i.Evaluate(accessMethod, "", ""); rule == nil || rule.ID != second.ID {
+		t.Errorf("Evaluate() = %v, want rule %s", rule, second.ID)
+	}
+
+	if n := i.Clear(); n != 2 {
+		t.Errorf("Clear() = %d, want 2", n)
+	}
+	if rule := i.Evaluate(accessMethod, "", ""); rule != nil {
+		t.Errorf("Evaluate() without rules = %v", rule)
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	invalid := map[string]Rule{
+		"no action":          {Method: "GetSecret"},
+		"bad glob":           {Resource: "projects/[", Code: codes.Internal},
+		"probability":        {Probability: 1.5, Code: codes.Internal},
+		"drop and code":      {Drop: true, Code: codes.Internal},
+		"retry without code": {RetryDelay: time.Second, Latency: time.Second},
+	}
+	for name, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("%s: Validate() error = nil", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "faults.yaml")
+	data := `rules:
+  - method: AccessSecretVersion
+    nth: 2
+    code: unavailable
+    retry_delay: 1.5s
+  - latency: 10ms
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(rules) != 2 || rules[0].Code != codes.Unavailable || rules[0].RetryDelay != 1500*time.Millisecond || rules[1].Latency != 10*time.Millisecond {
+		t.Errorf("Load() = %+v", rules)
+	}
+
+	for content, want := range map[string]string{
+		"rules:\n  - code: NOPE\n":               "rule 1: invalid code",
+		"rules:\n  - latency: soon\n":            "rule 1: invalid latency",
+		"rules:\n  - code: INTERNAL\n    x: 1\n": "field x not found",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load(%q) error = %v, want %q", content, err, want)
+		}
+	}
+}
+
+// startServer serves Secret Manager with injector's interceptor and listener
+// and returns a client connected to it.
+func startServer(t *testing.T, injector *Injector) secretmanagerpb.SecretManagerServiceClient {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock, err := server.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.UnaryInterceptor(injector.UnaryServerInterceptor()))
+	secretmanagerpb.RegisterSecretManagerServiceServer(srv, mock)
+	go func() { _ = srv.Serve(injector.Listener(lis)) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return secretmanagerpb.NewSecretManagerServiceClient(conn)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	injector := NewInjector()
+	client := startServer(t, injector)
+	ctx := emulatorauth.InjectPrincipalToContext(context.Background(), "user:ci@example.com")
+	req := &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/missing"}
+
+	// Errors carry RetryInfo and only hit matching callers
+	mustAdd(t, injector, Rule{Method: "GetSecret", Principal: "user:ci@*", Code: codes.Unavailable, RetryDelay: 2 * time.Second})
+	_, err := client.GetSecret(ctx, req)
+	st := status.Convert(err)
+	if st.Code() != codes.Unavailable {
+		t.Fatalf("GetSecret() error = %v, want Unavailable", err)
+	}
+	var retry *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	if retry.GetRetryDelay().AsDuration() != 2*time.Second {
+		t.Errorf("RetryInfo = %v, want 2s delay", retry)
+	}
+	if _, err := client.GetSecret(context.Background(), req); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret() as another caller error = %v, want NotFound from the service", err)
+	}
+	injector.Clear()
+
+	// Latency delays the call, which then proceeds
+	mustAdd(t, injector, Rule{Latency: 50 * time.Millisecond})
+	start := time.Now()
+	if _, err := client.GetSecret(ctx, req); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret() with latency error = %v, want NotFound", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("GetSecret() took %v, want at least 50ms", elapsed)
+	}
+	injector.Clear()
+
+	// Dropping closes the connection; the client sees the transport fail
+	mustAdd(t, injector, Rule{Drop: true, MaxInjections: 1})
+	_, err = client.GetSecret(ctx, req)
+	if status.Code(err) != codes.Unavailable || IsDropped(err) {
+		t.Errorf("GetSecret() dropped error = %v, want a transport UNAVAILABLE", err)
+	}
+	if _, err := client.GetSecret(ctx, req); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret() after reconnect error = %v, want NotFound", err)
+	}
+
+	// Calls forwarded by the gateway get the dropped marker instead
+	mustAdd(t, injector, Rule{Drop: true})
+	_, err = client.GetSecret(origin.MarkGateway(ctx), req)
+	if !IsDropped(err) {
+		t.Errorf("GetSecret() via gateway error = %v, want dropped marker", err)
+	}
+
+	// A direct client cannot pass for the gateway
+	_, err = client.GetSecret(metadata.AppendToOutgoingContext(ctx, origin.MetadataKey, "true"), req)
+	if status.Code(err) != codes.Unavailable || IsDropped(err) {
+		t.Errorf("GetSecret() with a forged gateway mark error = %v, want a transport UNAVAILABLE", err)
+	}
+}
@@ -0,0 +1,129 @@
+package fault
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+// ruleFile is the YAML or JSON layout of a rules file:
+//
+//	rules:
+//	  - method: AccessSecretVersion
+//	    resource: projects/*/secrets/db-*
+//	    nth: 2
+//	    code: UNAVAILABLE
+//	    retry_delay: 1.5s
+//
+// Field names match the admin API's FaultRule message.
+type ruleFile struct {
+	Rules []ruleSpec `yaml:"rules"`
+}
+
+type ruleSpec struct {
+	Method         string  `yaml:"method"`
+	Resource       string  `yaml:"resource"`
+	Principal      string  `yaml:"principal"`
+	Probability    float64 `yaml:"probability"`
+	Nth            int64   `yaml:"nth"`
+	MaxInjections  int64   `yaml:"max_injections"`
+	Code           string  `yaml:"code"`
+	Message        string  `yaml:"message"`
+	RetryDelay     string  `yaml:"retry_delay"`
+	Latency        string  `yaml:"latency"`
+	DropConnection bool    `yaml:"drop_connection"`
+}
+
+// Load reads rules from a YAML or JSON file. Rules are validated but not
+// added to an injector.
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f ruleFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	rules := make([]Rule, 0, len(f.Rules))
+	for n, spec := range f.Rules {
+		r, err := spec.rule()
+		if err == nil {
+			err = r.Validate()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, n+1, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func (s ruleSpec) rule() (Rule, error) {
+	r := Rule{
+		Method:        s.Method,
+		Resource:      s.Resource,
+		Principal:     s.Principal,
+		Probability:   s.Probability,
+		Nth:           s.Nth,
+		MaxInjections: s.MaxInjections,
+		Message:       s.Message,
+		Drop:          s.DropConnection,
+	}
+
+	var err error
+	if r.Code, err = ParseCode(s.Code); err != nil {
+		return r, err
+	}
+	if r.RetryDelay, err = parseDuration("retry_delay", s.RetryDelay); err != nil {
+		return r, err
+	}
+	if r.Latency, err = parseDuration("latency", s.Latency); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// ParseCode parses a gRPC status code name such as "UNAVAILABLE". The empty
+// string is codes.OK.
+func ParseCode(name string) (codes.Code, error) {
+	if name == "" {
+		return codes.OK, nil
+	}
+	v, ok := code.Code_value[strings.ToUpper(name)]
+	if !ok {
+		return codes.OK, fmt.Errorf("invalid code %q", name)
+	}
+	return codes.Code(v), nil
+}
+
+// CodeName returns the name of c as accepted by ParseCode, or "" for codes.OK.
+func CodeName(c codes.Code) string {
+	if c == codes.OK {
+		return ""
+	}
+	return code.Code(c).String()
+}
+
+func parseDuration(field, s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", field, s, err)
+	}
+	return d, nil
+}
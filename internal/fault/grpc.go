@@ -0,0 +1,173 @@
+package fault
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/logging"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/origin"
+)
+
+// serviceMethodPrefix limits injection to Secret Manager RPCs, so health
+// checks and the admin API used to manage rules are never affected.
+const serviceMethodPrefix = "/google.cloud.secretmanager.v1.SecretManagerService/"
+
+// Reason and domain of the google.rpc.ErrorInfo detail marking a dropped
+// connection.
+const (
+	droppedReason = "CONNECTION_DROPPED"
+	droppedDomain = "emulator.secretmanager"
+)
+
+// UnaryServerInterceptor injects faults into Secret Manager RPCs. Dropping a
+// connection requires serving on a listener wrapped with Listener; otherwise
+// the call fails with UNAVAILABLE.
+func (i *Injector) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, serviceMethodPrefix) {
+			return handler(ctx, req)
+		}
+		rule := i.Evaluate(info.FullMethod, logging.ResourceName(req), emulatorauth.ExtractPrincipalFromContext(ctx))
+		if rule == nil {
+			return handler(ctx, req)
+		}
+
+		if rule.Latency > 0 {
+			timer := time.NewTimer(rule.Latency)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, status.FromContextError(ctx.Err()).Err()
+			case <-timer.C:
+			}
+		}
+		if rule.Drop {
+			return nil, i.drop(ctx, rule)
+		}
+		if err := rule.Err(); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// drop closes the caller's connection, unless the call came through the
+// REST gateway, and returns the error marking the call as dropped.
+func (i *Injector) drop(ctx context.Context, rule *Rule) error {
+	st, _ := status.New(codes.Unavailable, "Connection dropped by fault rule "+rule.ID).WithDetails(&errdetails.ErrorInfo{
+		Reason: droppedReason,
+		Domain: droppedDomain,
+	})
+	// A call forwarded by the REST gateway is reported back to the gateway,
+	// which aborts the REST caller's connection, instead of closing the shared
+	// backend connection
+	if origin.FromGateway(ctx) {
+		return st.Err()
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		i.conns.close(p.Addr.String())
+	}
+	return st.Err()
+}
+
+// IsDropped reports whether err marks a call whose connection a fault rule
+// dropped.
+func IsDropped(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() == droppedReason && info.GetDomain() == droppedDomain {
+			return true
+		}
+	}
+	return false
+}
+
+// Listener wraps lis so that fault rules can drop the connections it
+// accepts. Connections are identified by their remote address; callers on
+// Unix sockets usually share one, so a drop closes all of them.
+func (i *Injector) Listener(lis net.Listener) net.Listener {
+	return &listener{Listener: lis, conns: i.conns}
+}
+
+type listener struct {
+	net.Listener
+	conns *connTracker
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return l.conns.track(c), nil
+}
+
+// connTracker records open connections by remote address.
+type connTracker struct {
+	mu    sync.Mutex
+	conns map[string]map[*trackedConn]struct{}
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{conns: make(map[string]map[*trackedConn]struct{})}
+}
+
+func (t *connTracker) track(c net.Conn) net.Conn {
+	tc := &trackedConn{Conn: c, tracker: t, addr: c.RemoteAddr().String()}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conns[tc.addr] == nil {
+		t.conns[tc.addr] = make(map[*trackedConn]struct{})
+	}
+	t.conns[tc.addr][tc] = struct{}{}
+	return tc
+}
+
+func (t *connTracker) untrack(tc *trackedConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.conns[tc.addr], tc)
+	if len(t.conns[tc.addr]) == 0 {
+		delete(t.conns, tc.addr)
+	}
+}
+
+// close closes every connection from addr.
+func (t *connTracker) close(addr string) {
+	t.mu.Lock()
+	conns := make([]*trackedConn, 0, len(t.conns[addr]))
+	for tc := range t.conns[addr] {
+		conns = append(conns, tc)
+	}
+	t.mu.Unlock()
+
+	for _, tc := range conns {
+		_ = tc.Close()
+	}
+}
+
+// trackedConn removes itself from its tracker when closed.
+type trackedConn struct {
+	net.Conn
+	tracker *connTracker
+	addr    string
+	once    sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() { c.tracker.untrack(c) })
+	return c.Conn.Close()
+}
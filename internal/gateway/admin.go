@@ -0,0 +1,406 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+)
+
+// adminRoutes lists the REST bindings of the emulator's admin API, derived
+// from the google.api.http annotations of AdminService. They are served only
+// when the gateway is created WithAdmin.
+var adminRoutes = buildRoutes(adminpb.File_admin_v1_admin_proto.Services().ByName("AdminService"), adminHandlers)
+
+// adminHandlers maps each AdminService RPC to the handler serving its REST
+// bindings.
+var adminHandlers = map[string]routeHandler{
+	"Reset": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminReset(ctx, w, r, vars["parent"])
+	},
+	"ExportSnapshot": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminExportSnapshot(ctx, w, r)
+	},
+	"ImportSnapshot": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminImportSnapshot(ctx, w, r)
+	},
+	"ListSecrets": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminListSecrets(ctx, w, r, vars["parent"])
+	},
+	"GetStats": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminGetStats(ctx, w, r)
+	},
+	"ListFaultRules": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminListFaultRules(ctx, w, r)
+	},
+	"CreateFaultRule": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminCreateFaultRule(ctx, w, r)
+	},
+	"DeleteFaultRule": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminDeleteFaultRule(ctx, w, r, vars["name"])
+	},
+	"ClearFaultRules": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminClearFaultRules(ctx, w, r)
+	},
+	"GetClock": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminGetClock(ctx, w, r)
+	},
+	"FreezeClock": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminFreezeClock(ctx, w, r)
+	},
+	"AdvanceClock": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminAdvanceClock(ctx, w, r)
+	},
+	"ResumeClock": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminResumeClock(ctx, w, r)
+	},
+	"ListTenants": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminListTenants(ctx, w, r)
+	},
+	"DeleteTenant": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminDeleteTenant(ctx, w, r, vars["name"])
+	},
+	"ListAuditLogEntries": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminListAuditLogEntries(ctx, w, r)
+	},
+	"ClearAuditLogEntries": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminClearAuditLogEntries(ctx, w, r)
+	},
+	"CreateCheckpoint": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminCreateCheckpoint(ctx, w, r)
+	},
+	"ListCheckpoints": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminListCheckpoints(ctx, w, r)
+	},
+	"RestoreCheckpoint": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminRestoreCheckpoint(ctx, w, r, vars["name"])
+	},
+	"DeleteCheckpoint": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminDeleteCheckpoint(ctx, w, r, vars["name"])
+	},
+	"MintToken": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminMintToken(ctx, w, r)
+	},
+	"ClearPermissionCache": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.adminClearPermissionCache(ctx, w, r)
+	},
+}
+
+// handleAdmin routes admin REST requests using the admin route table.
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	s.dispatch(w, r, adminRoutes)
+}
+
+func (s *Server) adminReset(ctx context.Context, w http.ResponseWriter, r *http.Request, parent string) {
+	var req adminpb.ResetRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if parent != "" {
+		req.Parent = parent
+	}
+
+	resp, err := s.admin.Reset(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminExportSnapshot(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	resp, err := s.admin.ExportSnapshot(ctx, &adminpb.ExportSnapshotRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminImportSnapshot(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var snapshot adminpb.Snapshot
+	if !decodeBody(w, r, &snapshot) {
+		return
+	}
+
+	req := &adminpb.ImportSnapshotRequest{Snapshot: &snapshot}
+	if v := r.URL.Query().Get("replace"); v != "" {
+		replace, err := strconv.ParseBool(v)
+		if err != nil {
+			writeHTTPError(w, http.StatusBadRequest, codes.InvalidArgument, fmt.Sprintf("Invalid value for replace: %q", v))
+			return
+		}
+		req.Replace = replace
+	}
+
+	resp, err := s.admin.ImportSnapshot(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminListSecrets(ctx context.Context, w http.ResponseWriter, r *http.Request, parent string) {
+	resp, err := s.admin.ListSecrets(ctx, &adminpb.ListSecretsRequest{Parent: parent})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminGetStats(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	resp, err := s.admin.GetStats(ctx, &adminpb.GetStatsRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminListFaultRules(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	resp, err := s.admin.ListFaultRules(ctx, &adminpb.ListFaultRulesRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminCreateFaultRule(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var rule adminpb.FaultRule
+	if !decodeBody(w, r, &rule) {
+		return
+	}
+
+	resp, err := s.admin.CreateFaultRule(ctx, &adminpb.CreateFaultRuleRequest{Rule: &rule})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminDeleteFaultRule(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
+	resp, err := s.admin.DeleteFaultRule(ctx, &adminpb.DeleteFaultRuleRequest{Name: name})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminClearFaultRules(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req adminpb.ClearFaultRulesRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := s.admin.ClearFaultRules(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminGetClock(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	resp, err := s.admin.GetClock(ctx, &adminpb.GetClockRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminFreezeClock(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req adminpb.FreezeClockRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := s.admin.FreezeClock(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminAdvanceClock(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req adminpb.AdvanceClockRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := s.admin.AdvanceClock(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminResumeClock(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req adminpb.ResumeClockRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := s.admin.ResumeClock(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminListTenants(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	resp, err := s.admin.ListTenants(ctx, &adminpb.ListTenantsRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminDeleteTenant(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
+	resp, err := s.admin.DeleteTenant(ctx, &adminpb.DeleteTenantRequest{Name: name})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminListAuditLogEntries(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	resp, err := s.admin.ListAuditLogEntries(ctx, &adminpb.ListAuditLogEntriesRequest{
+		Log:       query.Get("log"),
+		Method:    query.Get("method"),
+		Principal: query.Get("principal"),
+		Resource:  query.Get("resource"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminClearAuditLogEntries(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req adminpb.ClearAuditLogEntriesRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := s.admin.ClearAuditLogEntries(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminCreateCheckpoint(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req adminpb.CreateCheckpointRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := s.admin.CreateCheckpoint(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminListCheckpoints(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	resp, err := s.admin.ListCheckpoints(ctx, &adminpb.ListCheckpointsRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminRestoreCheckpoint(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
+	var req adminpb.RestoreCheckpointRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	req.Name = name
+
+	resp, err := s.admin.RestoreCheckpoint(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminDeleteCheckpoint(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
+	resp, err := s.admin.DeleteCheckpoint(ctx, &adminpb.DeleteCheckpointRequest{Name: name})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminMintToken(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req adminpb.MintTokenRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := s.admin.MintToken(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) adminClearPermissionCache(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req adminpb.ClearPermissionCacheRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	resp, err := s.admin.ClearPermissionCache(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
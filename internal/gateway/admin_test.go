@@ -0,0 +1,469 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/audit"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/fault"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
+)
+
+// startAdminBackend starts an in-process gRPC backend with the admin API,
+// fault injection, audit logging and bearer tokens and returns its address.
+func startAdminBackend(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	faults := fault.NewInjector()
+	clk := clock.NewVirtual()
+	auditLog := audit.NewLogger(nil, clk)
+	tokens := token.NewResolver(clk, nil)
+	grpcServer := grpc.NewServer(append(tokens.ServerOptions(), grpc.ChainUnaryInterceptor(auditLog.UnaryServerInterceptor(), faults.UnaryServerInterceptor()))...)
+	mockServer, err := server.NewServer(server.WithClock(clk))
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	secretmanagerpb.RegisterSecretManagerServiceServer(grpcServer, mockServer)
+	admin.NewServer(mockServer.Storage(), admin.WithFaults(faults), admin.WithClock(clk), admin.WithAudit(auditLog), admin.WithTenants(mockServer.Tenants()), admin.WithTokens(tokens)).Register(grpcServer)
+	go func() {
+		_ = grpcServer.Serve(faults.Listener(lis))
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
+
+// unmarshalBody decodes a JSON response body into msg.
+func unmarshalBody(t *testing.T, body string, msg proto.Message) {
+	t.Helper()
+	if err := protojson.Unmarshal([]byte(body), msg); err != nil {
+		t.Fatalf("Failed to decode %s: %v", body, err)
+	}
+}
+
+func TestGateway_Admin(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t), WithAdmin())
+	secrets := ts.URL + "/v1/projects/test-project/secrets"
+
+	for _, id := range []string{"a", "b"} {
+		resp, body := doRequest(t, http.MethodPost, secrets+"?secretId="+id, `{"replication":{"automatic":{}}}`)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("CreateSecret status = %d: %s", resp.StatusCode, body)
+		}
+	}
+	resp, body := doRequest(t, http.MethodPost, secrets+"/a:addVersion", `{"payload":{"data":"c2VjcmV0"}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("AddSecretVersion status = %d: %s", resp.StatusCode, body)
+	}
+
+	resp, body = doRequest(t, http.MethodGet, ts.URL+"/admin/v1/stats", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GetStats status = %d: %s", resp.StatusCode, body)
+	}
+	var stats adminpb.Stats
+	unmarshalBody(t, body, &stats)
+	if stats.Secrets != 2 || stats.PayloadBytes != 6 {
+		t.Errorf("GetStats = %v, want 2 secrets and 6 payload bytes", &stats)
+	}
+
+	resp, body = doRequest(t, http.MethodGet, ts.URL+"/admin/v1/projects/test-project/secrets", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ListSecrets status = %d: %s", resp.StatusCode, body)
+	}
+	var list adminpb.ListSecretsResponse
+	unmarshalBody(t, body, &list)
+	if len(list.Secrets) != 2 || len(list.Secrets[0].Versions) != 1 || list.Secrets[0].Versions[0].PayloadSizeBytes != 6 {
+		t.Errorf("ListSecrets = %v, want payload metadata of version a/1", &list)
+	}
+	if strings.Contains(body, "c2VjcmV0") {
+		t.Errorf("ListSecrets body = %s, want no payloads", body)
+	}
+
+	resp, snapshot := doRequest(t, http.MethodGet, ts.URL+"/admin/v1/snapshot", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ExportSnapshot status = %d: %s", resp.StatusCode, snapshot)
+	}
+	if !strings.Contains(snapshot, "c2VjcmV0") {
+		t.Errorf("ExportSnapshot body = %s, want payload", snapshot)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/projects/test-project:reset", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Reset status = %d: %s", resp.StatusCode, body)
+	}
+	var reset adminpb.ResetResponse
+	unmarshalBody(t, body, &reset)
+	if reset.DeletedSecrets != 2 {
+		t.Errorf("Reset deleted %d secrets, want 2", reset.DeletedSecrets)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/snapshot:import", snapshot)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ImportSnapshot status = %d: %s", resp.StatusCode, body)
+	}
+	var imported adminpb.ImportSnapshotResponse
+	unmarshalBody(t, body, &imported)
+	if imported.ImportedSecrets != 2 || imported.ImportedVersions != 1 {
+		t.Errorf("ImportSnapshot = %v, want 2 secrets and 1 version", &imported)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/snapshot:import", snapshot)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("ImportSnapshot over existing secrets status = %d, want %d: %s", resp.StatusCode, http.StatusConflict, body)
+	}
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/snapshot:import?replace=true", snapshot)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("ImportSnapshot(replace) status = %d: %s", resp.StatusCode, body)
+	}
+
+	resp, body = doRequest(t, http.MethodGet, secrets+"/a/versions/1:access", "")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "c2VjcmV0") {
+		t.Errorf("AccessSecretVersion after import status = %d: %s", resp.StatusCode, body)
+	}
+
+	resp, _ = doRequest(t, http.MethodGet, ts.URL+"/admin/v1:reset", "")
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /admin/v1:reset status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGateway_AdminLargeSnapshot(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t), WithAdmin())
+
+	// Dozens of maximum-size payloads make a snapshot over the 1 MiB limit
+	// of other requests
+	snapshot := &adminpb.Snapshot{}
+	payload := bytes.Repeat([]byte("x"), 64<<10)
+	for i := range 24 {
+		name := fmt.Sprintf("projects/test-project/secrets/s%d", i)
+		snapshot.Secrets = append(snapshot.Secrets, &adminpb.SnapshotSecret{
+			Secret:      &secretmanagerpb.Secret{Name: name},
+			NextVersion: 2,
+			Versions: []*adminpb.SnapshotVersion{{
+				Version: &secretmanagerpb.SecretVersion{Name: name + "/versions/1", State: secretmanagerpb.SecretVersion_ENABLED},
+				Payload: payload,
+			}},
+		})
+	}
+	body, err := protojson.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(body) <= DefaultMaxBodyBytes {
+		t.Fatalf("snapshot is %d bytes, want more than %d for this test", len(body), DefaultMaxBodyBytes)
+	}
+
+	resp, respBody := doRequest(t, http.MethodPost, ts.URL+"/admin/v1/snapshot:import", string(body))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ImportSnapshot status = %d: %s", resp.StatusCode, respBody)
+	}
+	var imported adminpb.ImportSnapshotResponse
+	unmarshalBody(t, respBody, &imported)
+	if imported.ImportedSecrets != 24 {
+		t.Errorf("ImportSnapshot = %v, want 24 secrets", &imported)
+	}
+
+	// Other requests keep their limit
+	resp, _ = doRequest(t, http.MethodPost, ts.URL+"/v1/projects/test-project/secrets?secretId=big", string(body))
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("CreateSecret status = %d, want %d", resp.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestGateway_AdminDisabled(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t))
+
+	for _, path := range []string{"/admin/v1/stats", "/admin/v1/snapshot"} {
+		resp, body := doRequest(t, http.MethodGet, ts.URL+path, "")
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s status = %d, want %d: %s", path, resp.StatusCode, http.StatusNotFound, body)
+		}
+	}
+}
+
+func TestGateway_AdminFaults(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t), WithAdmin())
+	secret := ts.URL + "/v1/projects/test-project/secrets/db"
+
+	resp, body := doRequest(t, http.MethodPost, ts.URL+"/admin/v1/faults", `{"method":"GetSecret","code":"UNAVAILABLE","retryDelay":"3s","maxInjections":"1"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateFaultRule status = %d: %s", resp.StatusCode, body)
+	}
+	var rule adminpb.FaultRule
+	unmarshalBody(t, body, &rule)
+	if rule.Name != "faults/1" {
+		t.Errorf("CreateFaultRule name = %q, want faults/1", rule.Name)
+	}
+
+	// The injected error reaches REST callers with its RetryInfo
+	resp, body = doRequest(t, http.MethodGet, secret, "")
+	var errResp errorBody
+	if err := json.Unmarshal([]byte(body), &errResp); err != nil {
+		t.Fatalf("Failed to decode %s: %v", body, err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || len(errResp.Error.Details) != 1 {
+		t.Fatalf("GetSecret = %d %s, want 503 with RetryInfo", resp.StatusCode, body)
+	}
+	var detail anypb.Any
+	var retry errdetails.RetryInfo
+	if err := protojson.Unmarshal(errResp.Error.Details[0], &detail); err != nil {
+		t.Fatalf("Failed to decode detail: %v", err)
+	}
+	if err := detail.UnmarshalTo(&retry); err != nil || retry.RetryDelay.AsDuration() != 3*time.Second {
+		t.Errorf("GetSecret details = %s, want RetryInfo with a 3s delay", errResp.Error.Details[0])
+	}
+	resp, _ = doRequest(t, http.MethodGet, secret, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GetSecret after max injections status = %d, want 404", resp.StatusCode)
+	}
+
+	// Dropped calls abort the REST connection
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/faults", `{"method":"GetSecret","dropConnection":true}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateFaultRule status = %d: %s", resp.StatusCode, body)
+	}
+	// A fresh transport, since clients retry idempotent requests that fail
+	// on reused connections
+	client := &http.Client{Transport: &http.Transport{}}
+	if dropped, err := client.Get(secret); err == nil {
+		dropped.Body.Close()
+		t.Errorf("GetSecret with dropped connection status = %d, want a connection error", dropped.StatusCode)
+	}
+
+	resp, body = doRequest(t, http.MethodGet, ts.URL+"/admin/v1/faults", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ListFaultRules status = %d: %s", resp.StatusCode, body)
+	}
+	var list adminpb.ListFaultRulesResponse
+	unmarshalBody(t, body, &list)
+	if len(list.Rules) != 2 || list.Rules[0].MatchedCalls != 3 || list.Rules[0].Injections != 1 {
+		t.Errorf("ListFaultRules = %v, want 2 rules, the first matched 3 times and fired once", &list)
+	}
+
+	resp, _ = doRequest(t, http.MethodDelete, ts.URL+"/admin/v1/faults/2", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("DeleteFaultRule status = %d, want 200", resp.StatusCode)
+	}
+	resp, _ = doRequest(t, http.MethodDelete, ts.URL+"/admin/v1/faults/2", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("DeleteFaultRule twice status = %d, want 404", resp.StatusCode)
+	}
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/faults:clear", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ClearFaultRules status = %d: %s", resp.StatusCode, body)
+	}
+	var cleared adminpb.ClearFaultRulesResponse
+	unmarshalBody(t, body, &cleared)
+	if cleared.DeletedRules != 1 {
+		t.Errorf("ClearFaultRules deleted %d rules, want 1", cleared.DeletedRules)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/faults", `{"method":"GetSecret"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("CreateFaultRule without action = %d %s, want 400", resp.StatusCode, body)
+	}
+}
+
+func TestGateway_AdminClock(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t), WithAdmin())
+
+	resp, body := doRequest(t, http.MethodPost, ts.URL+"/admin/v1/clock:freeze", `{"time":"2024-01-01T00:00:00Z"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("FreezeClock status = %d: %s", resp.StatusCode, body)
+	}
+
+	// Create times come from the frozen clock, so responses are reproducible
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/v1/projects/test-project/secrets?secretId=s", `{"replication":{"automatic":{}},"ttl":"60s"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateSecret status = %d: %s", resp.StatusCode, body)
+	}
+	var secret secretmanagerpb.Secret
+	unmarshalBody(t, body, &secret)
+	if got := secret.GetCreateTime().AsTime().Format(time.RFC3339); got != "2024-01-01T00:00:00Z" {
+		t.Errorf("createTime = %s, want the frozen time", got)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/clock:advance", `{"duration":"60s"}`)
+	var c adminpb.Clock
+	unmarshalBody(t, body, &c)
+	if resp.StatusCode != http.StatusOK || c.GetTime().AsTime().Format(time.RFC3339) != "2024-01-01T00:01:00Z" || !c.GetFrozen() {
+		t.Fatalf("AdvanceClock = %d %s", resp.StatusCode, body)
+	}
+	if resp, _ := doRequest(t, http.MethodGet, ts.URL+"/v1/projects/test-project/secrets/s", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GetSecret after expiry status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestGateway_AdminAuditLog(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t), WithAdmin())
+
+	doRequest(t, http.MethodPost, ts.URL+"/v1/projects/test-project/secrets?secretId=s", `{"replication":{"automatic":{}}}`)
+	doRequest(t, http.MethodGet, ts.URL+"/v1/projects/test-project/secrets/s", "")
+	doRequest(t, http.MethodGet, ts.URL+"/v1/projects/test-project/secrets/missing", "")
+
+	resp, body := doRequest(t, http.MethodGet, ts.URL+"/admin/v1/auditLogEntries?log=data_access&method=GetSecret", "")
+	var list adminpb.ListAuditLogEntriesResponse
+	unmarshalBody(t, body, &list)
+	if resp.StatusCode != http.StatusOK || len(list.GetEntries()) != 2 {
+		t.Fatalf("ListAuditLogEntries = %d %s; want two GetSecret entries", resp.StatusCode, body)
+	}
+	missing := list.GetEntries()[1].GetFields()
+	payload := missing["protoPayload"].GetStructValue().GetFields()
+	if missing["logName"].GetStringValue() != "projects/test-project/logs/cloudaudit.googleapis.com%2Fdata_access" ||
+		missing["severity"].GetStringValue() != "ERROR" ||
+		payload["@type"].GetStringValue() != "type.googleapis.com/google.cloud.audit.AuditLog" {
+		t.Errorf("ListAuditLogEntries body = %s", body)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, ts.URL+"/admin/v1/auditLogEntries:clear", "")
+	var cleared adminpb.ClearAuditLogEntriesResponse
+	unmarshalBody(t, body, &cleared)
+	if resp.StatusCode != http.StatusOK || cleared.GetDeletedEntries() != 3 {
+		t.Errorf("ClearAuditLogEntries = %d %s", resp.StatusCode, body)
+	}
+	if resp, _ := doRequest(t, http.MethodGet, ts.URL+"/admin/v1/auditLogEntries?log=system_event", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("ListAuditLogEntries(system_event) status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestGateway_Tenants(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t), WithAdmin())
+	body := `{"replication":{"automatic":{}}}`
+
+	// The same secret in the default tenant, by URL prefix and by header
+	if resp, body := doRequest(t, http.MethodPost, ts.URL+"/v1/projects/p/secrets?secretId=s", body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateSecret status = %d: %s", resp.StatusCode, body)
+	}
+	if resp, body := doRequest(t, http.MethodPost, ts.URL+"/tenants/job-1/v1/projects/p/secrets?secretId=s", body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateSecret in job-1 status = %d: %s", resp.StatusCode, body)
+	}
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/v1/projects/p/secrets/s", nil)
+	req.Header.Set(TenantHeader, "job-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GetSecret with tenant header status = %d", resp.StatusCode)
+	}
+	if resp, _ := doRequest(t, http.MethodGet, ts.URL+"/tenants/job-2/v1/projects/p/secrets/s", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GetSecret in job-2 status = %d, want 404", resp.StatusCode)
+	}
+
+	// The admin API is served under the prefix too
+	resp, respBody := doRequest(t, http.MethodGet, ts.URL+"/tenants/job-1/admin/v1/stats", "")
+	var stats adminpb.Stats
+	unmarshalBody(t, respBody, &stats)
+	if resp.StatusCode != http.StatusOK || stats.GetSecrets() != 1 {
+		t.Errorf("GetStats in job-1 = %d %s", resp.StatusCode, respBody)
+	}
+	resp, respBody = doRequest(t, http.MethodGet, ts.URL+"/admin/v1/tenants", "")
+	var list adminpb.ListTenantsResponse
+	unmarshalBody(t, respBody, &list)
+	if resp.StatusCode != http.StatusOK || len(list.GetTenants()) != 2 {
+		t.Fatalf("ListTenants = %d %s; want job-1 and job-2", resp.StatusCode, respBody)
+	}
+	if resp, respBody := doRequest(t, http.MethodDelete, ts.URL+"/admin/v1/tenants/job-1", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("DeleteTenant status = %d: %s", resp.StatusCode, respBody)
+	}
+	if resp, _ := doRequest(t, http.MethodGet, ts.URL+"/tenants/job-1/v1/projects/p/secrets/s", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GetSecret in deleted job-1 status = %d, want 404", resp.StatusCode)
+	}
+
+	if resp, _ := doRequest(t, http.MethodGet, ts.URL+"/tenants/job%201/v1/projects/p/secrets/s", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GetSecret in invalid tenant status = %d, want 400", resp.StatusCode)
+	}
+	if resp, _ := doRequest(t, http.MethodGet, ts.URL+"/tenants/job-1", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /tenants/job-1 status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestGateway_AdminCheckpoints(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t), WithAdmin())
+
+	doRequest(t, http.MethodPost, ts.URL+"/v1/projects/p/secrets?secretId=s", `{"replication":{"automatic":{}}}`)
+	resp, body := doRequest(t, http.MethodPost, ts.URL+"/admin/v1/checkpoints", "")
+	var cp adminpb.Checkpoint
+	unmarshalBody(t, body, &cp)
+	if resp.StatusCode != http.StatusOK || cp.GetName() != "checkpoints/1" || cp.GetSecrets() != 1 {
+		t.Fatalf("CreateCheckpoint = %d %s", resp.StatusCode, body)
+	}
+
+	doRequest(t, http.MethodDelete, ts.URL+"/v1/projects/p/secrets/s", "")
+	doRequest(t, http.MethodPost, ts.URL+"/v1/projects/p/secrets?secretId=other", `{"replication":{"automatic":{}}}`)
+	if resp, body := doRequest(t, http.MethodPost, ts.URL+"/admin/v1/checkpoints/1:restore", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("RestoreCheckpoint status = %d: %s", resp.StatusCode, body)
+	}
+	if resp, _ := doRequest(t, http.MethodGet, ts.URL+"/v1/projects/p/secrets/s", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("GetSecret after restore status = %d, want 200", resp.StatusCode)
+	}
+	if resp, _ := doRequest(t, http.MethodGet, ts.URL+"/v1/projects/p/secrets/other", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GetSecret of secret created after the checkpoint status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, body = doRequest(t, http.MethodGet, ts.URL+"/admin/v1/checkpoints", "")
+	var list adminpb.ListCheckpointsResponse
+	unmarshalBody(t, body, &list)
+	if resp.StatusCode != http.StatusOK || len(list.GetCheckpoints()) != 1 {
+		t.Errorf("ListCheckpoints = %d %s", resp.StatusCode, body)
+	}
+	if resp, body := doRequest(t, http.MethodDelete, ts.URL+"/admin/v1/checkpoints/1", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("DeleteCheckpoint status = %d: %s", resp.StatusCode, body)
+	}
+	if resp, _ := doRequest(t, http.MethodPost, ts.URL+"/admin/v1/checkpoints/1:restore", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("RestoreCheckpoint of deleted checkpoint status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestGateway_BearerToken(t *testing.T) {
+	_, ts := startTestGatewayFor(t, startAdminBackend(t), WithAdmin())
+
+	resp, body := doRequest(t, http.MethodPost, ts.URL+"/admin/v1/tokens", `{"principal":"serviceAccount:ci@p.iam.gserviceaccount.com"}`)
+	var tok adminpb.Token
+	unmarshalBody(t, body, &tok)
+	if resp.StatusCode != http.StatusOK || tok.GetToken() == "" {
+		t.Fatalf("MintToken = %d %s", resp.StatusCode, body)
+	}
+
+	// The backend derives the caller's principal from the forwarded token
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/v1/projects/p/secrets/missing", nil)
+	req.Header.Set("Authorization", "Bearer "+tok.GetToken())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	resp, body = doRequest(t, http.MethodGet, ts.URL+"/admin/v1/auditLogEntries?method=GetSecret&principal=serviceAccount:ci@p.iam.gserviceaccount.com", "")
+	var list adminpb.ListAuditLogEntriesResponse
+	unmarshalBody(t, body, &list)
+	if resp.StatusCode != http.StatusOK || len(list.GetEntries()) != 1 {
+		t.Errorf("ListAuditLogEntries for the token's principal = %d %s; want one entry", resp.StatusCode, body)
+	}
+
+	if resp, body := doRequest(t, http.MethodPost, ts.URL+"/admin/v1/tokens", `{"principal":"ci"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("MintToken(ci) status = %d: %s", resp.StatusCode, body)
+	}
+}
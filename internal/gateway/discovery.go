@@ -0,0 +1,530 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// serviceDescriptor is the Secret Manager service definition the route table
+// binds to. Request and response schemas are derived from it.
+var serviceDescriptor = secretmanagerpb.File_google_cloud_secretmanager_v1_service_proto.Services().ByName("SecretManagerService")
+
+// methodDescriptor returns the RPC descriptor for a route.
+func (rt *route) methodDescriptor() protoreflect.MethodDescriptor {
+	return serviceDescriptor.Methods().ByName(protoreflect.Name(rt.rpc))
+}
+
+// resourcePath returns the Discovery resource path of the route, e.g.
+// ["projects", "secrets", "versions"].
+func (rt *route) resourcePath() []string {
+	var path []string
+	for i, seg := range rt.template.segments {
+		if i == 0 && seg.variable == "" {
+			continue // API version prefix
+		}
+		if seg.literal != "*" {
+			path = append(path, seg.literal)
+		}
+	}
+	return path
+}
+
+// methodName returns the Discovery method name of the route, e.g. "list" or
+// "addVersion".
+func (rt *route) methodName() string {
+	if rt.template.verb != "" {
+		return rt.template.verb
+	}
+	onCollection := rt.template.segments[len(rt.template.segments)-1].literal != "*"
+	switch rt.method {
+	case http.MethodGet:
+		if onCollection {
+			return "list"
+		}
+		return "get"
+	case http.MethodPost:
+		return "create"
+	default:
+		return strings.ToLower(rt.method)
+	}
+}
+
+// methodID returns the Discovery method ID, e.g. "secretmanager.projects.secrets.list".
+func (rt *route) methodID() string {
+	return "secretmanager." + strings.Join(rt.resourcePath(), ".") + "." + rt.methodName()
+}
+
+// discoveryPath renders the template in Discovery's "v1/{+name}:access" form.
+func (rt *route) discoveryPath() string {
+	var parts []string
+	seen := make(map[string]bool)
+	for _, seg := range rt.template.segments {
+		switch {
+		case seg.variable == "":
+			parts = append(parts, seg.literal)
+		case !seen[seg.variable]:
+			seen[seg.variable] = true
+			parts = append(parts, "{+"+paramName(seg.variable)+"}")
+		}
+	}
+	return rt.withVerb(strings.Join(parts, "/"))
+}
+
+// flatPath renders the template with one named parameter per wildcard, e.g.
+// "v1/projects/{projectsId}/secrets/{secretsId}". OpenAPI paths use this form.
+func (rt *route) flatPath() string {
+	parts := make([]string, len(rt.template.segments))
+	for i, seg := range rt.template.segments {
+		parts[i] = seg.literal
+		if seg.literal == "*" {
+			parts[i] = "{" + flatParamName(rt.template.segments, i) + "}"
+		}
+	}
+	return rt.withVerb(strings.Join(parts, "/"))
+}
+
+func (rt *route) withVerb(path string) string {
+	if rt.template.verb != "" {
+		return path + ":" + rt.template.verb
+	}
+	return path
+}
+
+// flatParamName names the wildcard at index i after the collection preceding it.
+func flatParamName(segments []templateSegment, i int) string {
+	if i > 0 && segments[i-1].literal != "*" {
+		return segments[i-1].literal + "Id"
+	}
+	return fmt.Sprintf("param%d", i)
+}
+
+// paramName returns the name used for a path variable: the last element of
+// its field path ("secret.name" is published as "name").
+func paramName(field string) string {
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		return field[i+1:]
+	}
+	return field
+}
+
+// variablePattern returns the regular expression a path variable must match.
+func variablePattern(v variable) string {
+	parts := make([]string, len(v.pattern))
+	for i, p := range v.pattern {
+		parts[i] = p
+		if p == "*" {
+			parts[i] = "[^/]+"
+		}
+	}
+	return "^" + strings.Join(parts, "/") + "$"
+}
+
+// queryParams returns the request fields carried in the query string: every
+// top-level field that is neither bound to the path nor to the body.
+func (rt *route) queryParams() []protoreflect.FieldDescriptor {
+	if rt.body == "*" {
+		return nil
+	}
+	bound := make(map[string]bool)
+	for _, v := range rt.template.variables() {
+		bound[strings.Split(v.field, ".")[0]] = true
+	}
+	if rt.body != "" {
+		bound[rt.body] = true
+	}
+
+	var params []protoreflect.FieldDescriptor
+	fields := rt.methodDescriptor().Input().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if bound[string(fd.Name())] || fd.IsList() || fd.IsMap() {
+			continue
+		}
+		if fd.Kind() == protoreflect.MessageKind && wellKnownSchema(fd.Message()) == nil {
+			continue
+		}
+		params = append(params, fd)
+	}
+	return params
+}
+
+// requestMessage returns the message sent as the request body, if any.
+func (rt *route) requestMessage() protoreflect.MessageDescriptor {
+	switch rt.body {
+	case "":
+		return nil
+	case "*":
+		return rt.methodDescriptor().Input()
+	default:
+		return rt.methodDescriptor().Input().Fields().ByName(protoreflect.Name(rt.body)).Message()
+	}
+}
+
+// schemaSet collects JSON schemas for messages reachable from the routes.
+type schemaSet struct {
+	schemas    map[string]map[string]any
+	ref        func(name string) map[string]any
+	protoNames bool // JSON properties use proto field names, see WithProtoFieldNames
+}
+
+func newSchemaSet(protoNames bool, ref func(name string) map[string]any) *schemaSet {
+	return &schemaSet{schemas: make(map[string]map[string]any), ref: ref, protoNames: protoNames}
+}
+
+// schemaName returns the published name of a message, e.g. "Secret" or
+// "ReplicationAutomatic" for nested messages.
+func schemaName(md protoreflect.MessageDescriptor) string {
+	name := strings.TrimPrefix(string(md.FullName()), string(md.ParentFile().Package())+".")
+	return strings.ReplaceAll(name, ".", "")
+}
+
+// jsonFieldName returns the name a field is encoded under in responses.
+func (ss *schemaSet) jsonFieldName(fd protoreflect.FieldDescriptor) string {
+	if ss.protoNames {
+		return string(fd.Name())
+	}
+	return fd.JSONName()
+}
+
+// wellKnownSchema returns the inline schema of well-known types encoded as
+// JSON strings, or nil for ordinary messages.
+func wellKnownSchema(md protoreflect.MessageDescriptor) map[string]any {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return map[string]any{"type": "string", "format": "google-datetime"}
+	case "google.protobuf.Duration":
+		return map[string]any{"type": "string", "format": "google-duration"}
+	case "google.protobuf.FieldMask":
+		return map[string]any{"type": "string", "format": "google-fieldmask"}
+	case "google.protobuf.Int64Value":
+		return map[string]any{"type": "string", "format": "int64"}
+	}
+	return nil
+}
+
+// message adds md (and every message it references) to the set and returns a
+// reference to it.
+func (ss *schemaSet) message(md protoreflect.MessageDescriptor) map[string]any {
+	if s := wellKnownSchema(md); s != nil {
+		return s
+	}
+	name := schemaName(md)
+	if _, ok := ss.schemas[name]; ok {
+		return ss.ref(name)
+	}
+
+	properties := make(map[string]any)
+	schema := map[string]any{
+		"id":         name,
+		"type":       "object",
+		"properties": properties,
+	}
+	ss.schemas[name] = schema
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		properties[ss.jsonFieldName(fd)] = ss.field(fd)
+	}
+	return ss.ref(name)
+}
+
+// field returns the schema of a single field.
+func (ss *schemaSet) field(fd protoreflect.FieldDescriptor) map[string]any {
+	if fd.IsMap() {
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": ss.scalar(fd.MapValue()),
+		}
+	}
+	if fd.IsList() {
+		return map[string]any{"type": "array", "items": ss.scalar(fd)}
+	}
+	return ss.scalar(fd)
+}
+
+// scalar returns the schema of a single (non-repeated) value of fd.
+func (ss *schemaSet) scalar(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return map[string]any{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return map[string]any{"type": "string", "enum": names}
+	default:
+		return ss.message(fd.Message())
+	}
+}
+
+// handleDiscovery serves the Google API Discovery document for the REST surface.
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	if v := r.URL.Query().Get("version"); v != "" && v != "v1" {
+		writeHTTPError(w, http.StatusNotFound, codes.NotFound, fmt.Sprintf("Discovery document for version %q not found", v))
+		return
+	}
+	writeJSON(w, discoveryDocument(rootURL(r), s.useProtoNames))
+}
+
+// handleOpenAPI serves an OpenAPI 3 document for the REST surface.
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, openAPIDocument(rootURL(r), s.useProtoNames))
+}
+
+// handleSwaggerUI serves a Swagger UI page for the OpenAPI document.
+func (s *Server) handleSwaggerUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, swaggerUIPage)
+}
+
+// rootURL returns the scheme and host the request was addressed to.
+func rootURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/"
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, codes.Internal, fmt.Sprintf("Failed to marshal response: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	_, _ = w.Write(data)
+}
+
+// discoveryDocument builds the Discovery document (discovery#restDescription)
+// for every route in the route table.
+func discoveryDocument(root string, protoNames bool) map[string]any {
+	schemas := newSchemaSet(protoNames, func(name string) map[string]any {
+		return map[string]any{"$ref": name}
+	})
+	resources := make(map[string]any)
+
+	for _, rt := range routes {
+		md := rt.methodDescriptor()
+
+		parameters := make(map[string]any)
+		var order []string
+		for _, v := range rt.template.variables() {
+			name := paramName(v.field)
+			parameters[name] = map[string]any{
+				"type":     "string",
+				"location": "path",
+				"required": true,
+				"pattern":  variablePattern(v),
+			}
+			order = append(order, name)
+		}
+		for _, fd := range rt.queryParams() {
+			param := schemas.field(fd)
+			param["location"] = "query"
+			parameters[fd.JSONName()] = param
+		}
+
+		method := map[string]any{
+			"id":             rt.methodID(),
+			"path":           rt.discoveryPath(),
+			"flatPath":       rt.flatPath(),
+			"httpMethod":     rt.method,
+			"parameters":     parameters,
+			"parameterOrder": order,
+			"response":       schemas.message(md.Output()),
+		}
+		if req := rt.requestMessage(); req != nil {
+			method["request"] = schemas.message(req)
+		}
+
+		// Nest the method under its resource path: resources.projects.resources.secrets...
+		level := resources
+		path := rt.resourcePath()
+		for i, name := range path {
+			res, ok := level[name].(map[string]any)
+			if !ok {
+				res = map[string]any{"resources": map[string]any{}, "methods": map[string]any{}}
+				level[name] = res
+			}
+			if i == len(path)-1 {
+				res["methods"].(map[string]any)[rt.methodName()] = method
+			}
+			level = res["resources"].(map[string]any)
+		}
+	}
+
+	return map[string]any{
+		"kind":             "discovery#restDescription",
+		"discoveryVersion": "v1",
+		"id":               "secretmanager:v1",
+		"name":             "secretmanager",
+		"version":          "v1",
+		"title":            "Secret Manager API",
+		"description":      "Stores sensitive data such as API keys, passwords, and certificates. Served by the GCP Secret Manager Emulator.",
+		"ownerDomain":      "google.com",
+		"ownerName":        "Google",
+		"protocol":         "rest",
+		"rootUrl":          root,
+		"servicePath":      "",
+		"baseUrl":          root,
+		"parameters":       systemParameters(),
+		"resources":        resources,
+		"schemas":          schemas.schemas,
+	}
+}
+
+// systemParameters describes the query parameters accepted by every method.
+func systemParameters() map[string]any {
+	return map[string]any{
+		"alt": map[string]any{
+			"type":             "string",
+			"location":         "query",
+			"default":          "json",
+			"enum":             []string{"json", "proto"},
+			"enumDescriptions": []string{"Responses with Content-Type of application/json", "Responses with Content-Type of application/x-protobuf"},
+		},
+		"prettyPrint": map[string]any{
+			"type":        "boolean",
+			"location":    "query",
+			"default":     "true",
+			"description": "Returns response with indentations and line breaks.",
+		},
+	}
+}
+
+// openAPIDocument builds an OpenAPI 3 document for every route in the route
+// table.
+func openAPIDocument(root string, protoNames bool) map[string]any {
+	schemas := newSchemaSet(protoNames, func(name string) map[string]any {
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	})
+	paths := make(map[string]any)
+
+	for _, rt := range routes {
+		md := rt.methodDescriptor()
+
+		var parameters []any
+		for i, seg := range rt.template.segments {
+			if seg.literal == "*" {
+				parameters = append(parameters, map[string]any{
+					"name":     flatParamName(rt.template.segments, i),
+					"in":       "path",
+					"required": true,
+					"schema":   map[string]any{"type": "string"},
+				})
+			}
+		}
+		for _, fd := range rt.queryParams() {
+			parameters = append(parameters, map[string]any{
+				"name":   fd.JSONName(),
+				"in":     "query",
+				"schema": schemas.field(fd),
+			})
+		}
+
+		op := map[string]any{
+			"operationId": rt.methodID(),
+			"tags":        []string{strings.Join(rt.resourcePath(), ".")},
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "Successful response",
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": schemas.message(md.Output())},
+					},
+				},
+				"default": map[string]any{"description": "Error response"},
+			},
+		}
+		if len(parameters) > 0 {
+			op["parameters"] = parameters
+		}
+		if req := rt.requestMessage(); req != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": schemas.message(req)},
+				},
+			}
+		}
+
+		path := "/" + rt.flatPath()
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+		item[strings.ToLower(rt.method)] = op
+	}
+
+	// OpenAPI does not know Discovery's "id" keyword.
+	components := make(map[string]any, len(schemas.schemas))
+	names := make([]string, 0, len(schemas.schemas))
+	for name := range schemas.schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schema := make(map[string]any)
+		for k, v := range schemas.schemas[name] {
+			if k != "id" {
+				schema[k] = v
+			}
+		}
+		components[name] = schema
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Secret Manager API",
+			"version": "v1",
+		},
+		"servers":    []any{map[string]any{"url": strings.TrimSuffix(root, "/")}},
+		"paths":      paths,
+		"components": map[string]any{"schemas": components},
+	}
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Secret Manager API - GCP Secret Manager Emulator</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
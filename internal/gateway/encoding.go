@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// protobufContentType is the media type of binary protobuf responses.
+const protobufContentType = "application/x-protobuf"
+
+// responseFormat describes how a response message is encoded, as selected by
+// the $alt / alt and prettyPrint system parameters and the Accept header.
+type responseFormat struct {
+	binary      bool // binary protobuf instead of JSON
+	pretty      bool // indented JSON
+	enumNumbers bool // enums as numbers ($alt=json;enum-encoding=int)
+}
+
+// negotiateFormat selects the response encoding for a request.
+//
+// Like googleapis, JSON is pretty-printed unless prettyPrint=false, and
+// "$alt=proto" (or "alt=proto", or an Accept header preferring
+// application/x-protobuf) selects binary protobuf.
+func negotiateFormat(r *http.Request) responseFormat {
+	query := r.URL.Query()
+	f := responseFormat{pretty: true}
+
+	if v := query.Get("prettyPrint"); v != "" {
+		if pretty, err := strconv.ParseBool(v); err == nil {
+			f.pretty = pretty
+		}
+	}
+
+	alt := query.Get("$alt")
+	if alt == "" {
+		alt = query.Get("alt")
+	}
+	if alt != "" {
+		kind, params, _ := strings.Cut(alt, ";")
+		f.binary = kind == "proto" || kind == "protobuf"
+		f.enumNumbers = strings.TrimSpace(params) == "enum-encoding=int"
+		return f
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case protobufContentType, "application/protobuf", "application/vnd.google.protobuf":
+			f.binary = true
+			return f
+		case "application/json", "*/*":
+			return f
+		}
+	}
+	return f
+}
+
+// jsonMarshaler returns the protojson options for the server and format.
+func (s *Server) jsonMarshaler(f responseFormat) protojson.MarshalOptions {
+	opts := protojson.MarshalOptions{
+		EmitUnpopulated: true,
+		UseProtoNames:   s.useProtoNames,
+		UseEnumNumbers:  f.enumNumbers,
+	}
+	if f.pretty {
+		opts.Multiline = true
+		opts.Indent = "  "
+	}
+	return opts
+}
+
+// writeProto writes a response message with the given status code, encoded
+// as negotiated for the request.
+func (s *Server) writeProto(w http.ResponseWriter, r *http.Request, code int, msg proto.Message) {
+	f := negotiateFormat(r)
+
+	var (
+		data        []byte
+		err         error
+		contentType = "application/json; charset=UTF-8"
+	)
+	if f.binary {
+		data, err = proto.Marshal(msg)
+		contentType = protobufContentType
+	} else {
+		data, err = s.jsonMarshaler(f).Marshal(msg)
+	}
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, codes.Internal, fmt.Sprintf("Failed to marshal response: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// createTestSecret creates projects/test-project/secrets/{id} through the gateway.
+func createTestSecret(t *testing.T, baseURL, id string) {
+	t.Helper()
+
+	resp, body := doRequest(t, http.MethodPost, baseURL+"/v1/projects/test-project/secrets?secretId="+id, `{"replication":{"automatic":{}}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateSecret status = %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestGateway_JSONFieldNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    string
+		notWant string
+	}{
+		{"CamelCaseByDefault", nil, `"createTime"`, `"create_time"`},
+		{"ProtoNamesCompat", []Option{WithProtoFieldNames(true)}, `"create_time"`, `"createTime"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ts := startTestGateway(t, tt.opts...)
+			createTestSecret(t, ts.URL, "names")
+
+			_, body := doRequest(t, http.MethodGet, ts.URL+"/v1/projects/test-project/secrets/names", "")
+			if !strings.Contains(body, tt.want) || strings.Contains(body, tt.notWant) {
+				t.Errorf("body = %s, want %s and not %s", body, tt.want, tt.notWant)
+			}
+		})
+	}
+}
+
+func TestGateway_PrettyPrint(t *testing.T) {
+	_, ts := startTestGateway(t)
+	createTestSecret(t, ts.URL, "pretty")
+	url := ts.URL + "/v1/projects/test-project/secrets/pretty"
+
+	_, body := doRequest(t, http.MethodGet, url, "")
+	if !strings.Contains(body, "\n") {
+		t.Errorf("default body = %q, want pretty-printed", body)
+	}
+
+	_, body = doRequest(t, http.MethodGet, url+"?prettyPrint=false", "")
+	if strings.Contains(body, "\n") {
+		t.Errorf("prettyPrint=false body = %q, want compact", body)
+	}
+}
+
+func TestGateway_ProtobufResponses(t *testing.T) {
+	_, ts := startTestGateway(t)
+	createTestSecret(t, ts.URL, "binary")
+	url := ts.URL + "/v1/projects/test-project/secrets/binary"
+
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+	}{
+		{"AltQueryParameter", "?$alt=proto", ""},
+		{"LegacyAltQueryParameter", "?alt=proto", ""},
+		{"AcceptHeader", "", "application/x-protobuf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, url+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("GetSecret failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if got := resp.Header.Get("Content-Type"); got != protobufContentType {
+				t.Fatalf("Content-Type = %q, want %q", got, protobufContentType)
+			}
+			data, _ := io.ReadAll(resp.Body)
+			var secret secretmanagerpb.Secret
+			if err := proto.Unmarshal(data, &secret); err != nil {
+				t.Fatalf("Failed to decode protobuf response: %v", err)
+			}
+			if secret.GetName() != "projects/test-project/secrets/binary" {
+				t.Errorf("Secret.Name = %q, want projects/test-project/secrets/binary", secret.GetName())
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/fault"
+)
+
+// errorBody is the googleapis JSON error envelope:
+//
+//	{"error": {"code": 404, "message": "...", "status": "NOT_FOUND", "details": [...]}}
+//
+// gcloud and the Google API client libraries parse this shape to surface
+// error messages and retry decisions.
+type errorBody struct {
+	Error errorStatus `json:"error"`
+}
+
+type errorStatus struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Status  string            `json:"status"`
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// writeError writes a gRPC error with the HTTP status GCP uses for its code,
+// including any google.rpc error details. A call dropped by fault injection
+// aborts the response, closing the caller's connection.
+func writeError(w http.ResponseWriter, err error) {
+	if fault.IsDropped(err) {
+		panic(http.ErrAbortHandler)
+	}
+	st := status.Convert(err)
+
+	var details []json.RawMessage
+	for _, detail := range st.Proto().GetDetails() {
+		if data, err := protojson.Marshal(detail); err == nil {
+			details = append(details, data)
+		}
+	}
+
+	writeErrorBody(w, httpStatusFromCode(st.Code()), st.Code(), st.Message(), details)
+}
+
+// writeHTTPError writes an error raised by the gateway itself (routing, body
+// decoding) in the googleapis error format.
+func writeHTTPError(w http.ResponseWriter, httpStatus int, c codes.Code, message string) {
+	writeErrorBody(w, httpStatus, c, message, nil)
+}
+
+func writeErrorBody(w http.ResponseWriter, httpStatus int, c codes.Code, message string, details []json.RawMessage) {
+	data, err := json.Marshal(errorBody{Error: errorStatus{
+		Code:    httpStatus,
+		Message: message,
+		Status:  code.Code(c).String(),
+		Details: details,
+	}})
+	if err != nil {
+		data = []byte(fmt.Sprintf(`{"error":{"code":500,"message":%q,"status":"INTERNAL"}}`, err.Error()))
+		httpStatus = http.StatusInternalServerError
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "application/json; charset=UTF-8")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(httpStatus)
+	_, _ = w.Write(data)
+}
+
+// httpStatusFromCode maps a gRPC status code to its HTTP equivalent, following
+// google.rpc.Code.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -3,63 +3,151 @@ package gateway
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
+	"crypto/tls"
 	"fmt"
-	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 
+	"cloud.google.com/go/iam/apiv1/iampb"
 	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/reflect/protoreflect"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/admin/adminpb"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/listen"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/origin"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/tlsutil"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
 )
 
 // Server represents the REST gateway server
 type Server struct {
-	grpcClient secretmanagerpb.SecretManagerServiceClient
-	httpServer *http.Server
-	conn       *grpc.ClientConn
+	grpcClient   secretmanagerpb.SecretManagerServiceClient
+	healthClient healthpb.HealthClient
+	admin        adminpb.AdminServiceClient
+	httpServer   *http.Server
+	conn         *grpc.ClientConn
+
+	cors              *CORSConfig
+	maxBodyBytes      int64
+	maxAdminBodyBytes int64
+	compression       bool
+	useProtoNames     bool
+	logger            *slog.Logger
+	metrics           *metrics.Metrics
+	handlers          map[string]http.Handler
+	tlsConfig         *tls.Config
+	backendTLS        *tls.Config
+	enableAdmin       bool
+	tokens            *token.Resolver
 }
 
 // NewServer creates a new REST gateway server that proxies to a gRPC server
-func NewServer(grpcAddr string) *Server {
+func NewServer(grpcAddr string, opts ...Option) *Server {
+	s := &Server{
+		maxBodyBytes:      DefaultMaxBodyBytes,
+		maxAdminBodyBytes: DefaultMaxAdminBodyBytes,
+		compression:       true,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	// Connect to gRPC server
+	transport := insecure.NewCredentials()
+	if s.backendTLS != nil {
+		transport = credentials.NewTLS(s.backendTLS)
+	}
 	conn, err := grpc.NewClient(
 		grpcAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(transport),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	)
 	if err != nil {
 		panic(fmt.Sprintf("failed to dial gRPC server: %v", err))
 	}
 
-	return &Server{
-		grpcClient: secretmanagerpb.NewSecretManagerServiceClient(conn),
-		conn:       conn,
+	s.conn = conn
+	s.grpcClient = secretmanagerpb.NewSecretManagerServiceClient(conn)
+	s.healthClient = healthpb.NewHealthClient(conn)
+	if s.enableAdmin {
+		s.admin = adminpb.NewAdminServiceClient(conn)
 	}
+	return s
 }
 
-// Start starts the REST gateway server on the specified address
+// Start starts the REST gateway server on the specified address: a TCP
+// address such as ":8080" or a Unix socket such as "unix:///tmp/sm.sock".
 func (s *Server) Start(ctx context.Context, addr string) error {
+	lis, err := listen.Listen(addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(lis)
+}
+
+// Serve serves the REST gateway on lis until Stop is called.
+func (s *Server) Serve(lis net.Listener) error {
+	s.httpServer = &http.Server{
+		Handler:   s.Handler(),
+		TLSConfig: s.tlsConfig,
+	}
+
+	if s.tlsConfig != nil {
+		// The certificate comes from TLSConfig
+		return s.httpServer.ServeTLS(lis, "", "")
+	}
+	return s.httpServer.Serve(lis)
+}
+
+// Handler returns the HTTP handler serving the REST API, the API description
+// documents and the health and readiness checks.
+func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// Register routes matching GCP's REST API
 	mux.HandleFunc("/v1/", s.handleRequest)
 
-	// Health check
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, `{"status":"healthy"}`)
-	})
+	// API descriptions generated from the route table
+	mux.HandleFunc("/$discovery/rest", s.handleDiscovery)
+	mux.HandleFunc("/discovery/v1/apis/secretmanager/v1/rest", s.handleDiscovery)
+	mux.HandleFunc("/openapi.json", s.handleOpenAPI)
+	mux.HandleFunc("/docs", s.handleSwaggerUI)
 
-	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: mux,
+	// Emulator administration, only when enabled
+	if s.admin != nil {
+		mux.HandleFunc(adminPathPrefix, s.handleAdmin)
+	}
+
+	// Liveness and readiness, backed by the gRPC health service
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/ready", s.handleReady)
+
+	// Additional endpoints such as /metrics
+	for pattern, h := range s.handlers {
+		mux.Handle(pattern, h)
 	}
 
-	return s.httpServer.ListenAndServe()
+	// otelhttp extracts the caller's W3C trace context; the otelgrpc client
+	// handler on the connection propagates it to the gRPC backend.
+	h := s.loggingMiddleware(s.metricsMiddleware(s.corsMiddleware(s.compressionMiddleware(s.requestDecodingMiddleware(mux)))))
+
+	// Every endpoint again under /tenants/{tenant}, acting in that tenant
+	return tenantMiddleware(otelhttp.NewHandler(h, "gateway", otelhttp.WithSpanNameFormatter(spanName)))
 }
 
 // Stop gracefully stops the REST gateway server
@@ -73,192 +161,139 @@ func (s *Server) Stop(ctx context.Context) error {
 	return nil
 }
 
-// handleRequest routes REST requests to appropriate gRPC calls
+// handleRequest routes REST requests to appropriate gRPC calls using the
+// route table.
 func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	// Parse path: /v1/projects/{project}/secrets/{secret}/versions/{version}
-	path := strings.TrimPrefix(r.URL.Path, "/v1/")
-
-	// Handle :verb suffixes (like :addVersion, :access, etc.)
-	// Split on both / and :
-	parts := strings.Split(path, "/")
-
-	// Set JSON content type
-	w.Header().Set("Content-Type", "application/json")
-
-	// Route based on path structure and HTTP method
-	if len(parts) >= 2 && parts[0] == "projects" {
-		project := fmt.Sprintf("projects/%s", parts[1])
-
-		// Secrets operations
-		if len(parts) == 3 && parts[2] == "secrets" {
-			switch r.Method {
-			case http.MethodGet:
-				s.listSecrets(ctx, w, r, project)
-			case http.MethodPost:
-				s.createSecret(ctx, w, r, project)
-			default:
-				http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
-			}
-			return
-		}
+	s.dispatch(w, r, routes)
+}
 
-		// AddVersion operation (handles /secrets/name:addVersion path structure)
-		// Check this BEFORE individual secret operations
-		if len(parts) == 4 && parts[2] == "secrets" && strings.HasSuffix(parts[3], ":addVersion") {
-			secretName := fmt.Sprintf("%s/secrets/%s", project, strings.TrimSuffix(parts[3], ":addVersion"))
-			if r.Method == http.MethodPost {
-				s.addSecretVersion(ctx, w, r, secretName)
-			} else {
-				http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
-			}
-			return
+// dispatch serves a request with the first route in table matching its path
+// and method.
+func (s *Server) dispatch(w http.ResponseWriter, r *http.Request, table []*route) {
+	// Set JSON content type and the headers googleapis sends with every response
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("Cache-Control", "private")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	var allowed []string
+	for _, rt := range table {
+		vars, ok := rt.template.match(r.URL.EscapedPath())
+		if !ok {
+			continue
 		}
-
-		// Individual secret operations
-		if len(parts) == 4 && parts[2] == "secrets" {
-			secretName := fmt.Sprintf("%s/secrets/%s", project, parts[3])
-			switch r.Method {
-			case http.MethodGet:
-				s.getSecret(ctx, w, r, secretName)
-			case http.MethodPatch:
-				s.updateSecret(ctx, w, r, secretName)
-			case http.MethodDelete:
-				s.deleteSecret(ctx, w, r, secretName)
-			default:
-				http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		if rt.method != r.Method {
+			if !slices.Contains(allowed, rt.method) {
+				allowed = append(allowed, rt.method)
 			}
-			return
+			continue
 		}
-
-		// Secret versions operations
-		if len(parts) == 5 && parts[4] == "versions" {
-			secretName := fmt.Sprintf("%s/secrets/%s", project, parts[3])
-			switch r.Method {
-			case http.MethodGet:
-				s.listSecretVersions(ctx, w, r, secretName)
-			default:
-				http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
-			}
-			return
+		// Forward the caller's identity for IAM checks: the verified client
+		// certificate under mTLS, otherwise the X-Emulator-Principal header,
+		// otherwise the bearer token the backend derives a principal from.
+		ctx := emulatorauth.InjectPrincipalToContext(r.Context(), tlsutil.PrincipalFromRequest(r))
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
 		}
-
-		// Individual version operations
-		if len(parts) == 6 && parts[4] == "versions" {
-			versionName := fmt.Sprintf("%s/secrets/%s/versions/%s", project, parts[3], parts[5])
-
-			// Check for :access suffix
-			if strings.HasSuffix(parts[5], ":access") {
-				versionName = strings.TrimSuffix(versionName, ":access")
-				s.accessSecretVersion(ctx, w, r, versionName)
-				return
-			}
-
-			// Check for :enable suffix
-			if strings.HasSuffix(parts[5], ":enable") {
-				versionName = strings.TrimSuffix(versionName, ":enable")
-				s.enableSecretVersion(ctx, w, r, versionName)
-				return
-			}
-
-			// Check for :disable suffix
-			if strings.HasSuffix(parts[5], ":disable") {
-				versionName = strings.TrimSuffix(versionName, ":disable")
-				s.disableSecretVersion(ctx, w, r, versionName)
-				return
-			}
-
-			// Check for :destroy suffix
-			if strings.HasSuffix(parts[5], ":destroy") {
-				versionName = strings.TrimSuffix(versionName, ":destroy")
-				s.destroySecretVersion(ctx, w, r, versionName)
-				return
-			}
-
-			switch r.Method {
-			case http.MethodGet:
-				s.getSecretVersion(ctx, w, r, versionName)
-			case http.MethodDelete:
-				s.destroySecretVersion(ctx, w, r, versionName)
-			default:
-				http.Error(w, `{"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
-			}
-			return
+		// Faults that drop the connection drop the REST caller's instead
+		ctx = origin.MarkGateway(ctx)
+		if tenant := r.Header.Get(TenantHeader); tenant != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, server.TenantMetadataKey, tenant)
 		}
+		rt.handler(s, ctx, w, r, vars)
+		return
 	}
 
-	http.Error(w, `{"error":"Not found"}`, http.StatusNotFound)
+	// The path names a resource, just not with this method
+	if len(allowed) > 0 {
+		slices.Sort(allowed)
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeHTTPError(w, http.StatusMethodNotAllowed, codes.Unimplemented, fmt.Sprintf("Method %s is not allowed for %s", r.Method, r.URL.Path))
+		return
+	}
+	writeHTTPError(w, http.StatusNotFound, codes.NotFound, fmt.Sprintf("The requested URL %s was not found on this server.", r.URL.Path))
 }
 
-// Helper to write protobuf response as JSON
-func writeProtoJSON(w http.ResponseWriter, msg interface{}) {
-	marshaler := protojson.MarshalOptions{
-		EmitUnpopulated: true,
-		UseProtoNames:   true,
+// queryInt32 returns an integer query parameter, or def if it is absent or malformed.
+func queryInt32(r *http.Request, key string, def int32) int32 {
+	v, err := strconv.ParseInt(r.URL.Query().Get(key), 10, 32)
+	if err != nil || v <= 0 {
+		return def
 	}
+	return int32(v)
+}
 
-	// Type assert to proto.Message
-	protoMsg, ok := msg.(interface{ ProtoReflect() protoreflect.Message })
-	if !ok {
-		http.Error(w, `{"error":"Failed to marshal response: not a proto message"}`, http.StatusInternalServerError)
-		return
+// updateMaskFromQuery parses the updateMask query parameter. Paths may use
+// lowerCamelCase, as gcloud sends them ("versionAliases"), or proto names.
+func updateMaskFromQuery(r *http.Request) *fieldmaskpb.FieldMask {
+	query := r.URL.Query()
+	mask := query.Get("updateMask")
+	if mask == "" {
+		mask = query.Get("update_mask")
+	}
+	if mask == "" {
+		return nil
 	}
 
-	data, err := marshaler.Marshal(protoMsg)
-	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"Failed to marshal response: %v"}`, err), http.StatusInternalServerError)
-		return
+	var paths []string
+	for _, path := range strings.Split(mask, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, snakeCase(path))
+		}
 	}
+	return &fieldmaskpb.FieldMask{Paths: paths}
+}
 
-	if _, err := w.Write(data); err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"Failed to write response: %v"}`, err), http.StatusInternalServerError)
+// snakeCase converts a lowerCamelCase field path to its proto name
+// ("versionAliases" -> "version_aliases"). Proto names are returned unchanged.
+func snakeCase(path string) string {
+	var b strings.Builder
+	for _, c := range path {
+		if c >= 'A' && c <= 'Z' {
+			b.WriteByte('_')
+			c += 'a' - 'A'
+		}
+		b.WriteRune(c)
 	}
+	return b.String()
 }
 
 // Secrets operations
 func (s *Server) listSecrets(ctx context.Context, w http.ResponseWriter, r *http.Request, parent string) {
 	req := &secretmanagerpb.ListSecretsRequest{
 		Parent:    parent,
-		PageSize:  100,
+		PageSize:  queryInt32(r, "pageSize", 100),
 		PageToken: r.URL.Query().Get("pageToken"),
+		Filter:    r.URL.Query().Get("filter"),
 	}
 
 	resp, err := s.grpcClient.ListSecrets(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) createSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, parent string) {
-	body, _ := io.ReadAll(r.Body)
-	defer r.Body.Close()
-
 	var secret secretmanagerpb.Secret
-	if err := protojson.Unmarshal(body, &secret); err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"Invalid JSON: %v"}`, err), http.StatusBadRequest)
+	if !decodeBody(w, r, &secret) {
 		return
 	}
 
-	secretID := r.URL.Query().Get("secretId")
-
 	req := &secretmanagerpb.CreateSecretRequest{
 		Parent:   parent,
-		SecretId: secretID,
+		SecretId: r.URL.Query().Get("secretId"),
 		Secret:   &secret,
 	}
 
 	resp, err := s.grpcClient.CreateSecret(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) getSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
@@ -266,103 +301,82 @@ func (s *Server) getSecret(ctx context.Context, w http.ResponseWriter, r *http.R
 
 	resp, err := s.grpcClient.GetSecret(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) updateSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
-	body, _ := io.ReadAll(r.Body)
-	defer r.Body.Close()
-
 	var secret secretmanagerpb.Secret
-	if err := protojson.Unmarshal(body, &secret); err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"Invalid JSON: %v"}`, err), http.StatusBadRequest)
+	if !decodeBody(w, r, &secret) {
 		return
 	}
 
 	secret.Name = name
 
 	req := &secretmanagerpb.UpdateSecretRequest{
-		Secret: &secret,
+		Secret:     &secret,
+		UpdateMask: updateMaskFromQuery(r),
 	}
 
 	resp, err := s.grpcClient.UpdateSecret(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) deleteSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
-	req := &secretmanagerpb.DeleteSecretRequest{Name: name}
+	req := &secretmanagerpb.DeleteSecretRequest{
+		Name: name,
+		Etag: r.URL.Query().Get("etag"),
+	}
 
-	_, err := s.grpcClient.DeleteSecret(ctx, req)
+	resp, err := s.grpcClient.DeleteSecret(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 // Secret version operations
 func (s *Server) addSecretVersion(ctx context.Context, w http.ResponseWriter, r *http.Request, parent string) {
-	body, _ := io.ReadAll(r.Body)
-	defer r.Body.Close()
-
-	var reqBody struct {
-		Payload struct {
-			Data string `json:"data"`
-		} `json:"payload"`
-	}
-
-	if err := json.Unmarshal(body, &reqBody); err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"Invalid JSON: %v"}`, err), http.StatusBadRequest)
+	var req secretmanagerpb.AddSecretVersionRequest
+	if !decodeBody(w, r, &req) {
 		return
 	}
+	req.Parent = parent
 
-	// Decode base64 data
-	data, err := base64.StdEncoding.DecodeString(reqBody.Payload.Data)
+	resp, err := s.grpcClient.AddSecretVersion(ctx, &req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"Invalid base64 data: %v"}`, err), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 
-	req := &secretmanagerpb.AddSecretVersionRequest{
-		Parent: parent,
-		Payload: &secretmanagerpb.SecretPayload{
-			Data: data,
-		},
-	}
-
-	resp, err := s.grpcClient.AddSecretVersion(ctx, req)
-	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
-		return
-	}
-
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) listSecretVersions(ctx context.Context, w http.ResponseWriter, r *http.Request, parent string) {
 	req := &secretmanagerpb.ListSecretVersionsRequest{
 		Parent:    parent,
-		PageSize:  100,
+		PageSize:  queryInt32(r, "pageSize", 100),
 		PageToken: r.URL.Query().Get("pageToken"),
+		Filter:    r.URL.Query().Get("filter"),
 	}
 
 	resp, err := s.grpcClient.ListSecretVersions(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) getSecretVersion(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
@@ -370,11 +384,11 @@ func (s *Server) getSecretVersion(ctx context.Context, w http.ResponseWriter, r
 
 	resp, err := s.grpcClient.GetSecretVersion(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusNotFound)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) accessSecretVersion(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
@@ -382,45 +396,105 @@ func (s *Server) accessSecretVersion(ctx context.Context, w http.ResponseWriter,
 
 	resp, err := s.grpcClient.AccessSecretVersion(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) enableSecretVersion(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
-	req := &secretmanagerpb.EnableSecretVersionRequest{Name: name}
+	var req secretmanagerpb.EnableSecretVersionRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	req.Name = name
 
-	resp, err := s.grpcClient.EnableSecretVersion(ctx, req)
+	resp, err := s.grpcClient.EnableSecretVersion(ctx, &req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) disableSecretVersion(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
-	req := &secretmanagerpb.DisableSecretVersionRequest{Name: name}
+	var req secretmanagerpb.DisableSecretVersionRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	req.Name = name
 
-	resp, err := s.grpcClient.DisableSecretVersion(ctx, req)
+	resp, err := s.grpcClient.DisableSecretVersion(ctx, &req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
 
 func (s *Server) destroySecretVersion(ctx context.Context, w http.ResponseWriter, r *http.Request, name string) {
-	req := &secretmanagerpb.DestroySecretVersionRequest{Name: name}
+	var req secretmanagerpb.DestroySecretVersionRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	req.Name = name
+
+	resp, err := s.grpcClient.DestroySecretVersion(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+// IAM policy operations
+func (s *Server) setIamPolicy(ctx context.Context, w http.ResponseWriter, r *http.Request, resource string) {
+	var req iampb.SetIamPolicyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	req.Resource = resource
+
+	resp, err := s.grpcClient.SetIamPolicy(ctx, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) getIamPolicy(ctx context.Context, w http.ResponseWriter, r *http.Request, resource string) {
+	req := &iampb.GetIamPolicyRequest{Resource: resource}
+	if v := queryInt32(r, "options.requestedPolicyVersion", 0); v > 0 {
+		req.Options = &iampb.GetPolicyOptions{RequestedPolicyVersion: v}
+	}
+
+	resp, err := s.grpcClient.GetIamPolicy(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeProto(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) testIamPermissions(ctx context.Context, w http.ResponseWriter, r *http.Request, resource string) {
+	var req iampb.TestIamPermissionsRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	req.Resource = resource
 
-	resp, err := s.grpcClient.DestroySecretVersion(ctx, req)
+	resp, err := s.grpcClient.TestIamPermissions(ctx, &req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%v"}`, err), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
-	writeProtoJSON(w, resp)
+	s.writeProto(w, r, http.StatusOK, resp)
 }
@@ -0,0 +1,385 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/grpc"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/health"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/listen"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
+)
+
+// startTestGateway starts an in-process gRPC backend and a REST gateway in
+// front of it.
+func startTestGateway(t *testing.T, opts ...Option) (*Server, *httptest.Server) {
+	t.Helper()
+
+	addr, _ := startTestBackend(t)
+	return startTestGatewayFor(t, addr, opts...)
+}
+
+// startTestBackend starts an in-process gRPC Secret Manager backend with the
+// health service registered and returns its address and health checker.
+func startTestBackend(t *testing.T, serverOpts ...grpc.ServerOption) (string, *health.Checker) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	mockServer, err := server.NewServer()
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	secretmanagerpb.RegisterSecretManagerServiceServer(grpcServer, mockServer)
+	checker := health.NewChecker()
+	checker.Register(grpcServer)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String(), checker
+}
+
+// startTestGatewayFor starts a REST gateway in front of the gRPC backend at addr.
+func startTestGatewayFor(t *testing.T, addr string, opts ...Option) (*Server, *httptest.Server) {
+	t.Helper()
+
+	gw := NewServer(addr, opts...)
+	httpServer := httptest.NewServer(gw.Handler())
+	t.Cleanup(func() {
+		httpServer.Close()
+		_ = gw.Stop(t.Context())
+	})
+
+	return gw, httpServer
+}
+
+// doRequest performs an HTTP request against the gateway and returns the
+// response and its body.
+func doRequest(t *testing.T, method, url, body string) (*http.Response, string) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return resp, string(data)
+}
+
+func TestGateway_SecretLifecycle(t *testing.T) {
+	_, ts := startTestGateway(t)
+	base := ts.URL + "/v1/projects/test-project/secrets"
+
+	resp, body := doRequest(t, http.MethodPost, base+"?secretId=db-password", `{"replication":{"automatic":{}}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateSecret status = %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, base+"/db-password:addVersion", `{"payload":{"data":"c2VjcmV0"}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("AddSecretVersion status = %d: %s", resp.StatusCode, body)
+	}
+
+	resp, body = doRequest(t, http.MethodGet, base+"/db-password/versions/latest:access", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("AccessSecretVersion status = %d: %s", resp.StatusCode, body)
+	}
+	if !strings.Contains(body, `"c2VjcmV0"`) {
+		t.Errorf("AccessSecretVersion body = %s, want payload c2VjcmV0", body)
+	}
+
+	resp, body = doRequest(t, http.MethodGet, base+"?pageSize=1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ListSecrets status = %d: %s", resp.StatusCode, body)
+	}
+	if !strings.Contains(body, "projects/test-project/secrets/db-password") {
+		t.Errorf("ListSecrets body = %s, want db-password", body)
+	}
+}
+
+func TestGateway_Discovery(t *testing.T) {
+	_, ts := startTestGateway(t)
+
+	resp, body := doRequest(t, http.MethodGet, ts.URL+"/$discovery/rest?version=v1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Discovery status = %d: %s", resp.StatusCode, body)
+	}
+
+	var doc struct {
+		Kind      string `json:"kind"`
+		RootURL   string `json:"rootUrl"`
+		Resources map[string]struct {
+			Resources map[string]struct {
+				Methods map[string]struct {
+					ID         string `json:"id"`
+					Path       string `json:"path"`
+					HTTPMethod string `json:"httpMethod"`
+				} `json:"methods"`
+			} `json:"resources"`
+		} `json:"resources"`
+		Schemas map[string]any `json:"schemas"`
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("Failed to decode discovery document: %v", err)
+	}
+
+	if doc.Kind != "discovery#restDescription" {
+		t.Errorf("kind = %q, want discovery#restDescription", doc.Kind)
+	}
+	if doc.RootURL != ts.URL+"/" {
+		t.Errorf("rootUrl = %q, want %q", doc.RootURL, ts.URL+"/")
+	}
+
+	addVersion := doc.Resources["projects"].Resources["secrets"].Methods["addVersion"]
+	if addVersion.ID != "secretmanager.projects.secrets.addVersion" || addVersion.Path != "v1/{+parent}:addVersion" || addVersion.HTTPMethod != http.MethodPost {
+		t.Errorf("addVersion = %+v, want POST v1/{+parent}:addVersion", addVersion)
+	}
+
+	for _, name := range []string{"Secret", "SecretVersion", "AccessSecretVersionResponse", "ListSecretsResponse"} {
+		if _, ok := doc.Schemas[name]; !ok {
+			t.Errorf("schema %q missing from discovery document", name)
+		}
+	}
+
+	resp, _ = doRequest(t, http.MethodGet, ts.URL+"/$discovery/rest?version=v2", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Discovery v2 status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestGateway_OpenAPICoversRouteTable(t *testing.T) {
+	_, ts := startTestGateway(t)
+
+	resp, body := doRequest(t, http.MethodGet, ts.URL+"/openapi.json", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("OpenAPI status = %d: %s", resp.StatusCode, body)
+	}
+
+	var doc struct {
+		OpenAPI string                               `json:"openapi"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("Failed to decode OpenAPI document: %v", err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("openapi = %q, want 3.x", doc.OpenAPI)
+	}
+
+	for _, rt := range routes {
+		op, ok := doc.Paths["/"+rt.flatPath()][strings.ToLower(rt.method)]
+		if !ok {
+			t.Errorf("%s %s missing from OpenAPI document", rt.method, rt.template.raw)
+			continue
+		}
+		if op["operationId"] != rt.methodID() {
+			t.Errorf("%s operationId = %v, want %s", rt.template.raw, op["operationId"], rt.methodID())
+		}
+	}
+}
+
+func TestGateway_RequestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	tokens := token.NewResolver(clock.Real{}, map[string]string{"ci-token": "serviceAccount:ci@p.iam.gserviceaccount.com"})
+	_, ts := startTestGateway(t, WithLogger(logger), WithTokens(tokens))
+	base := ts.URL + "/v1/projects/test-project/secrets"
+
+	createTestSecret(t, ts.URL, "logged")
+	buf.Reset()
+
+	req, err := http.NewRequest(http.MethodPost, base+"/logged:addVersion", strings.NewReader(`{"payload":{"data":"c3VwZXItc2VjcmV0"}}`))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	req.Header.Set("X-Emulator-Principal", "user:alice@example.com")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("AddSecretVersion failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if strings.Contains(buf.String(), "c3VwZXItc2VjcmV0") {
+		t.Fatalf("payload leaked into log: %s", buf.String())
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log entry is not JSON: %v: %s", err, buf.String())
+	}
+	want := map[string]any{
+		"method":    http.MethodPost,
+		"resource":  "projects/test-project/secrets/logged",
+		"principal": "user:alice@example.com",
+		"status":    float64(http.StatusOK),
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %v", k, entry[k], v)
+		}
+	}
+
+	// Bearer token callers are logged with the token's principal
+	buf.Reset()
+	req, err = http.NewRequest(http.MethodGet, base+"/logged", nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer ci-token")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GetSecret failed: %v", err)
+	}
+	resp.Body.Close()
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log entry is not JSON: %v: %s", err, buf.String())
+	}
+	if entry["principal"] != "serviceAccount:ci@p.iam.gserviceaccount.com" {
+		t.Errorf("principal of bearer token request = %v", entry["principal"])
+	}
+}
+
+func TestGateway_Metrics(t *testing.T) {
+	m := metrics.New()
+	_, ts := startTestGateway(t, WithMetrics(m), WithHandler("/metrics", m.Handler()))
+
+	createTestSecret(t, ts.URL, "measured")
+	doRequest(t, http.MethodGet, ts.URL+"/v1/projects/test-project/secrets/missing", "")
+	doRequest(t, http.MethodGet, ts.URL+"/tenants/job-1/v1/projects/test-project/secrets/missing", "")
+
+	resp, body := doRequest(t, http.MethodGet, ts.URL+"/metrics", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("/metrics status = %d", resp.StatusCode)
+	}
+	for _, want := range []string{
+		`secretmanager_emulator_http_requests_total{code="200",method="POST",rpc="CreateSecret"} 1`,
+		`secretmanager_emulator_http_requests_total{code="404",method="GET",rpc="GetSecret"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestGateway_HealthAndReadiness(t *testing.T) {
+	addr, checker := startTestBackend(t)
+	_, ts := startTestGatewayFor(t, addr)
+
+	resp, body := doRequest(t, http.MethodGet, ts.URL+"/health", "")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `"healthy"`) {
+		t.Errorf("/health = %d %s, want 200 healthy", resp.StatusCode, body)
+	}
+	resp, body = doRequest(t, http.MethodGet, ts.URL+"/ready", "")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `"ready"`) {
+		t.Errorf("/ready = %d %s, want 200 ready", resp.StatusCode, body)
+	}
+
+	// A failing dependency affects readiness but not liveness
+	checker.AddCheck("iam", func(context.Context) error { return errors.New("unreachable") })
+	resp, _ = doRequest(t, http.MethodGet, ts.URL+"/ready", "")
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("/ready status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	resp, _ = doRequest(t, http.MethodGet, ts.URL+"/health", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/health status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	checker.Shutdown()
+	resp, _ = doRequest(t, http.MethodGet, ts.URL+"/health", "")
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("/health after shutdown status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
+
+func TestGateway_UnixSockets(t *testing.T) {
+	dir := t.TempDir()
+
+	// gRPC backend on a Unix socket
+	grpcLis, err := listen.Listen("unix://" + filepath.Join(dir, "grpc.sock"))
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	mockServer, err := server.NewServer()
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	secretmanagerpb.RegisterSecretManagerServiceServer(grpcServer, mockServer)
+	go func() {
+		_ = grpcServer.Serve(grpcLis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	// REST gateway on another Unix socket
+	httpSock := filepath.Join(dir, "http.sock")
+	gw := NewServer(listen.DialTarget(grpcLis.Addr()))
+	errc := make(chan error, 1)
+	go func() {
+		errc <- gw.Start(t.Context(), "unix://"+httpSock)
+	}()
+	t.Cleanup(func() {
+		_ = gw.Stop(context.Background())
+		if err := <-errc; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("Start() error = %v", err)
+		}
+	})
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", httpSock)
+		},
+	}}
+	var resp *http.Response
+	for range 50 {
+		resp, err = client.Post("http://emulator/v1/projects/test-project/secrets?secretId=unix", "application/json", strings.NewReader(`{"replication":{"automatic":{}}}`))
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("CreateSecret over a Unix socket failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("CreateSecret status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// gcloudRecording is a recorded sequence of gcloud secrets requests, see
+// testdata/gcloud_secrets.json.
+type gcloudRecording struct {
+	Headers map[string]string `json:"headers"`
+	Steps   []struct {
+		Command string          `json:"command"`
+		Method  string          `json:"method"`
+		Path    string          `json:"path"`
+		Body    json.RawMessage `json:"body"`
+		Status  int             `json:"status"`
+		Expect  map[string]any  `json:"expect"`
+	} `json:"steps"`
+}
+
+// TestGateway_GcloudCompatibility replays the request shapes gcloud's secrets
+// command group sends and checks the response fields it reads.
+func TestGateway_GcloudCompatibility(t *testing.T) {
+	data, err := os.ReadFile("testdata/gcloud_secrets.json")
+	if err != nil {
+		t.Fatalf("Failed to read recording: %v", err)
+	}
+	var recording gcloudRecording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		t.Fatalf("Failed to parse recording: %v", err)
+	}
+
+	_, ts := startTestGateway(t)
+
+	for _, step := range recording.Steps {
+		var body io.Reader
+		if len(step.Body) > 0 {
+			body = bytes.NewReader(step.Body)
+		}
+		req, err := http.NewRequest(step.Method, ts.URL+step.Path, body)
+		if err != nil {
+			t.Fatalf("%s: NewRequest failed: %v", step.Command, err)
+		}
+		for k, v := range recording.Headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", step.Command, err)
+		}
+		respBody := readResponseBody(t, resp)
+
+		if resp.StatusCode != step.Status {
+			t.Fatalf("%s: status = %d, want %d: %s", step.Command, resp.StatusCode, step.Status, respBody)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("%s: Content-Type = %q, want application/json", step.Command, ct)
+		}
+
+		var decoded any
+		if err := json.Unmarshal(respBody, &decoded); err != nil {
+			t.Fatalf("%s: response is not JSON: %v: %s", step.Command, err, respBody)
+		}
+		for path, want := range step.Expect {
+			got, ok := lookupJSONPath(decoded, path)
+			switch {
+			case want == nil && ok && got != nil:
+				t.Errorf("%s: %s = %v, want absent", step.Command, path, got)
+			case want != nil && (!ok || fmt.Sprint(got) != fmt.Sprint(want)):
+				t.Errorf("%s: %s = %v, want %v", step.Command, path, got, want)
+			}
+		}
+	}
+}
+
+// readResponseBody reads a response body, decoding gzip since the recorded
+// headers request compression explicitly.
+func readResponseBody(t *testing.T, resp *http.Response) []byte {
+	t.Helper()
+	defer resp.Body.Close()
+
+	var r io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatalf("Invalid gzip response: %v", err)
+		}
+		r = zr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return data
+}
+
+// lookupJSONPath resolves a dotted path ("versions.0.name") in decoded JSON.
+func lookupJSONPath(v any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
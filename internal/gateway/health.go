@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/health"
+)
+
+// healthCheckTimeout bounds the gRPC health check behind /health and /ready.
+const healthCheckTimeout = 2 * time.Second
+
+// handleHealth serves liveness from the backend's grpc.health.v1 overall status.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.writeHealth(w, r, "", "healthy", "unhealthy")
+}
+
+// handleReady serves readiness from the backend's grpc.health.v1 status for
+// the Secret Manager service.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	s.writeHealth(w, r, health.ReadinessService, "ready", "not ready")
+}
+
+// writeHealth queries the backend health service and writes 200 with okText
+// when it is SERVING, or 503 with failText otherwise. A backend that does not
+// implement the health service is treated as serving once it answers.
+func (s *Server) writeHealth(w http.ResponseWriter, r *http.Request, service, okText, failText string) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	body := map[string]string{"status": okText}
+	code := http.StatusOK
+
+	resp, err := s.healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+	case err != nil:
+		code = http.StatusServiceUnavailable
+		body = map[string]string{"status": failText, "error": status.Convert(err).Message()}
+	case resp.GetStatus() != healthpb.HealthCheckResponse_SERVING:
+		code = http.StatusServiceUnavailable
+		body = map[string]string{"status": failText, "servingStatus": resp.GetStatus().String()}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/tlsutil"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
+)
+
+// resourceVars are the path variables naming the resource a route acts on,
+// in lookup order.
+var resourceVars = []string{"name", "secret.name", "parent", "resource"}
+
+// loggingMiddleware logs every request with its method, path, tenant,
+// resource name, principal, status and latency. Bodies are never logged.
+func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
+	if s.logger == nil {
+		return next
+	}
+	logger := s.logger
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		level := slog.LevelInfo
+		switch {
+		case sw.status >= 500:
+			level = slog.LevelError
+		case sw.status >= 400:
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(r.Context(), level, "http request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("tenant", r.Header.Get(TenantHeader)),
+			slog.String("resource", routeResource(r)),
+			slog.String("principal", s.requestPrincipal(r)),
+			slog.Int("status", sw.status),
+			slog.Int64("bytes", sw.bytes),
+			slog.Duration("latency", time.Since(start)),
+		)
+	})
+}
+
+// requestPrincipal returns the principal a request acts as, resolved as the
+// backend resolves the identity dispatch forwards: the verified client
+// certificate, then the X-Emulator-Principal header, then the bearer token.
+func (s *Server) requestPrincipal(r *http.Request) string {
+	if principal := tlsutil.PrincipalFromRequest(r); principal != "" {
+		return principal
+	}
+	bearer := token.BearerToken(r.Header.Values("Authorization"))
+	if s.tokens == nil || bearer == "" {
+		return ""
+	}
+	principal, _ := s.tokens.Principal(bearer) // Expired tokens are logged without one
+	return principal
+}
+
+// metricsMiddleware records the count and latency of every request, labeled
+// with the RPC its route maps to.
+func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
+	if s.metrics == nil {
+		return next
+	}
+	m := s.metrics
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		rpc := ""
+		if rt, _ := matchRoute(r); rt != nil {
+			rpc = rt.rpc
+		}
+		m.ObserveHTTPRequest(rpc, r.Method, sw.status, time.Since(start))
+	})
+}
+
+// spanName names a request's server span after its route template, keeping
+// span names low-cardinality (e.g. "GET /v1/{name=projects/*/secrets/*}").
+func spanName(_ string, r *http.Request) string {
+	if rt, _ := matchRoute(r); rt != nil {
+		return rt.method + " " + rt.template.raw
+	}
+	return r.Method
+}
+
+// matchRoute returns the route serving the request and its path variables,
+// or nil if none does.
+func matchRoute(r *http.Request) (*route, map[string]string) {
+	for _, rt := range routes {
+		if rt.method != r.Method {
+			continue
+		}
+		if vars, ok := rt.template.match(r.URL.EscapedPath()); ok {
+			return rt, vars
+		}
+	}
+	return nil, nil
+}
+
+// routeResource returns the resource named by the request path, or "" if the
+// path matches no route.
+func routeResource(r *http.Request) string {
+	_, vars := matchRoute(r)
+	for _, key := range resourceVars {
+		if v := vars[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// statusWriter records the status code and size of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if !sw.wroteHeader {
+		sw.status = code
+		sw.wroteHeader = true
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	sw.wroteHeader = true
+	n, err := sw.ResponseWriter.Write(p)
+	sw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
@@ -0,0 +1,283 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxBodyBytes is the default request body limit. GCP caps secret
+// payloads at 64 KiB, so 1 MiB leaves ample room for base64 and metadata.
+const DefaultMaxBodyBytes = 1 << 20
+
+// DefaultMaxAdminBodyBytes is the default body limit of /admin/ requests.
+// Imported snapshots hold every payload, so they get the room the gRPC
+// backend gives them (4 MiB of binary protobuf, more as JSON).
+const DefaultMaxAdminBodyBytes = 16 << 20
+
+// adminPathPrefix starts the paths of the admin API.
+const adminPathPrefix = "/admin/"
+
+// CORSConfig configures Cross-Origin Resource Sharing for browser clients.
+type CORSConfig struct {
+	// AllowedOrigins lists origins allowed to call the API. "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedHeaders lists request headers allowed in preflight. When empty, the
+	// headers requested by the browser are reflected.
+	AllowedHeaders []string
+	// ExposedHeaders lists response headers readable by browser scripts.
+	ExposedHeaders []string
+	// AllowCredentials allows cookies and Authorization headers on cross-origin requests.
+	AllowCredentials bool
+	// MaxAge is how long, in seconds, browsers may cache preflight results.
+	MaxAge int
+}
+
+// allowOrigin reports whether origin may call the API.
+func (c *CORSConfig) allowOrigin(origin string) bool {
+	return slices.Contains(c.AllowedOrigins, "*") || slices.Contains(c.AllowedOrigins, origin)
+}
+
+// corsMiddleware adds CORS headers for allowed origins and answers preflight requests.
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	if s.cors == nil {
+		return next
+	}
+	cfg := s.cors
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !cfg.allowOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if slices.Contains(cfg.AllowedOrigins, "*") && !cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if !preflight {
+			if len(cfg.ExposedHeaders) > 0 {
+				h.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS")
+		if len(cfg.AllowedHeaders) > 0 {
+			h.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+		} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+			h.Set("Access-Control-Allow-Headers", requested)
+		}
+		if cfg.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// requestDecodingMiddleware decompresses gzip/deflate request bodies and
+// enforces the body size limit on the decompressed stream.
+func (s *Server) requestDecodingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := s.bodyLimit(r)
+		if limit > 0 {
+			if r.ContentLength > limit {
+				writeBodyTooLarge(w, limit)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+		}
+
+		switch encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); encoding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				writeHTTPError(w, http.StatusBadRequest, codes.InvalidArgument, fmt.Sprintf("Invalid gzip body: %v", err))
+				return
+			}
+			r.Body = limitDecoded(w, zr, limit)
+		case "deflate":
+			r.Body = limitDecoded(w, flate.NewReader(r.Body), limit)
+		default:
+			writeHTTPError(w, http.StatusUnsupportedMediaType, codes.InvalidArgument, fmt.Sprintf("Unsupported Content-Encoding: %s", encoding))
+			return
+		}
+		if r.Header.Get("Content-Encoding") != "" {
+			r.Header.Del("Content-Encoding")
+			r.ContentLength = -1
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bodyLimit returns the body size limit of a request: the admin limit for
+// the admin API and the general limit for everything else.
+func (s *Server) bodyLimit(r *http.Request) int64 {
+	if strings.HasPrefix(r.URL.Path, adminPathPrefix) {
+		return s.maxAdminBodyBytes
+	}
+	return s.maxBodyBytes
+}
+
+// limitDecoded applies the body limit to a decompressed stream, guarding
+// against small compressed bodies that expand without bound.
+func limitDecoded(w http.ResponseWriter, rc io.ReadCloser, limit int64) io.ReadCloser {
+	if limit <= 0 {
+		return rc
+	}
+	return http.MaxBytesReader(w, rc, limit)
+}
+
+// readBody reads the request body, writing 413 if it exceeds the size limit
+// or 400 if it cannot be read. It reports whether the caller should continue.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeBodyTooLarge(w, maxErr.Limit)
+			return nil, false
+		}
+		writeHTTPError(w, http.StatusBadRequest, codes.InvalidArgument, fmt.Sprintf("Failed to read request body: %v", err))
+		return nil, false
+	}
+	return body, true
+}
+
+// decodeBody reads a JSON request body into msg. Both lowerCamelCase and proto
+// field names are accepted; an empty body leaves msg unset. It writes the
+// error response and reports false if the body cannot be read or parsed.
+func decodeBody(w http.ResponseWriter, r *http.Request, msg proto.Message) bool {
+	body, ok := readBody(w, r)
+	if !ok {
+		return false
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return true
+	}
+	if err := protojson.Unmarshal(body, msg); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, codes.InvalidArgument, fmt.Sprintf("Invalid JSON payload received. %v", err))
+		return false
+	}
+	return true
+}
+
+func writeBodyTooLarge(w http.ResponseWriter, limit int64) {
+	writeHTTPError(w, http.StatusRequestEntityTooLarge, codes.InvalidArgument, fmt.Sprintf("Request body exceeds %d bytes", limit))
+}
+
+// compressionMiddleware encodes responses with gzip or deflate when the
+// client accepts it.
+func (s *Server) compressionMiddleware(next http.Handler) http.Handler {
+	if !s.compression {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header.
+func negotiateEncoding(accept string) string {
+	var offered []string
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.ReplaceAll(strings.TrimSpace(params), " ", "") == "q=0" {
+			continue
+		}
+		offered = append(offered, strings.ToLower(strings.TrimSpace(name)))
+	}
+	for _, enc := range []string{"gzip", "deflate"} {
+		if slices.Contains(offered, enc) {
+			return enc
+		}
+	}
+	return ""
+}
+
+// compressWriter compresses the response body once the handler starts writing.
+// Responses without a body (e.g. 204) are left untouched.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	w           io.WriteCloser
+	wroteHeader bool
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	if code != http.StatusNoContent && code != http.StatusNotModified && cw.Header().Get("Content-Encoding") == "" {
+		cw.Header().Del("Content-Length")
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		if cw.encoding == "gzip" {
+			cw.w = gzip.NewWriter(cw.ResponseWriter)
+		} else {
+			cw.w, _ = flate.NewWriter(cw.ResponseWriter, flate.DefaultCompression)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.w == nil {
+		return cw.ResponseWriter.Write(p)
+	}
+	return cw.w.Write(p)
+}
+
+// Close flushes the compressed stream.
+func (cw *compressWriter) Close() error {
+	if cw.w == nil {
+		return nil
+	}
+	return cw.w.Close()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGateway_CORS(t *testing.T) {
+	_, ts := startTestGateway(t, WithCORS(CORSConfig{
+		AllowedOrigins: []string{"http://admin.local"},
+		MaxAge:         600,
+	}))
+	url := ts.URL + "/v1/projects/test-project/secrets"
+
+	t.Run("Preflight", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodOptions, url, nil)
+		req.Header.Set("Origin", "http://admin.local")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-type, x-emulator-principal")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Preflight failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "http://admin.local" {
+			t.Errorf("Access-Control-Allow-Origin = %q, want http://admin.local", got)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Headers"); got != "content-type, x-emulator-principal" {
+			t.Errorf("Access-Control-Allow-Headers = %q, want requested headers", got)
+		}
+		if got := resp.Header.Get("Access-Control-Max-Age"); got != "600" {
+			t.Errorf("Access-Control-Max-Age = %q, want 600", got)
+		}
+	})
+
+	t.Run("DisallowedOrigin", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Origin", "http://evil.local")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+		}
+	})
+}
+
+func TestGateway_Compression(t *testing.T) {
+	_, ts := startTestGateway(t)
+	base := ts.URL + "/v1/projects/test-project/secrets"
+
+	// gzip-encoded request body
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(`{"replication":{"automatic":{}}}`))
+	_ = zw.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, base+"?secretId=compressed", &buf)
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("CreateSecret failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateSecret status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	// gzip-encoded response (set explicitly so the transport does not decode it)
+	req, _ = http.NewRequest(http.MethodGet, base+"/compressed", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GetSecret failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("Invalid gzip response: %v", err)
+	}
+	body, _ := io.ReadAll(zr)
+	if !strings.Contains(string(body), "projects/test-project/secrets/compressed") {
+		t.Errorf("GetSecret body = %s, want secret name", body)
+	}
+}
+
+func TestGateway_BodyLimits(t *testing.T) {
+	_, ts := startTestGateway(t, WithMaxBodyBytes(64))
+	url := ts.URL + "/v1/projects/test-project/secrets?secretId=big"
+
+	t.Run("TooLarge", func(t *testing.T) {
+		resp, body := doRequest(t, http.MethodPost, url, `{"labels":{"k":"`+strings.Repeat("v", 128)+`"}}`)
+		if resp.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Errorf("status = %d, want %d: %s", resp.StatusCode, http.StatusRequestEntityTooLarge, body)
+		}
+	})
+
+	t.Run("CompressedTooLarge", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(`{"labels":{"k":"` + strings.Repeat("v", 4096) + `"}}`))
+		_ = zw.Close()
+		if buf.Len() > 64 {
+			t.Fatalf("compressed body is %d bytes, want <= 64 for this test", buf.Len())
+		}
+
+		req, _ := http.NewRequest(http.MethodPost, url, &buf)
+		req.Header.Set("Content-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusRequestEntityTooLarge)
+		}
+	})
+
+	t.Run("UnsupportedEncoding", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader("{}"))
+		req.Header.Set("Content-Encoding", "br")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnsupportedMediaType {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusUnsupportedMediaType)
+		}
+	})
+}
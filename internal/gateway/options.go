@@ -0,0 +1,113 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/token"
+)
+
+// Option configures the REST gateway.
+type Option func(*Server)
+
+// WithCORS enables CORS, including preflight (OPTIONS) handling.
+func WithCORS(cfg CORSConfig) Option {
+	return func(s *Server) {
+		s.cors = &cfg
+	}
+}
+
+// WithMaxBodyBytes limits the size of request bodies, after decompression.
+// Larger requests are rejected with 413 Request Entity Too Large.
+func WithMaxBodyBytes(n int64) Option {
+	return func(s *Server) {
+		s.maxBodyBytes = n
+	}
+}
+
+// WithMaxAdminBodyBytes limits the size of admin API request bodies, such as
+// imported snapshots, after decompression. They are not subject to
+// WithMaxBodyBytes.
+func WithMaxAdminBodyBytes(n int64) Option {
+	return func(s *Server) {
+		s.maxAdminBodyBytes = n
+	}
+}
+
+// WithCompression enables or disables gzip/deflate response compression.
+// Compressed request bodies are always accepted.
+func WithCompression(enabled bool) Option {
+	return func(s *Server) {
+		s.compression = enabled
+	}
+}
+
+// WithProtoFieldNames encodes JSON responses with the original proto field
+// names (create_time) instead of GCP's lowerCamelCase (createTime). This
+// restores the encoding used by earlier emulator releases.
+func WithProtoFieldNames(enabled bool) Option {
+	return func(s *Server) {
+		s.useProtoNames = enabled
+	}
+}
+
+// WithLogger logs every REST request to logger. Request and response bodies
+// are never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithMetrics records the count and latency of every REST request in m.
+// It does not serve m; mount m.Handler() with WithHandler or on an admin port.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+// WithHandler serves h at pattern alongside the REST API, e.g. "/metrics".
+func WithHandler(pattern string, h http.Handler) Option {
+	return func(s *Server) {
+		if s.handlers == nil {
+			s.handlers = make(map[string]http.Handler)
+		}
+		s.handlers[pattern] = h
+	}
+}
+
+// WithTLS serves HTTPS with cfg. When cfg requires client certificates, the
+// verified certificate's principal is forwarded to the gRPC backend instead
+// of the X-Emulator-Principal header.
+func WithTLS(cfg *tls.Config) Option {
+	return func(s *Server) {
+		s.tlsConfig = cfg
+	}
+}
+
+// WithBackendTLS dials the gRPC backend over TLS with cfg instead of
+// plaintext.
+func WithBackendTLS(cfg *tls.Config) Option {
+	return func(s *Server) {
+		s.backendTLS = cfg
+	}
+}
+
+// WithAdmin serves the emulator's admin API under /admin/v1, proxied to the
+// AdminService on the gRPC backend. The backend must register it as well.
+func WithAdmin() Option {
+	return func(s *Server) {
+		s.enableAdmin = true
+	}
+}
+
+// WithTokens resolves the principal of requests authenticated with a bearer
+// token through r for request logs, as the backend does for IAM checks.
+func WithTokens(r *token.Resolver) Option {
+	return func(s *Server) {
+		s.tokens = r
+	}
+}
@@ -0,0 +1,260 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// routeHandler serves a single REST binding. vars holds the path variables
+// captured by the route's template, keyed by request field path (e.g. "name",
+// "parent" or "secret.name").
+type routeHandler func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string)
+
+// route is one HTTP binding of a Secret Manager RPC.
+//
+// The route table is the single source of truth for the REST surface: the
+// router dispatches from it and the Discovery and OpenAPI documents are
+// generated from it, so the published specs cannot drift from what is served.
+type route struct {
+	method   string        // HTTP method
+	template *pathTemplate // google.api.http path template
+	rpc      string        // Secret Manager RPC name, e.g. "AccessSecretVersion"
+	body     string        // request body mapping: "", "*" or a field name
+	handler  routeHandler
+}
+
+// routes lists every REST binding served by the gateway. It is derived from
+// the google.api.http annotations of the Secret Manager service, including
+// additional_bindings (e.g. the regional projects/*/locations/* variants).
+var routes = buildRoutes(serviceDescriptor, rpcHandlers)
+
+// rpcHandlers maps each Secret Manager RPC to the handler serving its REST
+// bindings.
+var rpcHandlers = map[string]routeHandler{
+	"ListSecrets": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.listSecrets(ctx, w, r, vars["parent"])
+	},
+	"CreateSecret": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.createSecret(ctx, w, r, vars["parent"])
+	},
+	"GetSecret": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.getSecret(ctx, w, r, vars["name"])
+	},
+	"UpdateSecret": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.updateSecret(ctx, w, r, vars["secret.name"])
+	},
+	"DeleteSecret": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.deleteSecret(ctx, w, r, vars["name"])
+	},
+	"AddSecretVersion": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.addSecretVersion(ctx, w, r, vars["parent"])
+	},
+	"ListSecretVersions": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.listSecretVersions(ctx, w, r, vars["parent"])
+	},
+	"GetSecretVersion": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.getSecretVersion(ctx, w, r, vars["name"])
+	},
+	"AccessSecretVersion": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.accessSecretVersion(ctx, w, r, vars["name"])
+	},
+	"EnableSecretVersion": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.enableSecretVersion(ctx, w, r, vars["name"])
+	},
+	"DisableSecretVersion": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.disableSecretVersion(ctx, w, r, vars["name"])
+	},
+	"DestroySecretVersion": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.destroySecretVersion(ctx, w, r, vars["name"])
+	},
+	"SetIamPolicy": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.setIamPolicy(ctx, w, r, vars["resource"])
+	},
+	"GetIamPolicy": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.getIamPolicy(ctx, w, r, vars["resource"])
+	},
+	"TestIamPermissions": func(s *Server, ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+		s.testIamPermissions(ctx, w, r, vars["resource"])
+	},
+}
+
+// buildRoutes derives a route table from a service's HTTP annotations.
+func buildRoutes(service protoreflect.ServiceDescriptor, handlers map[string]routeHandler) []*route {
+	var table []*route
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		rule, ok := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		handler, ok := handlers[string(md.Name())]
+		if !ok {
+			panic(fmt.Sprintf("no REST handler for %s", md.FullName()))
+		}
+		for _, binding := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+			method, tmpl := httpRulePattern(binding)
+			table = append(table, newRoute(method, tmpl, string(md.Name()), binding.GetBody(), handler))
+		}
+	}
+	return table
+}
+
+func newRoute(method, template, rpc, body string, handler routeHandler) *route {
+	return &route{
+		method:   method,
+		template: mustParseTemplate(template),
+		rpc:      rpc,
+		body:     body,
+		handler:  handler,
+	}
+}
+
+// httpRulePattern returns the HTTP method and path template of a binding.
+func httpRulePattern(rule *annotations.HttpRule) (string, string) {
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, p.Get
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, p.Post
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, p.Put
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Custom:
+		return p.Custom.GetKind(), p.Custom.GetPath()
+	}
+	panic(fmt.Sprintf("unsupported HTTP rule pattern %T", rule.GetPattern()))
+}
+
+// pathTemplate is a parsed google.api.http path template such as
+// "/v1/{name=projects/*/secrets/*}:access".
+type pathTemplate struct {
+	raw      string
+	segments []templateSegment
+	verb     string
+}
+
+// templateSegment is a single "/"-separated element of a path template.
+// Segments that belong to a variable binding carry the variable's field path.
+type templateSegment struct {
+	literal  string // literal text, or "*" for a single-segment wildcard
+	variable string // field path of the enclosing variable, if any
+}
+
+// variable is a field bound by a path template.
+type variable struct {
+	field   string
+	pattern []string // segments of the variable's pattern, e.g. ["projects", "*"]
+}
+
+func mustParseTemplate(tmpl string) *pathTemplate {
+	t, err := parseTemplate(tmpl)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// parseTemplate parses the subset of the google.api.http template syntax used
+// by the Secret Manager API: literal segments, "*" wildcards, "{field=pattern}"
+// variables and a trailing ":verb".
+func parseTemplate(tmpl string) (*pathTemplate, error) {
+	if !strings.HasPrefix(tmpl, "/") {
+		return nil, fmt.Errorf("path template %q must start with /", tmpl)
+	}
+	t := &pathTemplate{raw: tmpl}
+
+	path := tmpl[1:]
+	if i := strings.LastIndex(path, ":"); i >= 0 && !strings.Contains(path[i:], "/") && !strings.Contains(path[i:], "}") {
+		t.verb = path[i+1:]
+		path = path[:i]
+	}
+
+	for len(path) > 0 {
+		if path[0] == '{' {
+			end := strings.Index(path, "}")
+			if end < 0 {
+				return nil, fmt.Errorf("path template %q has an unterminated variable", tmpl)
+			}
+			field, pattern, ok := strings.Cut(path[1:end], "=")
+			if !ok {
+				pattern = "*"
+			}
+			for _, lit := range strings.Split(pattern, "/") {
+				t.segments = append(t.segments, templateSegment{literal: lit, variable: field})
+			}
+			path = strings.TrimPrefix(path[end+1:], "/")
+			continue
+		}
+		seg, rest, _ := strings.Cut(path, "/")
+		t.segments = append(t.segments, templateSegment{literal: seg})
+		path = rest
+	}
+	return t, nil
+}
+
+// variables returns the variables bound by the template, in path order.
+func (t *pathTemplate) variables() []variable {
+	var vars []variable
+	for _, seg := range t.segments {
+		if seg.variable == "" {
+			continue
+		}
+		if len(vars) == 0 || vars[len(vars)-1].field != seg.variable {
+			vars = append(vars, variable{field: seg.variable})
+		}
+		vars[len(vars)-1].pattern = append(vars[len(vars)-1].pattern, seg.literal)
+	}
+	return vars
+}
+
+// match reports whether the escaped request path matches the template and
+// returns the captured variables. Segments are split before being unescaped,
+// so an encoded "/" or ":" inside a resource ID never acts as a separator.
+func (t *pathTemplate) match(escapedPath string) (map[string]string, bool) {
+	path := strings.TrimPrefix(escapedPath, "/")
+
+	last := path[strings.LastIndex(path, "/")+1:]
+	verb := ""
+	if i := strings.LastIndex(last, ":"); i >= 0 {
+		verb = last[i+1:]
+		path = path[:len(path)-len(last)+i]
+	}
+	if verb != t.verb {
+		return nil, false
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != len(t.segments) {
+		return nil, false
+	}
+
+	vars := make(map[string]string)
+	for i, seg := range t.segments {
+		part, err := url.PathUnescape(parts[i])
+		if err != nil || part == "" {
+			return nil, false
+		}
+		if seg.literal != "*" && seg.literal != part {
+			return nil, false
+		}
+		if seg.variable != "" {
+			if v, ok := vars[seg.variable]; ok {
+				vars[seg.variable] = v + "/" + part
+			} else {
+				vars[seg.variable] = part
+			}
+		}
+	}
+	return vars, true
+}
@@ -0,0 +1,156 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRoutes_CoverEveryHTTPBinding(t *testing.T) {
+	want := 0
+	methods := serviceDescriptor.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		rule := proto.GetExtension(methods.Get(i).Options(), annotations.E_Http).(*annotations.HttpRule)
+		if rule == nil {
+			continue
+		}
+		want += 1 + len(rule.GetAdditionalBindings())
+	}
+
+	if len(routes) != want {
+		t.Errorf("len(routes) = %d, want %d bindings", len(routes), want)
+	}
+}
+
+func TestPathTemplate_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		path     string
+		wantVars map[string]string
+	}{
+		{
+			name:     "Collection",
+			template: "/v1/{parent=projects/*}/secrets",
+			path:     "/v1/projects/p/secrets",
+			wantVars: map[string]string{"parent": "projects/p"},
+		},
+		{
+			name:     "Verb",
+			template: "/v1/{name=projects/*/secrets/*/versions/*}:access",
+			path:     "/v1/projects/p/secrets/s/versions/latest:access",
+			wantVars: map[string]string{"name": "projects/p/secrets/s/versions/latest"},
+		},
+		{
+			name:     "NestedField",
+			template: "/v1/{secret.name=projects/*/secrets/*}",
+			path:     "/v1/projects/p/secrets/s",
+			wantVars: map[string]string{"secret.name": "projects/p/secrets/s"},
+		},
+		{
+			name:     "EncodedCharacters",
+			template: "/v1/{name=projects/*/secrets/*}",
+			path:     "/v1/projects/p/secrets/a%2Fb%3Ac",
+			wantVars: map[string]string{"name": "projects/p/secrets/a/b:c"},
+		},
+		{
+			name:     "MissingVerb",
+			template: "/v1/{name=projects/*/secrets/*/versions/*}:access",
+			path:     "/v1/projects/p/secrets/s/versions/1",
+		},
+		{
+			name:     "VerbOnWrongSegment",
+			template: "/v1/{name=projects/*/secrets/*/versions/*}:access",
+			path:     "/v1/projects/p/secrets/s:access",
+		},
+		{
+			name:     "UnexpectedVerb",
+			template: "/v1/{name=projects/*/secrets/*}",
+			path:     "/v1/projects/p/secrets/s:frobnicate",
+		},
+		{
+			name:     "TrailingSlash",
+			template: "/v1/{parent=projects/*}/secrets",
+			path:     "/v1/projects/p/secrets/",
+		},
+		{
+			name:     "EmptySegment",
+			template: "/v1/{name=projects/*/secrets/*}",
+			path:     "/v1/projects//secrets/s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, ok := mustParseTemplate(tt.template).match(tt.path)
+			if ok != (tt.wantVars != nil) {
+				t.Fatalf("match(%q) ok = %v, want %v", tt.path, ok, tt.wantVars != nil)
+			}
+			for k, v := range tt.wantVars {
+				if vars[k] != v {
+					t.Errorf("vars[%q] = %q, want %q", k, vars[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestGateway_NotFoundAndMethodNotAllowed(t *testing.T) {
+	_, ts := startTestGateway(t)
+	base := ts.URL + "/v1/projects/test-project"
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantAllow  string
+	}{
+		{"WrongMethodOnCollection", http.MethodPut, "/secrets", http.StatusMethodNotAllowed, "GET, POST"},
+		{"WrongMethodOnSecret", http.MethodPost, "/secrets/s", http.StatusMethodNotAllowed, "DELETE, GET, PATCH"},
+		{"WrongMethodOnVerb", http.MethodPost, "/secrets/s/versions/1:access", http.StatusMethodNotAllowed, "GET"},
+		{"AccessOnSecret", http.MethodGet, "/secrets/s:access", http.StatusNotFound, ""},
+		{"UnsupportedVerb", http.MethodPost, "/secrets/s/versions/1:frobnicate", http.StatusNotFound, ""},
+		{"TrailingSlash", http.MethodGet, "/secrets/", http.StatusNotFound, ""},
+		{"UnknownCollection", http.MethodGet, "/widgets", http.StatusNotFound, ""},
+		{"MissingSecret", http.MethodGet, "/secrets/missing", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := doRequest(t, tt.method, base+tt.path, "")
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.wantStatus, body)
+			}
+			if got := resp.Header.Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestGateway_LocationBindings(t *testing.T) {
+	_, ts := startTestGateway(t)
+	base := ts.URL + "/v1/projects/test-project/locations/us-central1/secrets"
+
+	resp, body := doRequest(t, http.MethodPost, base+"?secretId=regional", `{}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("CreateSecret status = %d: %s", resp.StatusCode, body)
+	}
+	if !strings.Contains(body, "projects/test-project/locations/us-central1/secrets/regional") {
+		t.Errorf("CreateSecret body = %s, want regional secret name", body)
+	}
+
+	resp, body = doRequest(t, http.MethodPost, base+"/regional:addVersion", `{"payload":{"data":"dmFsdWU="}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("AddSecretVersion status = %d: %s", resp.StatusCode, body)
+	}
+
+	resp, body = doRequest(t, http.MethodGet, base+"/regional/versions/1:access", "")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `"dmFsdWU="`) {
+		t.Fatalf("AccessSecretVersion status = %d: %s", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// TenantHeader selects the tenant a REST request acts in. The gateway
+// forwards it to the backend as server.TenantMetadataKey.
+const TenantHeader = "X-Emulator-Tenant"
+
+// tenantPathPrefix starts paths that select a tenant, e.g.
+// /tenants/job-42/v1/projects/p/secrets.
+const tenantPathPrefix = "/tenants/"
+
+// tenantMiddleware serves /tenants/{tenant}/... by passing the rest of the
+// path to next with the tenant header set, so that clients can be pointed at
+// a tenant by their endpoint alone. It runs before the instrumentation, so
+// that tenant requests are matched to their routes like any other.
+func tenantMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, tenantPathPrefix)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		tenant, _, ok := strings.Cut(rest, "/")
+		if !ok {
+			writeHTTPError(w, http.StatusNotFound, codes.NotFound, fmt.Sprintf("The requested URL %s was not found on this server.", r.URL.Path))
+			return
+		}
+		if err := server.ValidateTenant(tenant); err != nil {
+			writeHTTPError(w, http.StatusBadRequest, codes.InvalidArgument, status.Convert(err).Message())
+			return
+		}
+
+		// Valid tenant names need no escaping, so the prefix has the same
+		// length in the raw path
+		n := len(tenantPathPrefix) + len(tenant)
+		r = r.Clone(r.Context())
+		r.URL.Path = r.URL.Path[n:]
+		if r.URL.RawPath != "" {
+			r.URL.RawPath = r.URL.RawPath[n:]
+		}
+		r.Header.Set(TenantHeader, tenant)
+		next.ServeHTTP(w, r)
+	})
+}
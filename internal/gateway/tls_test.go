@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/tlsutil"
+)
+
+// principalRecorder records the principal of every RPC the backend serves.
+func principalRecorder(principals chan<- string) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		select {
+		case principals <- emulatorauth.ExtractPrincipalFromContext(ctx):
+		default:
+		}
+		return handler(ctx, req)
+	})
+}
+
+func TestGateway_ForwardsPrincipalHeader(t *testing.T) {
+	principals := make(chan string, 1)
+	addr, _ := startTestBackend(t, principalRecorder(principals))
+	_, ts := startTestGatewayFor(t, addr)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/projects/test-project/secrets", nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	req.Header.Set(emulatorauth.PrincipalHeaderKey, "user:alice@example.com")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("ListSecrets failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := <-principals; got != "user:alice@example.com" {
+		t.Errorf("backend principal = %q, want user:alice@example.com", got)
+	}
+}
+
+func TestGateway_TLS(t *testing.T) {
+	caOut := filepath.Join(t.TempDir(), "ca.pem")
+	creds, err := tlsutil.Load(tlsutil.Options{SelfSigned: true, CAOut: caOut})
+	if err != nil {
+		t.Fatalf("tlsutil.Load() error = %v", err)
+	}
+
+	// Both hops are encrypted: client to gateway and gateway to backend
+	addr, _ := startTestBackend(t, creds.ServerOptions()...)
+	gw := NewServer(addr, WithTLS(creds.Server), WithBackendTLS(creds.Gateway))
+	ts := httptest.NewUnstartedServer(gw.Handler())
+	ts.TLS = creds.Server
+	ts.StartTLS()
+	t.Cleanup(func() {
+		ts.Close()
+		_ = gw.Stop(t.Context())
+	})
+
+	caPEM, err := os.ReadFile(caOut)
+	if err != nil {
+		t.Fatalf("CA bundle not written: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caPEM)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+
+	url := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1) + "/v1/projects/test-project/secrets?secretId=tls"
+	resp, err := client.Post(url, "application/json", strings.NewReader(`{"replication":{"automatic":{}}}`))
+	if err != nil {
+		t.Fatalf("CreateSecret over TLS failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("CreateSecret status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	// Plaintext clients are refused
+	if resp, err := http.Get(strings.Replace(ts.URL, "https://", "http://", 1) + "/v1/projects/test-project/secrets"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Error("plaintext request succeeded against a TLS listener")
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+)
+
+func TestGateway_TracePropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	addr, _ := startTestBackend(t, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	_, ts := startTestGatewayFor(t, addr)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	// Tenant requests are named after their route like any other
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/tenants/job-1/v1/projects/test-project/secrets?secretId=traced", strings.NewReader(`{"replication":{"automatic":{}}}`))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("CreateSecret failed: %v", err)
+	}
+	resp.Body.Close()
+	if err := provider.ForceFlush(t.Context()); err != nil {
+		t.Fatalf("ForceFlush failed: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID().String() != traceID {
+			t.Errorf("span %q trace ID = %s, want %s", span.Name, span.SpanContext.TraceID(), traceID)
+		}
+		names[span.Name] = true
+	}
+	for _, want := range []string{
+		"POST /v1/{parent=projects/*}/secrets",
+		"google.cloud.secretmanager.v1.SecretManagerService/CreateSecret",
+		"Storage.CreateSecret",
+	} {
+		if !names[want] {
+			t.Errorf("span %q missing; got %v", want, names)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package health implements grpc.health.v1.Health with liveness and
+// readiness semantics for the emulator.
+//
+// Two services are reported:
+//
+//   - "" (the overall server) is the liveness status: SERVING until shutdown
+//     begins.
+//   - "google.cloud.secretmanager.v1.SecretManagerService" is the readiness
+//     status: SERVING only while every registered readiness check passes,
+//     e.g. the IAM emulator is reachable in strict mode.
+//
+// Checks are evaluated when asked rather than cached, so a probe always sees
+// the current state. The REST gateway's /health and /ready endpoints query
+// the same service over gRPC.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// ReadinessService is the health service name reporting readiness.
+const ReadinessService = "google.cloud.secretmanager.v1.SecretManagerService"
+
+// DefaultWatchInterval is how often Watch re-evaluates the checks.
+const DefaultWatchInterval = time.Second
+
+// Check reports whether a dependency is ready; a non-nil error means it is not.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker evaluates readiness checks and serves grpc.health.v1.Health.
+type Checker struct {
+	healthpb.UnimplementedHealthServer
+
+	mu            sync.RWMutex
+	checks        []namedCheck
+	shuttingDown  atomic.Bool
+	watchInterval time.Duration
+}
+
+// NewChecker creates a Checker with no readiness checks; it reports ready
+// until checks are added.
+func NewChecker() *Checker {
+	return &Checker{watchInterval: DefaultWatchInterval}
+}
+
+// AddCheck registers a readiness check. Checks may be added at any time,
+// e.g. once a persistence backend has been configured.
+func (c *Checker) AddCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Register registers the Health service on a gRPC server.
+func (c *Checker) Register(s grpc.ServiceRegistrar) {
+	healthpb.RegisterHealthServer(s, c)
+}
+
+// Shutdown marks both services NOT_SERVING so load balancers and probes stop
+// routing to the server while it drains.
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Live reports whether the server is alive (not shutting down).
+func (c *Checker) Live() bool {
+	return !c.shuttingDown.Load()
+}
+
+// Ready evaluates every readiness check. It returns the failing checks by
+// name; the server is ready when the map is empty.
+func (c *Checker) Ready(ctx context.Context) map[string]error {
+	failures := make(map[string]error)
+	if c.shuttingDown.Load() {
+		failures["shutdown"] = status.Error(codes.Unavailable, "server is shutting down")
+		return failures
+	}
+
+	c.mu.RLock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.RUnlock()
+
+	for _, nc := range checks {
+		if err := nc.check(ctx); err != nil {
+			failures[nc.name] = err
+		}
+	}
+	return failures
+}
+
+// servingStatus returns the status of a health service, or false if the
+// service is unknown.
+func (c *Checker) servingStatus(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	switch service {
+	case "":
+		if c.Live() {
+			return healthpb.HealthCheckResponse_SERVING, true
+		}
+		return healthpb.HealthCheckResponse_NOT_SERVING, true
+	case ReadinessService:
+		failures := c.Ready(ctx)
+		if len(failures) == 0 {
+			return healthpb.HealthCheckResponse_SERVING, true
+		}
+		names := make([]string, 0, len(failures))
+		for name := range failures {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			slog.WarnContext(ctx, "readiness check failed", "check", name, "error", failures[name])
+		}
+		return healthpb.HealthCheckResponse_NOT_SERVING, true
+	}
+	return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+}
+
+// Check implements grpc.health.v1.Health.Check.
+func (c *Checker) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := c.servingStatus(ctx, req.GetService())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// List implements grpc.health.v1.Health.List.
+func (c *Checker) List(ctx context.Context, _ *healthpb.HealthListRequest) (*healthpb.HealthListResponse, error) {
+	resp := &healthpb.HealthListResponse{Statuses: make(map[string]*healthpb.HealthCheckResponse)}
+	for _, service := range []string{"", ReadinessService} {
+		st, _ := c.servingStatus(ctx, service)
+		resp.Statuses[service] = &healthpb.HealthCheckResponse{Status: st}
+	}
+	return resp, nil
+}
+
+// Watch implements grpc.health.v1.Health.Watch, sending the current status
+// and then every change until the client goes away.
+func (c *Checker) Watch(req *healthpb.HealthCheckRequest, stream grpc.ServerStreamingServer[healthpb.HealthCheckResponse]) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(c.watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		st, _ := c.servingStatus(ctx, req.GetService())
+		if st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
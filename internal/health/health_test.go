@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// startHealthServer serves checker over gRPC and returns a client for it.
+func startHealthServer(t *testing.T, checker *Checker) healthpb.HealthClient {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := grpc.NewServer()
+	checker.Register(s)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func checkStatus(t *testing.T, client healthpb.HealthClient, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q) error = %v", service, err)
+	}
+	return resp.GetStatus()
+}
+
+func TestChecker_LivenessAndReadiness(t *testing.T) {
+	checker := NewChecker()
+	client := startHealthServer(t, checker)
+
+	if got := checkStatus(t, client, ""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("liveness = %v, want SERVING", got)
+	}
+	if got := checkStatus(t, client, ReadinessService); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("readiness = %v, want SERVING", got)
+	}
+
+	var iamErr error = errors.New("IAM emulator unreachable")
+	checker.AddCheck("iam", func(context.Context) error { return iamErr })
+
+	if got := checkStatus(t, client, ReadinessService); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("readiness with failing check = %v, want NOT_SERVING", got)
+	}
+	if got := checkStatus(t, client, ""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("liveness with failing check = %v, want SERVING", got)
+	}
+	if failures := checker.Ready(context.Background()); failures["iam"] == nil {
+		t.Errorf("Ready() = %v, want iam failure", failures)
+	}
+
+	// Checks are evaluated on every probe
+	iamErr = nil
+	if got := checkStatus(t, client, ReadinessService); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("readiness after recovery = %v, want SERVING", got)
+	}
+
+	checker.Shutdown()
+	for _, service := range []string{"", ReadinessService} {
+		if got := checkStatus(t, client, service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("%q after Shutdown = %v, want NOT_SERVING", service, got)
+		}
+	}
+}
+
+func TestChecker_UnknownService(t *testing.T) {
+	client := startHealthServer(t, NewChecker())
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown.Service"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Check(unknown) error = %v, want NotFound", err)
+	}
+}
+
+func TestChecker_Watch(t *testing.T) {
+	checker := NewChecker()
+	checker.watchInterval = 10 * time.Millisecond
+	client := startHealthServer(t, checker)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: ReadinessService})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("first Watch status = %v, %v; want SERVING", resp.GetStatus(), err)
+	}
+
+	checker.Shutdown()
+	resp, err = stream.Recv()
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("Watch status after Shutdown = %v, %v; want NOT_SERVING", resp.GetStatus(), err)
+	}
+}
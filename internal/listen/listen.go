@@ -0,0 +1,120 @@
+// Package listen opens the emulator's listeners from address strings and
+// announces the addresses they are bound to.
+//
+// An address is either a TCP address ("localhost:9090", ":0",
+// "tcp://0.0.0.0:9090") or a Unix socket ("unix:///tmp/sm.sock",
+// "unix:sm.sock"). Unix sockets let parallel test runs on one host avoid TCP
+// port allocation entirely; ":0" lets the kernel pick a free port. Either
+// way, Announce prints the bound address so wrappers can discover it.
+package listen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Listen opens a listener on addr. A stale Unix socket file left behind by a
+// process that exited without cleaning up is removed; a socket another
+// process is still serving on is an error.
+func Listen(addr string) (net.Listener, error) {
+	network, address := Parse(addr)
+	if network == "unix" {
+		if err := removeStaleSocket(address); err != nil {
+			return nil, err
+		}
+	}
+	lis, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return lis, nil
+}
+
+// Parse splits addr into a network ("tcp" or "unix") and a network address.
+func Parse(addr string) (network, address string) {
+	switch {
+	case strings.HasPrefix(addr, "unix://"):
+		return "unix", strings.TrimPrefix(addr, "unix://")
+	case strings.HasPrefix(addr, "unix:"):
+		return "unix", strings.TrimPrefix(addr, "unix:")
+	case strings.HasPrefix(addr, "tcp://"):
+		return "tcp", strings.TrimPrefix(addr, "tcp://")
+	}
+	return "tcp", addr
+}
+
+// Address formats a bound address: "unix:///tmp/sm.sock" for Unix sockets
+// and "host:port" for TCP.
+func Address(a net.Addr) string {
+	if a.Network() == "unix" {
+		return "unix://" + a.String()
+	}
+	return a.String()
+}
+
+// DialTarget returns a gRPC dial target reaching a listener bound to a.
+// Wildcard TCP addresses are dialed on loopback.
+func DialTarget(a net.Addr) string {
+	if a.Network() == "unix" {
+		return Address(a)
+	}
+	tcp, ok := a.(*net.TCPAddr)
+	if !ok || !tcp.IP.IsUnspecified() {
+		return a.String()
+	}
+	return net.JoinHostPort("localhost", fmt.Sprint(tcp.Port))
+}
+
+// Announcement is the machine-readable line Announce writes.
+type Announcement struct {
+	Event    string `json:"event"`
+	Protocol string `json:"protocol"`
+	Network  string `json:"network"`
+	Address  string `json:"address"`
+}
+
+// Announce writes one JSON line describing a bound listener, e.g.
+//
+//	{"event":"listening","protocol":"grpc","network":"tcp","address":"[::]:40123"}
+//
+// protocol is "grpc" or "http".
+func Announce(w io.Writer, protocol string, lis net.Listener) error {
+	line, err := json.Marshal(Announcement{
+		Event:    "listening",
+		Protocol: protocol,
+		Network:  lis.Addr().Network(),
+		Address:  Address(lis.Addr()),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", line)
+	return err
+}
+
+// removeStaleSocket removes the socket file at path if nothing accepts
+// connections on it.
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is in use by another process", path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,113 @@
+package listen
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		addr, network, address string
+	}{
+		{":9090", "tcp", ":9090"},
+		{"localhost:0", "tcp", "localhost:0"},
+		{"tcp://0.0.0.0:9090", "tcp", "0.0.0.0:9090"},
+		{"unix:///tmp/sm.sock", "unix", "/tmp/sm.sock"},
+		{"unix:sm.sock", "unix", "sm.sock"},
+	}
+	for _, tt := range tests {
+		network, address := Parse(tt.addr)
+		if network != tt.network || address != tt.address {
+			t.Errorf("Parse(%q) = %q, %q; want %q, %q", tt.addr, network, address, tt.network, tt.address)
+		}
+	}
+}
+
+func TestListen_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sm.sock")
+
+	lis, err := Listen("unix://" + path)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	if got, want := Address(lis.Addr()), "unix://"+path; got != want {
+		t.Errorf("Address() = %q, want %q", got, want)
+	}
+	if got := DialTarget(lis.Addr()); got != "unix://"+path {
+		t.Errorf("DialTarget() = %q, want unix://%s", got, path)
+	}
+
+	// A socket that is being served on is not taken over
+	if _, err := Listen("unix://" + path); err == nil {
+		t.Error("expected an error listening on a socket in use")
+	}
+	lis.Close()
+
+	// A stale socket file is replaced
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("stale socket file missing: %v", err)
+	}
+	lis, err = Listen("unix://" + path)
+	if err != nil {
+		t.Fatalf("Listen() over a stale socket error = %v", err)
+	}
+	lis.Close()
+
+	// Regular files are never removed
+	file := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(file, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen("unix://" + file); err == nil {
+		t.Error("expected an error listening on a regular file")
+	}
+}
+
+func TestDialTarget_TCP(t *testing.T) {
+	lis, err := Listen(":0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer lis.Close()
+
+	port := lis.Addr().(*net.TCPAddr).Port
+	if got, want := DialTarget(lis.Addr()), net.JoinHostPort("localhost", strconv.Itoa(port)); got != want {
+		t.Errorf("DialTarget() = %q, want %q", got, want)
+	}
+}
+
+func TestAnnounce(t *testing.T) {
+	lis, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer lis.Close()
+
+	var buf bytes.Buffer
+	if err := Announce(&buf, "grpc", lis); err != nil {
+		t.Fatalf("Announce() error = %v", err)
+	}
+
+	var got Announcement
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("announcement is not JSON: %v: %s", err, buf.String())
+	}
+	want := Announcement{Event: "listening", Protocol: "grpc", Network: "tcp", Address: lis.Addr().String()}
+	if got != want {
+		t.Errorf("Announce() = %+v, want %+v", got, want)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		t.Error("announcement is not newline-terminated")
+	}
+}
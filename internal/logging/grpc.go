@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// LevelForCode returns the level a request completing with code is logged at:
+// info for success, warn for caller errors and error for server faults.
+func LevelForCode(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// UnaryServerInterceptor logs every unary RPC.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(ctx, logger, info.FullMethod, req, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs every streaming RPC once it completes.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logRPC(ss.Context(), logger, info.FullMethod, nil, err, time.Since(start))
+		return err
+	}
+}
+
+func logRPC(ctx context.Context, logger *slog.Logger, method string, req any, err error, latency time.Duration) {
+	code := status.Code(err)
+	level := LevelForCode(code)
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("resource", ResourceName(req)),
+		slog.String("principal", emulatorauth.ExtractPrincipalFromContext(ctx)),
+		slog.String("code", code.String()),
+		slog.Duration("latency", latency),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	if msg, ok := req.(proto.Message); ok && logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs, slog.Any("request", Message(msg)))
+	}
+	logger.LogAttrs(ctx, level, "grpc request", attrs...)
+}
@@ -0,0 +1,50 @@
+// Package logging provides structured, leveled logging for the emulator
+// binaries and request logging for the gRPC and REST front ends.
+//
+// Request logs carry the method, resource name, principal, status code and
+// latency. At debug level the request message is included as well, with every
+// bytes field (secret payload data) redacted.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Redacted replaces payload bytes in logged messages.
+const Redacted = "[REDACTED]"
+
+// ParseLevel parses a log level name: debug, info, warn (or warning) or error.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+}
+
+// New creates a logger writing to w at the given level. format is "text"
+// (logfmt-style key=value pairs) or "json".
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (want text or json)", format)
+}
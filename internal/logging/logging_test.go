@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		level, format string
+		wantErr       bool
+	}{
+		{"debug", "text", false},
+		{"INFO", "json", false},
+		{"warning", "", false},
+		{"error", "json", false},
+		{"verbose", "text", true},
+		{"info", "xml", true},
+	}
+	for _, tt := range tests {
+		_, err := New(&bytes.Buffer{}, tt.level, tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("New(%q, %q) error = %v, wantErr %v", tt.level, tt.format, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNew_HonorsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", "text")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	if strings.Contains(buf.String(), "hidden") {
+		t.Errorf("info message logged at warn level: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "shown") {
+		t.Errorf("warn message missing: %s", buf.String())
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	tests := []struct {
+		req  any
+		want string
+	}{
+		{&secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/s"}, "projects/p/secrets/s"},
+		{&secretmanagerpb.AddSecretVersionRequest{Parent: "projects/p/secrets/s"}, "projects/p/secrets/s"},
+		{&secretmanagerpb.UpdateSecretRequest{Secret: &secretmanagerpb.Secret{Name: "projects/p/secrets/s"}}, "projects/p/secrets/s"},
+		{&secretmanagerpb.UpdateSecretRequest{}, ""},
+		{"not a message", ""},
+	}
+	for _, tt := range tests {
+		if got := ResourceName(tt.req); got != tt.want {
+			t.Errorf("ResourceName(%T) = %q, want %q", tt.req, got, tt.want)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "debug", "json")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	interceptor := UnaryServerInterceptor(logger)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:alice@example.com"))
+	req := &secretmanagerpb.AddSecretVersionRequest{
+		Parent:  "projects/p/secrets/s",
+		Payload: &secretmanagerpb.SecretPayload{Data: []byte("super-secret-value")},
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/google.cloud.secretmanager.v1.SecretManagerService/AddSecretVersion"}
+
+	_, err = interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "Secret [projects/p/secrets/s] not found")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("interceptor error = %v, want the handler's error", err)
+	}
+
+	if strings.Contains(buf.String(), "super-secret-value") || strings.Contains(buf.String(), "c3VwZXItc2VjcmV0LXZhbHVl") {
+		t.Fatalf("payload bytes leaked into log: %s", buf.String())
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log entry is not JSON: %v: %s", err, buf.String())
+	}
+	want := map[string]any{
+		"level":     "WARN",
+		"method":    info.FullMethod,
+		"resource":  "projects/p/secrets/s",
+		"principal": "user:alice@example.com",
+		"code":      "NotFound",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %v", k, entry[k], v)
+		}
+	}
+	if _, ok := entry["latency"]; !ok {
+		t.Error("latency missing from log entry")
+	}
+	payload, _ := entry["request"].(map[string]any)["payload"].(map[string]any)
+	if payload["data"] != Redacted {
+		t.Errorf("request.payload.data = %v, want %s", payload["data"], Redacted)
+	}
+}
+
+func TestUnaryServerInterceptor_InfoOmitsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	interceptor := UnaryServerInterceptor(logger)
+
+	req := &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/s"}
+	info := &grpc.UnaryServerInfo{FullMethod: "/google.cloud.secretmanager.v1.SecretManagerService/GetSecret"}
+	_, _ = interceptor(context.Background(), req, info, func(ctx context.Context, req any) (any, error) {
+		return &secretmanagerpb.Secret{}, nil
+	})
+
+	if !strings.Contains(buf.String(), `"code":"OK"`) {
+		t.Errorf("log entry missing OK code: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), `"request"`) {
+		t.Errorf("request logged below debug level: %s", buf.String())
+	}
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"log/slog"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// resourceFields are the request fields naming the resource an RPC acts on,
+// in lookup order. UpdateSecret carries it in secret.name.
+var resourceFields = []protoreflect.Name{"name", "parent", "resource"}
+
+// ResourceName returns the resource a request message acts on, or "" if the
+// message has no resource field.
+func ResourceName(msg any) string {
+	m, ok := msg.(proto.Message)
+	if !ok || m == nil {
+		return ""
+	}
+	rm := m.ProtoReflect()
+	fields := rm.Descriptor().Fields()
+	for _, name := range resourceFields {
+		if fd := fields.ByName(name); fd != nil && fd.Kind() == protoreflect.StringKind && !fd.IsList() {
+			if v := rm.Get(fd).String(); v != "" {
+				return v
+			}
+		}
+	}
+	if fd := fields.ByName("secret"); fd != nil && fd.Kind() == protoreflect.MessageKind && rm.Has(fd) {
+		return ResourceName(rm.Get(fd).Message().Interface())
+	}
+	return ""
+}
+
+// Message returns a log value for msg with every bytes field replaced by
+// Redacted, so secret payloads never reach the log.
+func Message(msg proto.Message) slog.Value {
+	if msg == nil {
+		return slog.AnyValue(nil)
+	}
+	return slog.AnyValue(redactMessage(msg.ProtoReflect()))
+}
+
+// redactMessage converts a message to nested maps keyed by JSON field name.
+func redactMessage(m protoreflect.Message) map[string]any {
+	out := make(map[string]any)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			items := make([]any, list.Len())
+			for i := range items {
+				items[i] = redactValue(fd, list.Get(i))
+			}
+			out[fd.JSONName()] = items
+		case fd.IsMap():
+			entries := make(map[string]any)
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				entries[k.String()] = redactValue(fd.MapValue(), mv)
+				return true
+			})
+			out[fd.JSONName()] = entries
+		default:
+			out[fd.JSONName()] = redactValue(fd, v)
+		}
+		return true
+	})
+	return out
+}
+
+func redactValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.BytesKind:
+		return Redacted
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return redactMessage(v.Message())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	default:
+		return v.Interface()
+	}
+}
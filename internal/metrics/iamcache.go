@@ -0,0 +1,60 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// IAMCacheStats is the state of the IAM decision cache.
+type IAMCacheStats struct {
+	Hits    int64 // checks answered from the cache
+	Misses  int64 // checks that went to the IAM emulator
+	Entries int   // decisions cached
+}
+
+// iamCacheCollector reports the IAM decision cache's lookups, size and hit
+// ratio, reading the current stats on each scrape.
+type iamCacheCollector struct {
+	stats    func() IAMCacheStats
+	requests *prometheus.Desc
+	entries  *prometheus.Desc
+	hitRatio *prometheus.Desc
+}
+
+// RegisterIAMCache registers the lookup counts, size and hit ratio of the
+// IAM decision cache, computed by stats at scrape time.
+func (m *Metrics) RegisterIAMCache(stats func() IAMCacheStats) {
+	m.registry.MustRegister(&iamCacheCollector{
+		stats: stats,
+		requests: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "iam_cache", "requests_total"),
+			"IAM decision cache lookups by result (hit, miss).",
+			[]string{"result"}, nil,
+		),
+		entries: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "iam_cache", "entries"),
+			"IAM decisions in the cache.",
+			nil, nil,
+		),
+		hitRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "iam_cache", "hit_ratio"),
+			"Fraction of IAM decision cache lookups answered from the cache since startup.",
+			nil, nil,
+		),
+	})
+}
+
+func (c *iamCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requests
+	ch <- c.entries
+	ch <- c.hitRatio
+}
+
+func (c *iamCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	ratio := 0.0
+	if total := s.Hits + s.Misses; total > 0 {
+		ratio = float64(s.Hits) / float64(total)
+	}
+	ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Hits), "hit")
+	ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Misses), "miss")
+	ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(s.Entries))
+	ch <- prometheus.MustNewConstMetric(c.hitRatio, prometheus.GaugeValue, ratio)
+}
@@ -0,0 +1,138 @@
+// Package metrics exposes Prometheus metrics for the emulator: per-RPC
+// request counts and latencies, REST gateway requests, IAM permission checks
+// and their decision cache, and gauges derived from storage.
+//
+// Each Metrics value owns its registry, so several emulators can run in one
+// process (e.g. in tests) without colliding on the default registry.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "secretmanager_emulator"
+
+// IAM check results recorded by ObservePermissionCheck.
+const (
+	ResultAllowed = "allowed"
+	ResultDenied  = "denied"
+	ResultError   = "error"
+)
+
+// Metrics holds the emulator's collectors and the registry they are
+// registered with.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	grpcRequests *prometheus.CounterVec
+	grpcLatency  *prometheus.HistogramVec
+	httpRequests *prometheus.CounterVec
+	httpLatency  *prometheus.HistogramVec
+	iamChecks    *prometheus.CounterVec
+	iamLatency   *prometheus.HistogramVec
+}
+
+// New creates a Metrics with its own registry, including the standard Go
+// runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+		grpcLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "gRPC request latency by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "REST gateway requests by RPC, HTTP method and status code.",
+		}, []string{"rpc", "method", "code"}),
+		httpLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "REST gateway request latency by RPC and HTTP method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"rpc", "method"}),
+		iamChecks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "iam_checks_total",
+			Help:      "IAM permission checks by permission and result (allowed, denied, error).",
+		}, []string{"permission", "result"}),
+		iamLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "iam_check_duration_seconds",
+			Help:      "IAM permission check latency by permission.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"permission"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.grpcRequests, m.grpcLatency,
+		m.httpRequests, m.httpLatency,
+		m.iamChecks, m.iamLatency,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// UnaryServerInterceptor records the count and latency of every unary RPC.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observeRPC(info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records the count and latency of every streaming RPC.
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observeRPC(info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func (m *Metrics) observeRPC(method string, err error, latency time.Duration) {
+	m.grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.grpcLatency.WithLabelValues(method).Observe(latency.Seconds())
+}
+
+// ObserveHTTPRequest records a REST gateway request. rpc is the Secret
+// Manager RPC the route maps to, or "" for non-API paths.
+func (m *Metrics) ObserveHTTPRequest(rpc, method string, code int, latency time.Duration) {
+	if rpc == "" {
+		rpc = "other"
+	}
+	m.httpRequests.WithLabelValues(rpc, method, strconv.Itoa(code)).Inc()
+	m.httpLatency.WithLabelValues(rpc, method).Observe(latency.Seconds())
+}
+
+// ObservePermissionCheck records an IAM permission check and its result.
+func (m *Metrics) ObservePermissionCheck(permission, result string, latency time.Duration) {
+	m.iamChecks.WithLabelValues(permission, result).Inc()
+	m.iamLatency.WithLabelValues(permission).Observe(latency.Seconds())
+}
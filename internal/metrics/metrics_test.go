@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scrape returns the metrics exposition served by m.
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	m := New()
+	interceptor := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/google.cloud.secretmanager.v1.SecretManagerService/GetSecret"}
+
+	_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`secretmanager_emulator_grpc_requests_total{code="OK",method="/google.cloud.secretmanager.v1.SecretManagerService/GetSecret"} 1`,
+		`secretmanager_emulator_grpc_requests_total{code="NotFound",method="/google.cloud.secretmanager.v1.SecretManagerService/GetSecret"} 1`,
+		`secretmanager_emulator_grpc_request_duration_seconds_count{method="/google.cloud.secretmanager.v1.SecretManagerService/GetSecret"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestObserveHTTPRequestAndPermissionCheck(t *testing.T) {
+	m := New()
+	m.ObserveHTTPRequest("AccessSecretVersion", http.MethodGet, http.StatusOK, time.Millisecond)
+	m.ObserveHTTPRequest("", http.MethodGet, http.StatusNotFound, time.Millisecond)
+	m.ObservePermissionCheck("secretmanager.versions.access", ResultDenied, time.Millisecond)
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`secretmanager_emulator_http_requests_total{code="200",method="GET",rpc="AccessSecretVersion"} 1`,
+		`secretmanager_emulator_http_requests_total{code="404",method="GET",rpc="other"} 1`,
+		`secretmanager_emulator_iam_checks_total{permission="secretmanager.versions.access",result="denied"} 1`,
+		`secretmanager_emulator_iam_check_duration_seconds_count{permission="secretmanager.versions.access"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestRegisterStorage(t *testing.T) {
+	m := New()
+	secrets := 2
+	m.RegisterStorage(func() StorageStats {
+		return StorageStats{Secrets: secrets, Versions: map[string]int{"ENABLED": 3, "DESTROYED": 1}}
+	})
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`secretmanager_emulator_secrets 2`,
+		`secretmanager_emulator_secret_versions{state="ENABLED"} 3`,
+		`secretmanager_emulator_secret_versions{state="DESTROYED"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+
+	// Gauges are read at scrape time
+	secrets = 5
+	if out := scrape(t, m); !strings.Contains(out, `secretmanager_emulator_secrets 5`) {
+		t.Errorf("secrets gauge not refreshed on scrape")
+	}
+}
+
+func TestRegisterQuota(t *testing.T) {
+	m := New()
+	m.RegisterQuota(func() []QuotaStats {
+		return []QuotaStats{{Tenant: "ci-1", Project: "p", Class: "write", Limit: 600, Remaining: 598.5, Allowed: 7, Exceeded: 2}}
+	})
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`secretmanager_emulator_quota_remaining{class="write",project="p",tenant="ci-1"} 598.5`,
+		`secretmanager_emulator_quota_limit{class="write",project="p",tenant="ci-1"} 600`,
+		`secretmanager_emulator_quota_requests_total{class="write",project="p",result="allowed",tenant="ci-1"} 7`,
+		`secretmanager_emulator_quota_requests_total{class="write",project="p",result="exceeded",tenant="ci-1"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestRegisterIAMCache(t *testing.T) {
+	m := New()
+	m.RegisterIAMCache(func() IAMCacheStats {
+		return IAMCacheStats{Hits: 3, Misses: 1, Entries: 2}
+	})
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`secretmanager_emulator_iam_cache_requests_total{result="hit"} 3`,
+		`secretmanager_emulator_iam_cache_requests_total{result="miss"} 1`,
+		`secretmanager_emulator_iam_cache_entries 2`,
+		`secretmanager_emulator_iam_cache_hit_ratio 0.75`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// QuotaStats is the state of one project's quota for one class in a tenant.
+type QuotaStats struct {
+	Tenant    string // "" for the default tenant
+	Project   string
+	Class     string // access, read or write
+	Limit     int    // requests per minute
+	Remaining float64
+	Allowed   int64 // requests admitted
+	Exceeded  int64 // requests rejected with RESOURCE_EXHAUSTED
+}
+
+// quotaCollector reports quota gauges and counters, reading the current
+// stats on each scrape.
+type quotaCollector struct {
+	stats     func() []QuotaStats
+	remaining *prometheus.Desc
+	limit     *prometheus.Desc
+	requests  *prometheus.Desc
+}
+
+// RegisterQuota registers the remaining quota, limit and request counts of
+// every tenant, project and quota class, computed by stats at scrape time.
+func (m *Metrics) RegisterQuota(stats func() []QuotaStats) {
+	labels := []string{"tenant", "project", "class"}
+	m.registry.MustRegister(&quotaCollector{
+		stats: stats,
+		remaining: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "quota", "remaining"),
+			"Requests left in the project's quota bucket by tenant and class.",
+			labels, nil,
+		),
+		limit: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "quota", "limit"),
+			"Quota limit in requests per minute by tenant, project and class.",
+			labels, nil,
+		),
+		requests: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "quota", "requests_total"),
+			"Requests counted against quota by tenant, project, class and result (allowed, exceeded).",
+			append(labels, "result"), nil,
+		),
+	})
+}
+
+func (c *quotaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.remaining
+	ch <- c.limit
+	ch <- c.requests
+}
+
+func (c *quotaCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.stats() {
+		ch <- prometheus.MustNewConstMetric(c.remaining, prometheus.GaugeValue, s.Remaining, s.Tenant, s.Project, s.Class)
+		ch <- prometheus.MustNewConstMetric(c.limit, prometheus.GaugeValue, float64(s.Limit), s.Tenant, s.Project, s.Class)
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Allowed), s.Tenant, s.Project, s.Class, "allowed")
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Exceeded), s.Tenant, s.Project, s.Class, "exceeded")
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// StorageStats is a point-in-time summary of emulator storage.
+type StorageStats struct {
+	Secrets int
+	// Versions counts secret versions by state name (ENABLED, DISABLED, DESTROYED).
+	Versions map[string]int
+}
+
+// storageCollector reports storage gauges, reading the current stats on each
+// scrape rather than tracking every mutation.
+type storageCollector struct {
+	stats    func() StorageStats
+	secrets  *prometheus.Desc
+	versions *prometheus.Desc
+}
+
+// RegisterStorage registers gauges for the number of secrets and of versions
+// by state, computed by stats at scrape time.
+func (m *Metrics) RegisterStorage(stats func() StorageStats) {
+	m.registry.MustRegister(&storageCollector{
+		stats: stats,
+		secrets: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "secrets"),
+			"Number of secrets in storage.",
+			nil, nil,
+		),
+		versions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "secret_versions"),
+			"Number of secret versions in storage by state.",
+			[]string{"state"}, nil,
+		),
+	})
+}
+
+func (c *storageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.secrets
+	ch <- c.versions
+}
+
+func (c *storageCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.secrets, prometheus.GaugeValue, float64(stats.Secrets))
+	for state, n := range stats.Versions {
+		ch <- prometheus.MustNewConstMetric(c.versions, prometheus.GaugeValue, float64(n), state)
+	}
+}
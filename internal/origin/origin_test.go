@@ -0,0 +1,31 @@
+package origin
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// incoming turns the outgoing metadata of ctx into incoming metadata, as a
+// call arriving at the backend carries it.
+func incoming(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestFromGateway(t *testing.T) {
+	ctx := context.Background()
+
+	if !FromGateway(incoming(MarkGateway(ctx))) {
+		t.Error("FromGateway() of a marked call = false, want true")
+	}
+	if FromGateway(incoming(ctx)) {
+		t.Error("FromGateway() of an unmarked call = true, want false")
+	}
+	for _, forged := range []string{"true", "", token + "x"} {
+		if FromGateway(incoming(metadata.AppendToOutgoingContext(ctx, MetadataKey, forged))) {
+			t.Errorf("FromGateway() of a call marked %q = true, want false", forged)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Package quota emulates Secret Manager's per-project request quotas.
+//
+// Secret Manager limits requests per minute per project in three classes:
+// access requests (AccessSecretVersion), read requests and write requests.
+// A Limiter enforces configurable limits with a token bucket per tenant,
+// project and class, and rejects requests over quota with RESOURCE_EXHAUSTED carrying
+// google.rpc.QuotaFailure and ErrorInfo details, as Secret Manager does.
+package quota
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/logging"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// Class is a quota class: a group of RPCs sharing a per-project limit.
+type Class string
+
+// Quota classes of Secret Manager.
+const (
+	Access Class = "access"
+	Read   Class = "read"
+	Write  Class = "write"
+)
+
+// service is the API named in quota errors.
+const service = "secretmanager.googleapis.com"
+
+// quotaInfo describes a class the way Secret Manager's quota errors do.
+type quotaInfo struct {
+	metric string // quota metric name
+	id     string // quota limit ID
+	name   string // display name of the metric
+}
+
+var classInfo = map[Class]quotaInfo{
+	Access: {"secretmanager.googleapis.com/access_requests", "AccessRequestsPerMinutePerProject", "Access requests"},
+	Read:   {"secretmanager.googleapis.com/read_requests", "ReadRequestsPerMinutePerProject", "Read requests"},
+	Write:  {"secretmanager.googleapis.com/write_requests", "WriteRequestsPerMinutePerProject", "Write requests"},
+}
+
+// methodClasses maps Secret Manager RPCs to their quota class.
+var methodClasses = map[string]Class{
+	"AccessSecretVersion":  Access,
+	"GetSecret":            Read,
+	"ListSecrets":          Read,
+	"GetSecretVersion":     Read,
+	"ListSecretVersions":   Read,
+	"GetIamPolicy":         Read,
+	"TestIamPermissions":   Read,
+	"CreateSecret":         Write,
+	"UpdateSecret":         Write,
+	"DeleteSecret":         Write,
+	"AddSecretVersion":     Write,
+	"EnableSecretVersion":  Write,
+	"DisableSecretVersion": Write,
+	"DestroySecretVersion": Write,
+	"SetIamPolicy":         Write,
+}
+
+// serviceMethodPrefix starts the full name of every Secret Manager RPC.
+const serviceMethodPrefix = "/google.cloud.secretmanager.v1.SecretManagerService/"
+
+// Limits maps quota classes to requests per minute per project. Classes
+// without a positive limit are not enforced.
+type Limits map[Class]int
+
+// DefaultLimits are Secret Manager's default quotas.
+var DefaultLimits = Limits{
+	Access: 90000,
+	Read:   600,
+	Write:  600,
+}
+
+// ParseLimits parses a comma-separated list of class=requests-per-minute
+// pairs, e.g. "access=100,write=10". The word "default" stands for
+// DefaultLimits; pairs after it override them.
+func ParseLimits(s string) (Limits, error) {
+	limits := make(Limits)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == "default" {
+			maps.Copy(limits, DefaultLimits)
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		class := Class(strings.TrimSpace(name))
+		if _, known := classInfo[class]; !ok || !known {
+			return nil, fmt.Errorf("invalid quota %q: want access, read or write=requests per minute, or default", part)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid quota %q: limit must be a non-negative integer", part)
+		}
+		limits[class] = n
+	}
+	return limits, nil
+}
+
+// Limiter enforces quotas with a token bucket per project and class in each
+// tenant, so tenants sharing an emulator do not drain each other's quota. Each
+// bucket holds up to a minute's worth of requests and refills continuously.
+// It is safe for concurrent use.
+type Limiter struct {
+	limits Limits
+	clock  clock.Clock
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+type bucketKey struct {
+	tenant  string
+	project string
+	class   Class
+}
+
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	allowed  int64
+	exceeded int64
+}
+
+// NewLimiter creates a limiter enforcing limits, refilling buckets as c
+// advances.
+func NewLimiter(limits Limits, c clock.Clock) *Limiter {
+	return &Limiter{
+		limits:  limits,
+		clock:   c,
+		buckets: make(map[bucketKey]*bucket),
+	}
+}
+
+// Enabled reports whether any class is limited.
+func (l *Limiter) Enabled() bool {
+	for _, n := range l.limits {
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow takes a token for a call in tenant to a Secret Manager RPC on
+// resource, returning a RESOURCE_EXHAUSTED error if the project is over
+// quota. Calls to other services, unlimited classes or resources outside a
+// project are always allowed.
+func (l *Limiter) Allow(tenant, fullMethod, resource string) error {
+	method, ok := strings.CutPrefix(fullMethod, serviceMethodPrefix)
+	if !ok {
+		return nil
+	}
+	class, ok := methodClasses[method]
+	if !ok || l.limits[class] <= 0 {
+		return nil
+	}
+	project := projectID(resource)
+	if project == "" {
+		return nil
+	}
+
+	l.mu.Lock()
+	b := l.refill(bucketKey{tenant, project, class})
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+		b.allowed++
+	} else {
+		b.exceeded++
+	}
+	l.mu.Unlock()
+
+	if allowed {
+		return nil
+	}
+	return exceededError(project, class, l.limits[class])
+}
+
+// refill returns the bucket for key, created full, with tokens added for
+// the time since it was last updated. l.mu must be held.
+func (l *Limiter) refill(key bucketKey) *bucket {
+	limit := float64(l.limits[key.class])
+	now := l.clock.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit, updated: now}
+		l.buckets[key] = b
+		return b
+	}
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(limit, b.tokens+elapsed.Minutes()*limit)
+		b.updated = now
+	}
+	return b
+}
+
+// ForgetTenant drops the buckets of a tenant, e.g. once it is deleted, so a
+// tenant created later under the same name starts with full quota.
+func (l *Limiter) ForgetTenant(tenant string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key := range l.buckets {
+		if key.tenant == tenant {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Stats returns the remaining quota and request counts of every tenant,
+// project and class that has been used, for metrics.
+func (l *Limiter) Stats() []metrics.QuotaStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	keys := slices.Collect(maps.Keys(l.buckets))
+	slices.SortFunc(keys, func(a, b bucketKey) int {
+		return cmp.Or(
+			strings.Compare(a.tenant, b.tenant),
+			strings.Compare(a.project, b.project),
+			strings.Compare(string(a.class), string(b.class)),
+		)
+	})
+	stats := make([]metrics.QuotaStats, 0, len(keys))
+	for _, key := range keys {
+		b := l.refill(key)
+		stats = append(stats, metrics.QuotaStats{
+			Tenant:    key.tenant,
+			Project:   key.project,
+			Class:     string(key.class),
+			Limit:     l.limits[key.class],
+			Remaining: b.tokens,
+			Allowed:   b.allowed,
+			Exceeded:  b.exceeded,
+		})
+	}
+	return stats
+}
+
+// UnaryServerInterceptor rejects Secret Manager RPCs over quota before they
+// reach the service, counting each call against the tenant it selects.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.Allow(server.TenantFromContext(ctx), info.FullMethod, logging.ResourceName(req)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// projectID returns the project of a resource name such as
+// "projects/p/secrets/s", or "" if it has none.
+func projectID(resource string) string {
+	parts := strings.SplitN(resource, "/", 3)
+	if len(parts) < 2 || parts[0] != "projects" {
+		return ""
+	}
+	return parts[1]
+}
+
+// exceededError returns the error Secret Manager returns for a request over
+// quota.
+func exceededError(project string, class Class, limit int) error {
+	info := classInfo[class]
+	consumer := "projects/" + project
+	limitName := info.name + " per minute per project"
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf(
+		"Quota exceeded for quota metric '%s' and limit '%s' of service '%s' for consumer '%s'.",
+		info.name, limitName, service, consumer))
+
+	withDetails, err := st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: "RATE_LIMIT_EXCEEDED",
+			Domain: "googleapis.com",
+			Metadata: map[string]string{
+				"service":           service,
+				"consumer":          consumer,
+				"quota_metric":      info.metric,
+				"quota_limit":       info.id,
+				"quota_limit_value": strconv.Itoa(limit),
+				"quota_location":    "global",
+			},
+		},
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     consumer,
+				Description: fmt.Sprintf("%s exceeded the limit of %d per minute.", info.name, limit),
+				ApiService:  service,
+				QuotaMetric: info.metric,
+				QuotaId:     info.id,
+				QuotaValue:  int64(limit),
+			}},
+		},
+	)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,140 @@
+package quota
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+)
+
+const (
+	accessMethod = serviceMethodPrefix + "AccessSecretVersion"
+	createMethod = serviceMethodPrefix + "CreateSecret"
+)
+
+// newTestLimiter returns a limiter on a frozen clock.
+func newTestLimiter(limits Limits) (*Limiter, *clock.Virtual) {
+	c := clock.NewFrozen(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	return NewLimiter(limits, c), c
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("default, write=10,access=0")
+	if err != nil {
+		t.Fatalf("ParseLimits() error = %v", err)
+	}
+	if limits[Read] != DefaultLimits[Read] || limits[Write] != 10 || limits[Access] != 0 {
+		t.Errorf("ParseLimits() = %v", limits)
+	}
+	if limits, err := ParseLimits(""); err != nil || NewLimiter(limits, clock.Real{}).Enabled() {
+		t.Errorf("ParseLimits(\"\") = %v, %v; want no limits", limits, err)
+	}
+
+	for _, s := range []string{"write", "delete=5", "read=-1", "read=lots"} {
+		if _, err := ParseLimits(s); err == nil {
+			t.Errorf("ParseLimits(%q) error = nil", s)
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	l, clk := newTestLimiter(Limits{Write: 2})
+
+	for n := 0; n < 2; n++ {
+		if err := l.Allow("", createMethod, "projects/p"); err != nil {
+			t.Fatalf("Allow() call %d error = %v", n+1, err)
+		}
+	}
+	if err := l.Allow("", createMethod, "projects/p"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Allow() over quota error = %v, want ResourceExhausted", err)
+	}
+
+	// Buckets are per project and class; other calls are never limited
+	for _, call := range [][2]string{
+		{createMethod, "projects/other"},
+		{accessMethod, "projects/p/secrets/s/versions/1"},
+		{serviceMethodPrefix + "Unknown", "projects/p"},
+		{"/grpc.health.v1.Health/Check", ""},
+		{createMethod, ""},
+	} {
+		if err := l.Allow("", call[0], call[1]); err != nil {
+			t.Errorf("Allow(%s, %q) error = %v", call[0], call[1], err)
+		}
+	}
+
+	// Each tenant has its own buckets
+	if err := l.Allow("ci-1", createMethod, "projects/p"); err != nil {
+		t.Errorf("Allow() in another tenant error = %v", err)
+	}
+
+	// Tokens refill at the limit per minute, up to a minute's worth
+	_ = clk.Advance(30 * time.Second)
+	if err := l.Allow("", createMethod, "projects/p"); err != nil {
+		t.Errorf("Allow() after refill error = %v", err)
+	}
+	if err := l.Allow("", createMethod, "projects/p"); err == nil {
+		t.Errorf("Allow() beyond refill error = nil")
+	}
+	_ = clk.Advance(time.Hour)
+	stats := l.Stats()
+	if len(stats) != 3 || stats[0].Project != "other" || stats[1].Project != "p" || stats[2].Tenant != "ci-1" {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	if got := stats[1]; got.Class != "write" || got.Limit != 2 || got.Remaining != 2 || got.Allowed != 3 || got.Exceeded != 2 {
+		t.Errorf("Stats() for p = %+v", got)
+	}
+
+	// A deleted tenant's buckets are dropped
+	for range 2 {
+		_ = l.Allow("ci-1", createMethod, "projects/p")
+	}
+	l.ForgetTenant("ci-1")
+	if err := l.Allow("ci-1", createMethod, "projects/p"); err != nil {
+		t.Errorf("Allow() in a forgotten tenant error = %v", err)
+	}
+	if stats := l.Stats(); len(stats) != 3 || stats[2].Tenant != "ci-1" || stats[2].Allowed != 1 {
+		t.Errorf("Stats() after ForgetTenant = %+v", stats)
+	}
+}
+
+func TestExceededError(t *testing.T) {
+	l, _ := newTestLimiter(Limits{Access: 1})
+	interceptor := l.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: accessMethod}
+	req := &secretmanagerpb.AccessSecretVersionRequest{Name: "projects/p/secrets/s/versions/latest"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	if _, err := interceptor(context.Background(), req, info, handler); err != nil {
+		t.Fatalf("first call error = %v", err)
+	}
+	_, err := interceptor(context.Background(), req, info, handler)
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted || !strings.Contains(st.Message(), "consumer 'projects/p'") {
+		t.Fatalf("second call error = %v", err)
+	}
+
+	var violation *errdetails.QuotaFailure_Violation
+	var reason string
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.QuotaFailure:
+			violation = d.GetViolations()[0]
+		case *errdetails.ErrorInfo:
+			reason = d.GetReason()
+		}
+	}
+	if violation.GetSubject() != "projects/p" || violation.GetQuotaMetric() != "secretmanager.googleapis.com/access_requests" || violation.GetQuotaValue() != 1 {
+		t.Errorf("QuotaFailure violation = %v", violation)
+	}
+	if reason != "RATE_LIMIT_EXCEEDED" {
+		t.Errorf("ErrorInfo reason = %q, want RATE_LIMIT_EXCEEDED", reason)
+	}
+}
@@ -0,0 +1,203 @@
+// Package record captures Secret Manager traffic and replays it.
+//
+// A Recorder is a gRPC interceptor that appends every Secret Manager call,
+// with its request, response and status, to a JSON Lines file. REST requests
+// reach it through the gateway's backend connection and are recorded as the
+// RPCs they translate to. Payloads can be redacted, in which case every bytes
+// field and its checksum is cleared before the entry is written.
+//
+// A Replayer re-executes recorded calls against another endpoint, usually a
+// fresh emulator, and reports how the responses differ.
+package record
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/origin"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// serviceMethodPrefix limits recording to Secret Manager RPCs; health checks
+// and the admin API are not recorded.
+const serviceMethodPrefix = "/google.cloud.secretmanager.v1.SecretManagerService/"
+
+// Transports a recorded call arrived over.
+const (
+	TransportGRPC = "grpc"
+	TransportREST = "rest"
+)
+
+// Entry is one recorded call: a line of a recording.
+type Entry struct {
+	// Time is the emulator clock's time when the call arrived.
+	Time time.Time `json:"time"`
+	// Method is the full gRPC method name.
+	Method string `json:"method"`
+	// Transport is "grpc", or "rest" for calls forwarded by the gateway.
+	Transport string `json:"transport"`
+	// Principal is the caller's IAM principal, if any.
+	Principal string `json:"principal,omitempty"`
+	// Tenant is the tenant the call acted in; empty for the default tenant.
+	Tenant string `json:"tenant,omitempty"`
+	// Request is the request message in the proto3 JSON mapping.
+	Request json.RawMessage `json:"request"`
+	// Response is the response message; absent when the call failed.
+	Response json.RawMessage `json:"response,omitempty"`
+	// Code and Message are the call's status; Code is a name such as
+	// "NOT_FOUND".
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+	// Redacted reports whether payloads were cleared from the entry.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// Recorder writes recorded calls as JSON lines. It is safe for concurrent
+// use. A nil *Recorder records nothing.
+type Recorder struct {
+	clock  clock.Clock
+	redact bool
+
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// NewRecorder creates a recorder writing to w, stamping entries with c. With
+// redact, payloads are cleared from requests and responses.
+func NewRecorder(w io.Writer, c clock.Clock, redact bool) *Recorder {
+	return &Recorder{clock: c, redact: redact, w: w, enc: json.NewEncoder(w)}
+}
+
+// Create creates a recorder appending to the file at path, which is created
+// with owner-only permissions if it does not exist.
+func Create(path string, c clock.Clock, redact bool) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewRecorder(f, c, redact), nil
+}
+
+// Record appends an entry.
+func (r *Recorder) Record(e Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(e)
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// UnaryServerInterceptor records Secret Manager RPCs, including calls
+// rejected by interceptors later in the chain.
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if r == nil || !strings.HasPrefix(info.FullMethod, serviceMethodPrefix) {
+			return handler(ctx, req)
+		}
+		e := Entry{
+			Time:      r.clock.Now(),
+			Method:    info.FullMethod,
+			Transport: TransportGRPC,
+			Principal: emulatorauth.ExtractPrincipalFromContext(ctx),
+			Tenant:    server.TenantFromContext(ctx),
+			Redacted:  r.redact,
+		}
+		if origin.FromGateway(ctx) {
+			e.Transport = TransportREST
+		}
+		// Marshal the request before the handler runs, in case it is changed
+		e.Request = r.marshal(req)
+
+		resp, err := handler(ctx, req)
+
+		st := status.Convert(err)
+		e.Code, e.Message = code.Code(st.Code()).String(), st.Message()
+		if err == nil {
+			e.Response = r.marshal(resp)
+		}
+		if recErr := r.Record(e); recErr != nil {
+			slog.Error("Failed to record call", "method", info.FullMethod, "error", recErr)
+		}
+		return resp, err
+	}
+}
+
+// marshal returns msg in the proto3 JSON mapping, redacted if configured.
+func (r *Recorder) marshal(msg any) json.RawMessage {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if r.redact {
+		m = Redact(m)
+	}
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// Redact returns a copy of msg with every bytes field, and every checksum
+// field (named *_crc32c) of the same message, cleared.
+func Redact(msg proto.Message) proto.Message {
+	msg = proto.Clone(msg)
+	redactMessage(msg.ProtoReflect())
+	return msg
+}
+
+func redactMessage(m protoreflect.Message) {
+	var cleared []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Kind() == protoreflect.BytesKind || strings.HasSuffix(string(fd.Name()), "_crc32c"):
+			cleared = append(cleared, fd)
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redactMessage(mv.Message())
+					return true
+				})
+			}
+		case fd.Kind() != protoreflect.MessageKind:
+			// Other scalars are kept
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				redactMessage(v.List().Get(i).Message())
+			}
+		default:
+			redactMessage(v.Message())
+		}
+		return true
+	})
+	for _, fd := range cleared {
+		m.Clear(fd)
+	}
+}
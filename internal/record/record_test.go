@@ -0,0 +1,157 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/origin"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// startEmulator serves a fresh emulator on a clock frozen at start, with
+// the given interceptors, and returns a connection to it.
+func startEmulator(t *testing.T, interceptors ...grpc.UnaryServerInterceptor) (*grpc.ClientConn, *clock.Virtual) {
+	t.Helper()
+	clk := clock.NewFrozen(start)
+	mock, err := server.NewServer(server.WithClock(clk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	secretmanagerpb.RegisterSecretManagerServiceServer(srv, mock)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///emulator",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, clk
+}
+
+// recordTraffic records a short session and returns the recording.
+func recordTraffic(t *testing.T, redact bool) []Entry {
+	t.Helper()
+	var buf bytes.Buffer
+	var recorder *Recorder
+	conn, clk := startEmulator(t, func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return recorder.UnaryServerInterceptor()(ctx, req, info, handler)
+	})
+	recorder = NewRecorder(&buf, clk, redact)
+	client := secretmanagerpb.NewSecretManagerServiceClient(conn)
+	ctx := emulatorauth.InjectPrincipalToContext(context.Background(), "user:ci@example.com")
+
+	secret, err := client.CreateSecret(ctx, &secretmanagerpb.CreateSecretRequest{
+		Parent: "projects/p", SecretId: "db",
+		Secret: &secretmanagerpb.Secret{Replication: &secretmanagerpb.Replication{
+			Replication: &secretmanagerpb.Replication_Automatic_{Automatic: &secretmanagerpb.Replication_Automatic{}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = clk.Advance(time.Minute)
+	if _, err := client.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+		Parent: secret.GetName(), Payload: &secretmanagerpb.SecretPayload{Data: []byte("hunter2")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	restCtx := origin.MarkGateway(ctx)
+	if _, err := client.AccessSecretVersion(restCtx, &secretmanagerpb.AccessSecretVersionRequest{Name: secret.GetName() + "/versions/latest"}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/missing"})
+
+	entries, err := ReadEntries(&buf)
+	if err != nil {
+		t.Fatalf("ReadEntries() error = %v", err)
+	}
+	return entries
+}
+
+func TestRecorder(t *testing.T) {
+	entries := recordTraffic(t, false)
+	if len(entries) != 4 {
+		t.Fatalf("recorded %d calls, want 4", len(entries))
+	}
+	add, access, missing := entries[1], entries[2], entries[3]
+	if !add.Time.Equal(start.Add(time.Minute)) || add.Principal != "user:ci@example.com" || add.Transport != TransportGRPC {
+		t.Errorf("AddSecretVersion entry = %+v", add)
+	}
+	if access.Transport != TransportREST || !strings.Contains(string(access.Response), `"data":"aHVudGVyMg=="`) {
+		t.Errorf("AccessSecretVersion entry = %+v", access)
+	}
+	if missing.Code != "NOT_FOUND" || missing.Response != nil || missing.Message == "" {
+		t.Errorf("GetSecret entry = %+v, want NOT_FOUND without response", missing)
+	}
+
+	// Redaction clears payloads and checksums from requests and responses
+	for _, e := range recordTraffic(t, true) {
+		if !e.Redacted || strings.Contains(string(e.Request), "aHVudGVy") || strings.Contains(string(e.Response), "aHVudGVy") || strings.Contains(string(e.Response), "Crc32c") {
+			t.Errorf("redacted entry = %+v", e)
+		}
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	for _, redact := range []bool{false, true} {
+		entries := recordTraffic(t, redact)
+		conn, clk := startEmulator(t)
+		replayer := NewReplayer(conn, WithClock(clk))
+		for _, e := range entries {
+			result, err := replayer.Replay(context.Background(), e)
+			if err != nil {
+				t.Fatalf("Replay(%s) error = %v", e.Method, err)
+			}
+			if len(result.Diffs) != 0 {
+				t.Errorf("Replay(%s) redact=%v diffs = %v", e.Method, redact, result.Diffs)
+			}
+		}
+	}
+
+	// Against an emulator that already has the secret, creation fails, and
+	// without the clock create times differ unless ignored
+	entries := recordTraffic(t, false)
+	conn, _ := startEmulator(t)
+	replayer := NewReplayer(conn)
+	if _, err := replayer.Replay(context.Background(), entries[0]); err != nil {
+		t.Fatal(err)
+	}
+	result, err := replayer.Replay(context.Background(), entries[0])
+	if err != nil || len(result.Diffs) != 1 || !strings.HasPrefix(result.Diffs[0], "code: recorded OK, replayed ALREADY_EXISTS") {
+		t.Errorf("Replay(CreateSecret) again = %v, %v", result.Diffs, err)
+	}
+	result, err = replayer.Replay(context.Background(), entries[1])
+	if err != nil || len(result.Diffs) != 1 || !strings.HasPrefix(result.Diffs[0], "response.createTime: recorded \"2024-01-01T00:01:00Z\"") {
+		t.Errorf("Replay(AddSecretVersion) without clock diffs = %v, %v", result.Diffs, err)
+	}
+	fresh, _ := startEmulator(t)
+	ignoring := NewReplayer(fresh, WithIgnoredFields("createTime"))
+	for _, e := range entries {
+		if result, err := ignoring.Replay(context.Background(), e); err != nil || len(result.Diffs) != 0 {
+			t.Errorf("Replay(%s) ignoring createTime diffs = %v, %v", e.Method, result.Diffs, err)
+		}
+	}
+
+	if _, err := replayer.Replay(context.Background(), Entry{Method: "/google.cloud.secretmanager.v1.SecretManagerService/Nope"}); err == nil {
+		t.Error("Replay(unknown method) error = nil")
+	}
+}
@@ -0,0 +1,227 @@
+package record
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	// Registers the Secret Manager messages that recordings name
+	_ "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// ReadEntries reads a recording.
+func ReadEntries(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	dec := json.NewDecoder(r)
+	for {
+		var e Entry
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+// Result is the outcome of replaying one entry.
+type Result struct {
+	Entry Entry
+	// Code, Message and Response are what the replayed call returned.
+	Code     string
+	Message  string
+	Response json.RawMessage
+	// Diffs describe how the replayed call differs from the recording, one
+	// per line; empty when they match.
+	Diffs []string
+}
+
+// Replayer re-executes recorded calls over a connection and compares the
+// responses with the recorded ones.
+type Replayer struct {
+	conn   grpc.ClientConnInterface
+	clock  *clock.Virtual
+	ignore map[string]bool
+}
+
+// ReplayOption configures a Replayer.
+type ReplayOption func(*Replayer)
+
+// WithClock sets c to each entry's recorded time before replaying it. Use it
+// with the clock of an in-process emulator so that create times match a
+// recording made with a frozen clock.
+func WithClock(c *clock.Virtual) ReplayOption {
+	return func(r *Replayer) {
+		r.clock = c
+	}
+}
+
+// WithIgnoredFields leaves fields with these JSON names, such as
+// "createTime", out of the comparison at any depth.
+func WithIgnoredFields(names ...string) ReplayOption {
+	return func(r *Replayer) {
+		for _, name := range names {
+			r.ignore[name] = true
+		}
+	}
+}
+
+// NewReplayer creates a replayer calling Secret Manager over conn.
+func NewReplayer(conn grpc.ClientConnInterface, opts ...ReplayOption) *Replayer {
+	r := &Replayer{conn: conn, ignore: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Replay re-executes a recorded call as the recorded principal, in the
+// recorded tenant. It returns an error only if the entry cannot be replayed;
+// a call that fails differently from the recording is reported in the
+// result's Diffs.
+func (r *Replayer) Replay(ctx context.Context, e Entry) (Result, error) {
+	req, resp, err := newMessages(e.Method)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := protojson.Unmarshal(e.Request, req); err != nil {
+		return Result{}, fmt.Errorf("%s: invalid request: %w", e.Method, err)
+	}
+
+	if r.clock != nil {
+		r.clock.Set(e.Time)
+	}
+	ctx = emulatorauth.InjectPrincipalToContext(ctx, e.Principal)
+	if e.Tenant != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, server.TenantMetadataKey, e.Tenant)
+	}
+	callErr := r.conn.Invoke(ctx, e.Method, req, resp)
+
+	st := status.Convert(callErr)
+	result := Result{Entry: e, Code: code.Code(st.Code()).String(), Message: st.Message()}
+	if callErr == nil {
+		if e.Redacted {
+			resp = Redact(resp)
+		}
+		if result.Response, err = protojson.Marshal(resp); err != nil {
+			return Result{}, fmt.Errorf("%s: %w", e.Method, err)
+		}
+	}
+
+	if result.Code != e.Code {
+		result.Diffs = append(result.Diffs, fmt.Sprintf("code: recorded %s, replayed %s: %s", e.Code, result.Code, result.Message))
+		return result, nil
+	}
+	if callErr == nil {
+		var recorded, replayed any
+		if err := json.Unmarshal(e.Response, &recorded); err != nil {
+			return Result{}, fmt.Errorf("%s: invalid response: %w", e.Method, err)
+		}
+		if err := json.Unmarshal(result.Response, &replayed); err != nil {
+			return Result{}, fmt.Errorf("%s: %w", e.Method, err)
+		}
+		result.Diffs = r.diff("response", recorded, replayed, result.Diffs)
+	}
+	return result, nil
+}
+
+// newMessages returns empty request and response messages for a full gRPC
+// method name such as "/google.cloud.secretmanager.v1.SecretManagerService/GetSecret".
+func newMessages(fullMethod string) (req, resp proto.Message, err error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid method %q", fullMethod)
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unknown service %q", service)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown service %q", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, nil, fmt.Errorf("unknown method %q", fullMethod)
+	}
+
+	in, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	return in.New().Interface(), out.New().Interface(), nil
+}
+
+// diff appends a line to diffs for every value at or below path that differs
+// between two decoded JSON documents, skipping ignored fields.
+func (r *Replayer) diff(path string, recorded, replayed any, diffs []string) []string {
+	switch a := recorded.(type) {
+	case map[string]any:
+		b, ok := replayed.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(a)+len(b))
+		for k := range a {
+			keys = append(keys, k)
+		}
+		for k := range b {
+			if _, ok := a[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if !r.ignore[k] {
+				diffs = r.diff(path+"."+k, a[k], b[k], diffs)
+			}
+		}
+		return diffs
+	case []any:
+		b, ok := replayed.([]any)
+		if !ok || len(a) != len(b) {
+			break
+		}
+		for i := range a {
+			diffs = r.diff(fmt.Sprintf("%s[%d]", path, i), a[i], b[i], diffs)
+		}
+		return diffs
+	}
+	if !reflect.DeepEqual(recorded, replayed) {
+		diffs = append(diffs, fmt.Sprintf("%s: recorded %s, replayed %s", path, jsonValue(recorded), jsonValue(replayed)))
+	}
+	return diffs
+}
+
+// jsonValue formats a decoded JSON value for a diff; absent values are shown
+// as <absent>.
+func jsonValue(v any) string {
+	if v == nil {
+		return "<absent>"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,147 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// Summary counts what applying seeds changed.
+type Summary struct {
+	Secrets  int // secrets created
+	Versions int // versions added
+	Updated  int // existing secrets whose labels, annotations or aliases changed
+
+	// Existing versions moved to their declared state
+	Enabled   int
+	Disabled  int
+	Destroyed int
+}
+
+// Changed reports whether anything was changed.
+func (s Summary) Changed() bool {
+	return s != Summary{}
+}
+
+// add adds the counts of o to s.
+func (s *Summary) add(o Summary) {
+	s.Secrets += o.Secrets
+	s.Versions += o.Versions
+	s.Updated += o.Updated
+	s.Enabled += o.Enabled
+	s.Disabled += o.Disabled
+	s.Destroyed += o.Destroyed
+}
+
+// ApplyTenants applies files to the tenants they name, creating the tenants,
+// as Apply does to a single storage. Files without a tenant are applied to
+// the default tenant.
+func ApplyTenants(ctx context.Context, tenants *server.Tenants, files ...*File) (Summary, error) {
+	var summary Summary
+	groups := byTenant(files)
+	for _, tenant := range slices.Sorted(maps.Keys(groups)) {
+		storage, err := tenants.Get(tenant)
+		if err != nil {
+			return summary, err
+		}
+		applied, err := Apply(ctx, storage, groups[tenant]...)
+		summary.add(applied)
+		if err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// byTenant groups files by the tenant they name.
+func byTenant(files []*File) map[string][]*File {
+	groups := make(map[string][]*File)
+	for _, f := range files {
+		groups[f.Tenant] = append(groups[f.Tenant], f)
+	}
+	return groups
+}
+
+// Apply creates the secrets and versions declared by files in storage,
+// through the same Storage methods the RPCs use. A secret declared twice, or
+// one that already exists, fails with an error at its declaration.
+func Apply(ctx context.Context, storage *server.Storage, files ...*File) (Summary, error) {
+	var summary Summary
+	if err := checkDuplicates(files); err != nil {
+		return summary, err
+	}
+
+	for _, f := range files {
+		for _, p := range f.Projects {
+			for i := range p.Secrets {
+				s := &p.Secrets[i]
+				if err := createSecret(ctx, storage, p.ID, s); err != nil {
+					return summary, &Error{Path: f.Path, Line: s.line, Err: err}
+				}
+				summary.Secrets++
+				for n := range s.Versions {
+					v := &s.Versions[n]
+					if _, err := addVersion(ctx, storage, s.name(p.ID), v); err != nil {
+						return summary, &Error{Path: f.Path, Line: v.line, Err: err}
+					}
+					summary.Versions++
+				}
+				if len(s.Aliases) > 0 {
+					if _, err := storage.UpdateSecret(ctx, s.name(p.ID), nil, nil, s.Aliases); err != nil {
+						return summary, &Error{Path: f.Path, Line: s.line, Err: err}
+					}
+				}
+			}
+		}
+	}
+	return summary, nil
+}
+
+// checkDuplicates fails if any secret is declared more than once.
+func checkDuplicates(files []*File) error {
+	declared := make(map[string]string)
+	for _, f := range files {
+		for _, p := range f.Projects {
+			for _, s := range p.Secrets {
+				name := s.name(p.ID)
+				if at, ok := declared[name]; ok {
+					return &Error{Path: f.Path, Line: s.line, Err: fmt.Errorf("secret %s is already declared at %s", name, at)}
+				}
+				declared[name] = fmt.Sprintf("%s:%d", f.Path, s.line)
+			}
+		}
+	}
+	return nil
+}
+
+// name returns the secret's resource name.
+func (s *Secret) name(project string) string {
+	return s.parent(project) + "/secrets/" + s.ID
+}
+
+func createSecret(ctx context.Context, storage *server.Storage, project string, s *Secret) error {
+	_, err := storage.CreateSecret(ctx, s.parent(project), s.ID, s.proto())
+	return err
+}
+
+// addVersion adds a version and moves it to its declared state.
+func addVersion(ctx context.Context, storage *server.Storage, secretName string, v *Version) (*secretmanagerpb.SecretVersion, error) {
+	version, err := storage.AddSecretVersion(ctx, secretName, &secretmanagerpb.SecretPayload{Data: v.payload})
+	if err != nil {
+		return nil, err
+	}
+
+	state, _ := v.state()
+	switch state {
+	case secretmanagerpb.SecretVersion_DISABLED:
+		_, err = storage.DisableSecretVersion(ctx, version.Name)
+	case secretmanagerpb.SecretVersion_DESTROYED:
+		_, err = storage.DestroySecretVersion(ctx, version.Name)
+	}
+	return version, err
+}
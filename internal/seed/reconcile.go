@@ -0,0 +1,228 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// Reconciler brings storage in line with seed files that may change over
+// time. Reconciling is idempotent: applying unchanged seeds changes nothing.
+//
+// Versions are never renumbered or deleted. The reconciler remembers which
+// stored version each declared version was applied as; when a declared
+// payload changes, a new version is appended and takes over that
+// declaration. Secrets and versions no longer declared are left alone.
+type Reconciler struct {
+	storage *server.Storage
+
+	mu       sync.Mutex
+	versions map[string][]appliedVersion // secret name -> declared version index
+}
+
+// appliedVersion records the stored version a declared version was applied
+// as, and a digest of the payload it was applied with.
+type appliedVersion struct {
+	number int64
+	digest [sha256.Size]byte
+}
+
+// NewReconciler creates a reconciler for storage.
+func NewReconciler(storage *server.Storage) *Reconciler {
+	return &Reconciler{
+		storage:  storage,
+		versions: make(map[string][]appliedVersion),
+	}
+}
+
+// Reconcile applies files to storage:
+//   - missing secrets are created with their declared versions
+//   - labels, annotations and aliases are updated to the declared values
+//   - a declared version whose payload changed is appended as a new version
+//   - versions are enabled, disabled or destroyed to match their declared state
+//
+// Secrets the reconciler has not seen before (e.g. loaded by Apply) are
+// adopted: declared versions match the stored versions with the same number
+// and payload. Problems with individual versions, such as a destroyed
+// version declared enabled, are returned after the rest is reconciled.
+func (r *Reconciler) Reconcile(ctx context.Context, files ...*File) (Summary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var summary Summary
+	if err := checkDuplicates(files); err != nil {
+		return summary, err
+	}
+
+	existing := make(map[string]*server.StoredSecret)
+	for _, stored := range r.storage.Secrets() {
+		existing[stored.Name] = stored
+	}
+
+	var errs []error
+	for _, f := range files {
+		for _, p := range f.Projects {
+			for i := range p.Secrets {
+				s := &p.Secrets[i]
+				if err := r.reconcileSecret(ctx, f, p.ID, s, existing[s.name(p.ID)], &summary); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return summary, errors.Join(errs...)
+}
+
+// reconcileSecret reconciles one declared secret. stored is its current
+// state, or nil if it does not exist.
+func (r *Reconciler) reconcileSecret(ctx context.Context, f *File, project string, s *Secret, stored *server.StoredSecret, summary *Summary) error {
+	name := s.name(project)
+	fail := func(line int, err error) error {
+		return &Error{Path: f.Path, Line: line, Err: err}
+	}
+
+	created := stored == nil
+	var applied []appliedVersion
+	updated := false
+	if created {
+		if err := createSecret(ctx, r.storage, project, s); err != nil {
+			return fail(s.line, err)
+		}
+		summary.Secrets++
+		stored = &server.StoredSecret{Name: name}
+	} else {
+		applied = r.versions[name]
+		if !maps.Equal(stored.Labels, s.Labels) || !maps.Equal(stored.Annotations, s.Annotations) {
+			if _, err := r.storage.UpdateSecret(ctx, name, orEmpty(s.Labels), orEmpty(s.Annotations), nil); err != nil {
+				return fail(s.line, err)
+			}
+			updated = true
+		}
+	}
+
+	var errs []error
+	next := make([]appliedVersion, len(s.Versions))
+	for i := range s.Versions {
+		v := &s.Versions[i]
+		digest := sha256.Sum256(v.payload)
+		number := matchVersion(stored, applied, i, v, digest)
+		if number == 0 {
+			version, err := addVersion(ctx, r.storage, name, v)
+			if err != nil {
+				return fail(v.line, err)
+			}
+			summary.Versions++
+			number = versionNumber(version.Name)
+		} else if err := r.transition(ctx, stored.Versions[strconv.FormatInt(number, 10)], v, summary); err != nil {
+			errs = append(errs, fail(v.line, err))
+		}
+		next[i] = appliedVersion{number: number, digest: digest}
+	}
+	r.versions[name] = next
+
+	// Aliases count declared versions; point them at the stored numbers
+	aliases := make(map[string]int64, len(s.Aliases))
+	for alias, n := range s.Aliases {
+		aliases[alias] = next[n-1].number
+	}
+	if !maps.Equal(stored.VersionAliases, aliases) {
+		if _, err := r.storage.UpdateSecret(ctx, name, nil, nil, aliases); err != nil {
+			errs = append(errs, fail(s.line, err))
+		} else {
+			updated = true
+		}
+	}
+
+	if updated && !created {
+		summary.Updated++
+	}
+	return errors.Join(errs...)
+}
+
+// versionNumber returns the number of a version resource name.
+func versionNumber(name string) int64 {
+	n, _ := strconv.ParseInt(name[strings.LastIndex(name, "/")+1:], 10, 64)
+	return n
+}
+
+// matchVersion returns the number of the stored version that declared
+// version i was applied as, or 0 if it must be appended.
+func matchVersion(stored *server.StoredSecret, applied []appliedVersion, i int, v *Version, digest [sha256.Size]byte) int64 {
+	if i < len(applied) {
+		prev := applied[i]
+		if _, ok := stored.Versions[strconv.FormatInt(prev.number, 10)]; ok && prev.digest == digest {
+			return prev.number
+		}
+		return 0
+	}
+	if applied != nil {
+		return 0
+	}
+
+	// Adopt a secret the reconciler has not applied: match by number
+	n := int64(i + 1)
+	version, ok := stored.Versions[strconv.FormatInt(n, 10)]
+	if !ok {
+		return 0
+	}
+	state, _ := v.state()
+	if bytes.Equal(version.Payload, v.payload) || version.State == secretmanagerpb.SecretVersion_DESTROYED && state == secretmanagerpb.SecretVersion_DESTROYED {
+		return n
+	}
+	return 0
+}
+
+// transition moves a stored version to its declared state.
+func (r *Reconciler) transition(ctx context.Context, version *server.StoredVersion, v *Version, summary *Summary) error {
+	state, _ := v.state()
+	if version.State == state {
+		return nil
+	}
+	if version.State == secretmanagerpb.SecretVersion_DESTROYED {
+		return fmt.Errorf("version %s is destroyed and cannot be %s", version.Name, stateName(state))
+	}
+
+	var err error
+	switch state {
+	case secretmanagerpb.SecretVersion_ENABLED:
+		_, err = r.storage.EnableSecretVersion(ctx, version.Name)
+		summary.Enabled++
+	case secretmanagerpb.SecretVersion_DISABLED:
+		_, err = r.storage.DisableSecretVersion(ctx, version.Name)
+		summary.Disabled++
+	case secretmanagerpb.SecretVersion_DESTROYED:
+		_, err = r.storage.DestroySecretVersion(ctx, version.Name)
+		summary.Destroyed++
+	}
+	return err
+}
+
+// stateName returns the seed spelling of a version state.
+func stateName(state secretmanagerpb.SecretVersion_State) string {
+	switch state {
+	case secretmanagerpb.SecretVersion_ENABLED:
+		return "enabled"
+	case secretmanagerpb.SecretVersion_DISABLED:
+		return "disabled"
+	}
+	return "destroyed"
+}
+
+// orEmpty returns m, or an empty map if m is nil, so that UpdateSecret
+// clears the field instead of leaving it unchanged.
+func orEmpty(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
@@ -0,0 +1,190 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+const reconcileSeed = `projects:
+  - id: p
+    secrets:
+      - id: s
+        labels: {env: dev}
+        aliases: {current: 2}
+        versions:
+          - data: one
+            state: disabled
+          - data: two
+          - data: three
+            state: destroyed
+`
+
+// mustParse parses a seed or fails the test.
+func mustParse(t *testing.T, seed string) *File {
+	t.Helper()
+	f, err := Parse(filepath.Join(t.TempDir(), "seed.yaml"), []byte(seed))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return f
+}
+
+// versionStates returns the state of every version of a secret by number.
+func versionStates(t *testing.T, storage *server.Storage, name string) map[string]secretmanagerpb.SecretVersion_State {
+	t.Helper()
+	states := make(map[string]secretmanagerpb.SecretVersion_State)
+	for _, stored := range storage.Secrets() {
+		if stored.Name != name {
+			continue
+		}
+		for id, version := range stored.Versions {
+			states[id] = version.State
+		}
+	}
+	return states
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	storage := server.NewStorage()
+	if _, err := Apply(ctx, storage, mustParse(t, reconcileSeed)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	r := NewReconciler(storage)
+
+	// Secrets loaded by Apply are adopted without changes, and reconciling
+	// again is a no-op
+	for i := 0; i < 2; i++ {
+		summary, err := r.Reconcile(ctx, mustParse(t, reconcileSeed))
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if summary.Changed() {
+			t.Errorf("Reconcile() of unchanged seed = %+v, want no changes", summary)
+		}
+	}
+
+	// A changed payload appends a version that takes over the declaration
+	// and its alias; states follow the declaration
+	changed := strings.NewReplacer("data: two", "data: two-v2", "state: disabled", "state: enabled", "{env: dev}", "{env: prod}").Replace(reconcileSeed)
+	summary, err := r.Reconcile(ctx, mustParse(t, changed))
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	want := Summary{Versions: 1, Updated: 1, Enabled: 1}
+	if summary != want {
+		t.Errorf("Reconcile() = %+v, want %+v", summary, want)
+	}
+	secret, err := storage.GetSecret(ctx, "projects/p/secrets/s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.VersionAliases["current"] != 4 || secret.Labels["env"] != "prod" {
+		t.Errorf("secret = %v, want alias current=4 and env=prod", secret)
+	}
+	resp, err := storage.AccessSecretVersion(ctx, "projects/p/secrets/s/versions/current")
+	if err != nil || string(resp.GetPayload().GetData()) != "two-v2" {
+		t.Errorf("AccessSecretVersion(current) = %v, %v; want two-v2", resp, err)
+	}
+	if summary, _ := r.Reconcile(ctx, mustParse(t, changed)); summary.Changed() {
+		t.Errorf("Reconcile() repeated = %+v, want no changes", summary)
+	}
+
+	// Versions are disabled and destroyed as declared, never renumbered
+	destroyed := strings.NewReplacer("data: two-v2", "data: two-v2\n            state: destroyed", "state: enabled", "state: disabled").Replace(changed)
+	summary, err = r.Reconcile(ctx, mustParse(t, destroyed+"      - id: added\n"))
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	want = Summary{Secrets: 1, Disabled: 1, Destroyed: 1}
+	if summary != want {
+		t.Errorf("Reconcile() = %+v, want %+v", summary, want)
+	}
+	wantStates := map[string]secretmanagerpb.SecretVersion_State{
+		"1": secretmanagerpb.SecretVersion_DISABLED,
+		"2": secretmanagerpb.SecretVersion_ENABLED,
+		"3": secretmanagerpb.SecretVersion_DESTROYED,
+		"4": secretmanagerpb.SecretVersion_DESTROYED,
+	}
+	got := versionStates(t, storage, "projects/p/secrets/s")
+	for id, state := range wantStates {
+		if got[id] != state {
+			t.Errorf("version %s state = %v, want %v", id, got[id], state)
+		}
+	}
+	if len(got) != len(wantStates) {
+		t.Errorf("secret has %d versions, want %d", len(got), len(wantStates))
+	}
+}
+
+func TestReconcile_DestroyedVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	storage := server.NewStorage()
+	r := NewReconciler(storage)
+	if _, err := r.Reconcile(ctx, mustParse(t, reconcileSeed)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	// Destroyed versions cannot come back; the rest is still reconciled
+	f := mustParse(t, strings.NewReplacer("            state: destroyed\n", "", "state: disabled", "state: enabled").Replace(reconcileSeed))
+	summary, err := r.Reconcile(ctx, f)
+	if err == nil || !strings.Contains(err.Error(), f.Path+":11: version projects/p/secrets/s/versions/3 is destroyed and cannot be enabled") {
+		t.Errorf("Reconcile() error = %v, want destroyed version conflict at line 11", err)
+	}
+	if summary.Enabled != 1 {
+		t.Errorf("Reconcile() = %+v, want version 1 enabled", summary)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "seed.yaml", reconcileSeed)
+
+	var logs bytes.Buffer
+	tenants := server.NewTenants(clock.Real{})
+	storage := tenants.Default()
+	w := NewWatcher(dir, tenants, DefaultWatchInterval, slog.New(slog.NewTextHandler(&logs, nil)))
+	ctx := context.Background()
+
+	w.poll(ctx)
+	if got := len(versionStates(t, storage, "projects/p/secrets/s")); got != 3 {
+		t.Fatalf("after first poll secret has %d versions, want 3", got)
+	}
+	if !strings.Contains(logs.String(), "secrets_created=1") {
+		t.Errorf("logs = %s, want a reconciliation summary", logs.String())
+	}
+
+	// Unchanged seeds are not reconciled again
+	logs.Reset()
+	w.poll(ctx)
+	if logs.Len() != 0 {
+		t.Errorf("unchanged seed logged %s", logs.String())
+	}
+
+	// Invalid seeds are reported once and leave storage alone
+	writeFile(t, dir, "seed.yaml", reconcileSeed+"        bogus: true\n")
+	w.poll(ctx)
+	w.poll(ctx)
+	if n := strings.Count(logs.String(), "Invalid seed"); n != 1 {
+		t.Errorf("invalid seed logged %d times, want 1: %s", n, logs.String())
+	}
+
+	logs.Reset()
+	writeFile(t, dir, "seed.yaml", strings.Replace(reconcileSeed, "data: two", "data: two-v2", 1))
+	w.poll(ctx)
+	if !strings.Contains(logs.String(), "versions_added=1") {
+		t.Errorf("logs = %s, want one version added", logs.String())
+	}
+	if got := len(versionStates(t, storage, "projects/p/secrets/s")); got != 4 {
+		t.Errorf("after edit secret has %d versions, want 4", got)
+	}
+}
@@ -0,0 +1,445 @@
+// Package seed pre-populates emulator storage from YAML or JSON fixtures.
+//
+// A seed file declares projects and their secrets, each with ordered
+// versions:
+//
+//	projects:
+//	  - id: test-project
+//	    secrets:
+//	      - id: db-password
+//	        labels: {env: dev}
+//	        aliases: {current: 2}
+//	        ttl: 720h
+//	        versions:
+//	          - data: hunter2
+//	            state: disabled
+//	          - file: ./db-password.txt
+//	          - base64: c2VjcmV0
+//
+// A file may name a tenant to seed, e.g. "tenant: job-42"; files without one
+// seed the default tenant.
+//
+// Seeds are applied through the same Storage methods the RPCs use, so
+// seeded secrets are indistinguishable from ones created through the API.
+// Validation errors name the file and line of the offending declaration.
+//
+// A Watcher re-applies seeds when they are edited, reconciling storage with
+// the declarations without renumbering existing versions.
+package seed
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// secretIDPattern matches the secret IDs GCP accepts.
+var secretIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,255}$`)
+
+// File is a parsed seed file.
+type File struct {
+	// Path is the file the seed was read from.
+	Path string `yaml:"-"`
+	// Tenant is the tenant the secrets are created in, or "" for the
+	// default tenant.
+	Tenant   string    `yaml:"tenant"`
+	Projects []Project `yaml:"projects"`
+
+	data       []byte
+	tenantLine int
+}
+
+// Project declares the secrets of one project.
+type Project struct {
+	ID      string   `yaml:"id"`
+	Secrets []Secret `yaml:"secrets"`
+
+	line int
+}
+
+// Secret declares a secret and its versions.
+type Secret struct {
+	ID string `yaml:"id"`
+	// Location makes the secret regional
+	// (projects/{project}/locations/{location}/secrets/{id}).
+	Location    string            `yaml:"location"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+	Replication *Replication      `yaml:"replication"`
+	// ExpireTime (RFC 3339) and TTL (a Go duration such as "24h") are
+	// mutually exclusive.
+	ExpireTime string `yaml:"expire_time"`
+	TTL        string `yaml:"ttl"`
+	// Aliases maps alias names to version numbers, counting declared
+	// versions from 1.
+	Aliases  map[string]int64 `yaml:"aliases"`
+	Versions []Version        `yaml:"versions"`
+
+	line int
+}
+
+// Replication declares a secret's replication policy. The default is
+// automatic replication.
+type Replication struct {
+	Automatic bool `yaml:"automatic"`
+	// Locations selects user-managed replication to these locations.
+	Locations []string `yaml:"locations"`
+}
+
+// Version declares a secret version. Exactly one of Data, File and Base64
+// gives the payload.
+type Version struct {
+	Data *string `yaml:"data"`
+	// File is read relative to the seed file's directory.
+	File   string `yaml:"file"`
+	Base64 string `yaml:"base64"`
+	// State is enabled (the default), disabled or destroyed.
+	State string `yaml:"state"`
+
+	line    int
+	payload []byte
+}
+
+// Error is a seed error at a line of a seed file.
+type Error struct {
+	Path string
+	Line int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.Path, e.Line, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Load reads a seed file, or every .yaml, .yml and .json file in a
+// directory in name order.
+func Load(path string) ([]*File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		f, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []*File{f}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []*File
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || !isSeedFile(entry.Name()) {
+			continue
+		}
+		f, err := ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		files = append(files, f)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// isSeedFile reports whether a directory entry is loaded as a seed.
+func isSeedFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// ReadFile reads and validates a seed file.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, data)
+}
+
+// Parse parses and validates a seed file's contents. JSON is accepted as a
+// subset of YAML. Version payload files are read relative to path.
+func Parse(path string, data []byte) (*File, error) {
+	f := &File{Path: path, data: data}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return f, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, yamlError(path, err)
+	}
+	if len(doc.Content) == 0 {
+		return f, nil // Only comments
+	}
+	if err := decodeStrict(doc.Content[0], f); err != nil {
+		return nil, yamlError(path, err)
+	}
+	f.tenantLine = keyLine(doc.Content[0], "tenant")
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// UnmarshalYAML records the line of the project declaration.
+func (p *Project) UnmarshalYAML(n *yaml.Node) error {
+	type plain Project
+	p.line = n.Line
+	return decodeStrict(n, (*plain)(p))
+}
+
+// UnmarshalYAML records the line of the secret declaration.
+func (s *Secret) UnmarshalYAML(n *yaml.Node) error {
+	type plain Secret
+	s.line = n.Line
+	return decodeStrict(n, (*plain)(s))
+}
+
+// UnmarshalYAML records the line of the version declaration.
+func (v *Version) UnmarshalYAML(n *yaml.Node) error {
+	type plain Version
+	v.line = n.Line
+	return decodeStrict(n, (*plain)(v))
+}
+
+// decodeStrict decodes a mapping node into the struct v points at,
+// rejecting keys v has no field for. yaml.Node.Decode does not apply the
+// decoder's KnownFields setting to nested nodes.
+func decodeStrict(n *yaml.Node, v any) error {
+	if n.Kind == yaml.MappingNode {
+		known := yamlFields(reflect.TypeOf(v).Elem())
+		for i := 0; i < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if !slices.Contains(known, key.Value) {
+				return fmt.Errorf("line %d: unknown field %q", key.Line, key.Value)
+			}
+		}
+	}
+	return n.Decode(v)
+}
+
+// yamlFields returns the YAML keys of a struct type's fields.
+func yamlFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// yamlLinePattern matches the "line N: message" form of yaml.v3 errors.
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlError converts a YAML decoding error into errors naming path and line.
+func yamlError(path string, err error) error {
+	var messages []string
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	} else {
+		messages = []string{err.Error()}
+	}
+
+	var errs []error
+	for _, msg := range messages {
+		m := yamlLinePattern.FindStringSubmatch(msg)
+		if m == nil {
+			errs = append(errs, fmt.Errorf("%s: %s", path, strings.TrimPrefix(msg, "yaml: ")))
+			continue
+		}
+		line, _ := strconv.Atoi(m[1])
+		errs = append(errs, &Error{Path: path, Line: line, Err: errors.New(m[2])})
+	}
+	return errors.Join(errs...)
+}
+
+// keyLine returns the line of key in a mapping node, or 0 if it is absent.
+func keyLine(n *yaml.Node, key string) int {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i].Line
+		}
+	}
+	return 0
+}
+
+// validate checks every declaration and reads version payloads, reporting
+// all problems at once.
+func (f *File) validate() error {
+	var errs []error
+	fail := func(line int, format string, args ...any) {
+		errs = append(errs, &Error{Path: f.Path, Line: line, Err: fmt.Errorf(format, args...)})
+	}
+
+	if f.Tenant != server.DefaultTenant && server.ValidateTenant(f.Tenant) != nil {
+		fail(f.tenantLine, "invalid tenant %q: want 1-63 letters, digits, - or _, starting with a letter or digit", f.Tenant)
+	}
+	for i := range f.Projects {
+		p := &f.Projects[i]
+		if p.ID == "" || strings.Contains(p.ID, "/") {
+			fail(p.line, "invalid project id %q", p.ID)
+		}
+		for j := range p.Secrets {
+			s := &p.Secrets[j]
+			if !secretIDPattern.MatchString(s.ID) {
+				fail(s.line, "invalid secret id %q: want 1-255 letters, digits, - or _", s.ID)
+			}
+			if strings.Contains(s.Location, "/") {
+				fail(s.line, "invalid location %q", s.Location)
+			}
+			if s.ExpireTime != "" && s.TTL != "" {
+				fail(s.line, "secret %q sets both expire_time and ttl", s.ID)
+			}
+			if s.ExpireTime != "" {
+				if _, err := time.Parse(time.RFC3339, s.ExpireTime); err != nil {
+					fail(s.line, "secret %q: invalid expire_time %q: want RFC 3339", s.ID, s.ExpireTime)
+				}
+			}
+			if s.TTL != "" {
+				if d, err := time.ParseDuration(s.TTL); err != nil || d <= 0 {
+					fail(s.line, "secret %q: invalid ttl %q: want a positive duration such as 24h", s.ID, s.TTL)
+				}
+			}
+			if r := s.Replication; r != nil && r.Automatic && len(r.Locations) > 0 {
+				fail(s.line, "secret %q: replication is either automatic or has locations", s.ID)
+			}
+			for alias, n := range s.Aliases {
+				if n < 1 || n > int64(len(s.Versions)) {
+					fail(s.line, "secret %q: alias %q refers to version %d, but %d versions are declared", s.ID, alias, n, len(s.Versions))
+				}
+			}
+			for k := range s.Versions {
+				if err := f.loadPayload(&s.Versions[k]); err != nil {
+					fail(s.Versions[k].line, "secret %q version %d: %v", s.ID, k+1, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadPayload validates a version declaration and resolves its payload.
+func (f *File) loadPayload(v *Version) error {
+	sources := 0
+	for _, set := range []bool{v.Data != nil, v.File != "", v.Base64 != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New("set exactly one of data, file and base64")
+	}
+	if _, err := v.state(); err != nil {
+		return err
+	}
+
+	switch {
+	case v.Data != nil:
+		v.payload = []byte(*v.Data)
+	case v.File != "":
+		path := v.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(f.Path), path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		v.payload = data
+	default:
+		data, err := base64.StdEncoding.DecodeString(v.Base64)
+		if err != nil {
+			return fmt.Errorf("invalid base64: %v", err)
+		}
+		v.payload = data
+	}
+	return nil
+}
+
+// state returns the declared version state.
+func (v *Version) state() (secretmanagerpb.SecretVersion_State, error) {
+	switch strings.ToLower(v.State) {
+	case "", "enabled":
+		return secretmanagerpb.SecretVersion_ENABLED, nil
+	case "disabled":
+		return secretmanagerpb.SecretVersion_DISABLED, nil
+	case "destroyed":
+		return secretmanagerpb.SecretVersion_DESTROYED, nil
+	}
+	return 0, fmt.Errorf("invalid state %q: want enabled, disabled or destroyed", v.State)
+}
+
+// Payload returns the version's payload, resolved when the file was parsed.
+func (v *Version) Payload() []byte {
+	return v.payload
+}
+
+// parent returns the resource name the secret is created under.
+func (s *Secret) parent(project string) string {
+	if s.Location != "" {
+		return fmt.Sprintf("projects/%s/locations/%s", project, s.Location)
+	}
+	return "projects/" + project
+}
+
+// proto returns the secret metadata to create the secret with.
+func (s *Secret) proto() *secretmanagerpb.Secret {
+	secret := &secretmanagerpb.Secret{
+		Labels:      s.Labels,
+		Annotations: s.Annotations,
+	}
+	if r := s.Replication; r != nil && len(r.Locations) > 0 {
+		var replicas []*secretmanagerpb.Replication_UserManaged_Replica
+		for _, location := range r.Locations {
+			replicas = append(replicas, &secretmanagerpb.Replication_UserManaged_Replica{Location: location})
+		}
+		secret.Replication = &secretmanagerpb.Replication{
+			Replication: &secretmanagerpb.Replication_UserManaged_{
+				UserManaged: &secretmanagerpb.Replication_UserManaged{Replicas: replicas},
+			},
+		}
+	}
+	switch {
+	case s.TTL != "":
+		d, _ := time.ParseDuration(s.TTL)
+		secret.Expiration = &secretmanagerpb.Secret_Ttl{Ttl: durationpb.New(d)}
+	case s.ExpireTime != "":
+		t, _ := time.Parse(time.RFC3339, s.ExpireTime)
+		secret.Expiration = &secretmanagerpb.Secret_ExpireTime{ExpireTime: timestamppb.New(t)}
+	}
+	return secret
+}
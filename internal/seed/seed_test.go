@@ -0,0 +1,279 @@
+package seed
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+const testSeed = `projects:
+  - id: test-project
+    secrets:
+      - id: db-password
+        labels: {env: dev}
+        annotations: {owner: team-a}
+        aliases: {current: 2}
+        ttl: 24h
+        versions:
+          - data: one
+            state: disabled
+          - file: payload.txt
+          - base64: dGhyZWU=
+            state: destroyed
+      - id: regional
+        location: us-east1
+        replication:
+          locations: [us-east1, us-west1]
+        expire_time: "2030-01-01T00:00:00Z"
+        versions:
+          - data: ""
+`
+
+// writeFile writes content to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "payload.txt", "two")
+	path := writeFile(t, dir, "seed.yaml", testSeed)
+
+	files, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	ctx := context.Background()
+	storage := server.NewStorage()
+	summary, err := Apply(ctx, storage, files...)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if summary != (Summary{Secrets: 2, Versions: 4}) {
+		t.Errorf("Apply() = %+v, want 2 secrets and 4 versions", summary)
+	}
+
+	secret, err := storage.GetSecret(ctx, "projects/test-project/secrets/db-password")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if secret.Labels["env"] != "dev" || secret.Annotations["owner"] != "team-a" || secret.VersionAliases["current"] != 2 {
+		t.Errorf("secret metadata = %v", secret)
+	}
+	if secret.GetExpireTime() == nil {
+		t.Error("secret with a ttl has no expire_time")
+	}
+
+	wantStates := map[string]secretmanagerpb.SecretVersion_State{
+		"1": secretmanagerpb.SecretVersion_DISABLED,
+		"2": secretmanagerpb.SecretVersion_ENABLED,
+		"3": secretmanagerpb.SecretVersion_DESTROYED,
+	}
+	for id, want := range wantStates {
+		version, err := storage.GetSecretVersion(ctx, "projects/test-project/secrets/db-password/versions/"+id)
+		if err != nil {
+			t.Fatalf("GetSecretVersion(%s) error = %v", id, err)
+		}
+		if version.State != want {
+			t.Errorf("version %s state = %v, want %v", id, version.State, want)
+		}
+	}
+	resp, err := storage.AccessSecretVersion(ctx, "projects/test-project/secrets/db-password/versions/current")
+	if err != nil {
+		t.Fatalf("AccessSecretVersion(current) error = %v", err)
+	}
+	if string(resp.Payload.Data) != "two" {
+		t.Errorf("current payload = %q, want %q", resp.Payload.Data, "two")
+	}
+
+	regional, err := storage.GetSecret(ctx, "projects/test-project/locations/us-east1/secrets/regional")
+	if err != nil {
+		t.Fatalf("GetSecret(regional) error = %v", err)
+	}
+	if replicas := regional.GetReplication().GetUserManaged().GetReplicas(); len(replicas) != 2 {
+		t.Errorf("regional replicas = %v, want 2", replicas)
+	}
+	if got := regional.GetExpireTime().AsTime().Year(); got != 2030 {
+		t.Errorf("regional expire_time year = %d, want 2030", got)
+	}
+}
+
+func TestLoad_Directory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.yaml", "projects:\n  - id: p\n    secrets:\n      - id: a\n")
+	writeFile(t, dir, "b.json", `{"projects": [{"id": "p", "secrets": [{"id": "b", "versions": [{"data": "x"}]}]}]}`)
+	writeFile(t, dir, "notes.txt", "not a seed")
+
+	files, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Load() returned %d files, want 2", len(files))
+	}
+
+	summary, err := Apply(context.Background(), server.NewStorage(), files...)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if summary != (Summary{Secrets: 2, Versions: 1}) {
+		t.Errorf("Apply() = %+v, want 2 secrets and 1 version", summary)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name, seed string
+		want       []string
+	}{
+		{
+			name: "syntax",
+			seed: "projects:\n  - id: p\n   secrets: [\n",
+			want: []string{"seed.yaml:1:"},
+		},
+		{
+			name: "unknown field",
+			seed: "projects:\n  - id: p\n    secrets:\n      - id: s\n        lables: {}\n",
+			want: []string{`seed.yaml:5: unknown field "lables"`},
+		},
+		{
+			name: "wrong type",
+			seed: "projects:\n  - id: p\n    secrets:\n      - id: s\n        labels: [a]\n",
+			want: []string{"seed.yaml:5:"},
+		},
+		{
+			name: "invalid declarations",
+			seed: `projects:
+  - id: p
+    secrets:
+      - id: "bad id"
+      - id: s
+        ttl: soon
+        aliases: {current: 4}
+        versions:
+          - data: x
+            base64: eA==
+          - data: x
+            state: deleted
+          - base64: "!!"
+`,
+			want: []string{
+				`seed.yaml:4: invalid secret id "bad id"`,
+				`seed.yaml:5: secret "s": invalid ttl "soon"`,
+				`seed.yaml:5: secret "s": alias "current" refers to version 4`,
+				"seed.yaml:9: secret \"s\" version 1: set exactly one of data, file and base64",
+				`seed.yaml:11: secret "s" version 2: invalid state "deleted"`,
+				`seed.yaml:13: secret "s" version 3: invalid base64`,
+			},
+		},
+		{
+			name: "invalid tenant",
+			seed: "tenant: job/42\nprojects: []\n",
+			want: []string{`seed.yaml:1: invalid tenant "job/42"`},
+		},
+		{
+			name: "missing payload file",
+			seed: "projects:\n  - id: p\n    secrets:\n      - id: s\n        versions:\n          - file: missing.txt\n",
+			want: []string{"seed.yaml:6:", "missing.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), "seed.yaml", tt.seed)
+			_, err := ReadFile(path)
+			if err == nil {
+				t.Fatal("ReadFile() error = nil")
+			}
+			msg := strings.ReplaceAll(err.Error(), path, "seed.yaml")
+			for _, want := range tt.want {
+				if !strings.Contains(msg, want) {
+					t.Errorf("error %q does not contain %q", msg, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	for _, seed := range []string{"", "\n  \n", "# only a comment\n", "# projects:\n#   - id: p\n"} {
+		f, err := Parse("seed.yaml", []byte(seed))
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", seed, err)
+			continue
+		}
+		if len(f.Projects) != 0 {
+			t.Errorf("Parse(%q) = %d projects, want none", seed, len(f.Projects))
+		}
+	}
+}
+
+func TestApplyTenants(t *testing.T) {
+	dir := t.TempDir()
+	seed := "projects:\n  - id: p\n    secrets:\n      - id: s\n        versions:\n          - data: x\n"
+	writeFile(t, dir, "default.yaml", seed)
+	writeFile(t, dir, "job.yaml", "tenant: job-42\n"+seed)
+
+	files, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	ctx := context.Background()
+	tenants := server.NewTenants(clock.Real{})
+	summary, err := ApplyTenants(ctx, tenants, files...)
+	if err != nil {
+		t.Fatalf("ApplyTenants() error = %v", err)
+	}
+	if summary != (Summary{Secrets: 2, Versions: 2}) {
+		t.Errorf("ApplyTenants() = %+v, want 2 secrets and 2 versions", summary)
+	}
+
+	// The same secret is declared once in each tenant
+	job, ok := tenants.Lookup("job-42")
+	if !ok {
+		t.Fatal("ApplyTenants() did not create tenant job-42")
+	}
+	for _, storage := range []*server.Storage{tenants.Default(), job} {
+		if _, err := storage.GetSecret(ctx, "projects/p/secrets/s"); err != nil {
+			t.Errorf("GetSecret() error = %v", err)
+		}
+	}
+}
+
+func TestApply_Conflicts(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.yaml", "projects:\n  - id: p\n    secrets:\n      - id: s\n")
+	b := writeFile(t, dir, "b.yaml", "projects:\n  - id: other\n  - id: p\n    secrets:\n      - id: s\n")
+	files, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	_, err = Apply(context.Background(), server.NewStorage(), files...)
+	if err == nil || !strings.Contains(err.Error(), b+":5: secret projects/p/secrets/s is already declared at "+a+":4") {
+		t.Errorf("Apply() error = %v, want duplicate declaration", err)
+	}
+
+	// Secrets that already exist in storage are reported at their declaration
+	storage := server.NewStorage()
+	if _, err := storage.CreateSecret(context.Background(), "projects/p", "s", &secretmanagerpb.Secret{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Apply(context.Background(), storage, files[0])
+	if status.Code(err) != codes.AlreadyExists || !strings.HasPrefix(err.Error(), a+":4: ") {
+		t.Errorf("Apply() error = %v, want AlreadyExists at %s:4", err, a)
+	}
+}
@@ -0,0 +1,153 @@
+package seed
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/server"
+)
+
+// DefaultWatchInterval is how often a Watcher checks seed files for changes.
+const DefaultWatchInterval = time.Second
+
+// Watcher reconciles the tenants a seed file or directory declares with it
+// whenever its contents change.
+//
+// Seeds are polled rather than watched with filesystem notifications, which
+// are unreliable on the bind mounts and network filesystems seed fixtures
+// are typically edited through.
+type Watcher struct {
+	path        string
+	interval    time.Duration
+	tenants     *server.Tenants
+	reconcilers map[string]*Reconciler // by tenant
+	logger      *slog.Logger
+
+	digest  [sha256.Size]byte // digest of the seeds last reconciled
+	primed  bool              // whether the seeds have been reconciled once
+	lastErr string            // last load error logged, to log each error once
+}
+
+// NewWatcher creates a watcher reconciling tenants with the seeds at path
+// every interval. Seeds without a tenant are reconciled with the default
+// tenant.
+func NewWatcher(path string, tenants *server.Tenants, interval time.Duration, logger *slog.Logger) *Watcher {
+	return &Watcher{
+		path:        path,
+		interval:    interval,
+		tenants:     tenants,
+		reconcilers: make(map[string]*Reconciler),
+		logger:      logger,
+	}
+}
+
+// Run reconciles the seeds immediately, then again whenever they change,
+// until ctx is canceled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reloads the seeds and reconciles them if they changed since the last
+// reconciliation. Invalid seeds are logged and leave storage untouched.
+func (w *Watcher) poll(ctx context.Context) {
+	files, err := Load(w.path)
+	if err != nil {
+		if err.Error() != w.lastErr {
+			w.logger.Error("Invalid seed, keeping current state", "path", w.path, "error", err)
+			w.lastErr = err.Error()
+		}
+		return
+	}
+	w.lastErr = ""
+
+	digest := digestFiles(files)
+	if digest == w.digest {
+		return
+	}
+	w.digest = digest
+
+	start := time.Now()
+	summary, err := w.reconcile(ctx, files)
+
+	// The first pass usually adopts seeds already applied at startup
+	level := slog.LevelInfo
+	if !w.primed && !summary.Changed() {
+		level = slog.LevelDebug
+	}
+	w.primed = true
+	w.logger.Log(ctx, level, "Seed reconciled",
+		"path", w.path,
+		"files", len(files),
+		"secrets_created", summary.Secrets,
+		"secrets_updated", summary.Updated,
+		"versions_added", summary.Versions,
+		"versions_enabled", summary.Enabled,
+		"versions_disabled", summary.Disabled,
+		"versions_destroyed", summary.Destroyed,
+		"duration", time.Since(start),
+	)
+	if err != nil {
+		w.logger.Warn("Seed partially reconciled", "path", w.path, "error", err)
+	}
+}
+
+// reconcile reconciles each tenant with the seeds naming it.
+func (w *Watcher) reconcile(ctx context.Context, files []*File) (Summary, error) {
+	var summary Summary
+	var errs []error
+	groups := byTenant(files)
+	for _, tenant := range slices.Sorted(maps.Keys(groups)) {
+		storage, err := w.tenants.Get(tenant)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// A tenant deleted and created again starts over with new storage
+		r, ok := w.reconcilers[tenant]
+		if !ok || r.storage != storage {
+			r = NewReconciler(storage)
+			w.reconcilers[tenant] = r
+		}
+		reconciled, err := r.Reconcile(ctx, groups[tenant]...)
+		summary.add(reconciled)
+		errs = append(errs, err)
+	}
+	return summary, errors.Join(errs...)
+}
+
+// digestFiles returns a digest of the seeds' contents, including payloads
+// read from files.
+func digestFiles(files []*File) [sha256.Size]byte {
+	h := sha256.New()
+	for _, f := range files {
+		h.Write([]byte(f.Path))
+		h.Write([]byte{0})
+		h.Write(f.data)
+		for _, p := range f.Projects {
+			for _, s := range p.Secrets {
+				for _, v := range s.Versions {
+					payload := sha256.Sum256(v.payload)
+					h.Write(payload[:])
+				}
+			}
+		}
+	}
+	var digest [sha256.Size]byte
+	h.Sum(digest[:0])
+	return digest
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Checkpoint describes a saved storage state that Restore returns to.
+type Checkpoint struct {
+	ID         string
+	CreateTime time.Time
+	Secrets    int // Number of secrets saved
+}
+
+type checkpoint struct {
+	Checkpoint
+	seq      int
+	secrets  secretMap
+	expiring secretMap
+}
+
+// Checkpoint saves the current state of storage and returns its ID. It takes
+// constant time and memory: the checkpoint shares the persistent maps that
+// hold the state, and later writes copy what they change.
+func (s *Storage) Checkpoint() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextCheckpoint++
+	id := strconv.Itoa(s.nextCheckpoint)
+	s.checkpoints[id] = &checkpoint{
+		Checkpoint: Checkpoint{ID: id, CreateTime: s.clock.Now(), Secrets: s.secrets.Len()},
+		seq:        s.nextCheckpoint,
+		secrets:    s.secrets,
+		expiring:   s.expiring,
+	}
+	return id
+}
+
+// Restore resets storage to a checkpoint, which stays available to restore
+// again. Restoring takes constant time, plus rearming the expiry timer and
+// deleting the secrets that expired since the checkpoint.
+// Returns NotFound if the checkpoint doesn't exist.
+func (s *Storage) Restore(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+
+	cp, ok := s.checkpoints[id]
+	if !ok {
+		return status.Errorf(codes.NotFound, "Checkpoint [%s] not found", id)
+	}
+	s.secrets, s.expiring = cp.secrets, cp.expiring
+	s.expireDue()
+	return nil
+}
+
+// Checkpoints returns the saved checkpoints, oldest first.
+func (s *Storage) Checkpoints() []Checkpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cps := make([]*checkpoint, 0, len(s.checkpoints))
+	for _, cp := range s.checkpoints {
+		cps = append(cps, cp)
+	}
+	slices.SortFunc(cps, func(a, b *checkpoint) int { return a.seq - b.seq })
+
+	result := make([]Checkpoint, len(cps))
+	for i, cp := range cps {
+		result[i] = cp.Checkpoint
+	}
+	return result
+}
+
+// DeleteCheckpoint discards a checkpoint, releasing the state only it holds.
+// Returns NotFound if the checkpoint doesn't exist.
+func (s *Storage) DeleteCheckpoint(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.checkpoints[id]; !ok {
+		return status.Errorf(codes.NotFound, "Checkpoint [%s] not found", id)
+	}
+	delete(s.checkpoints, id)
+	return nil
+}
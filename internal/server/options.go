@@ -0,0 +1,46 @@
+package server
+
+import (
+	"time"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
+)
+
+// Option configures the Server.
+type Option func(*Server)
+
+// WithMetrics records IAM permission checks in m and registers storage
+// gauges (secrets, versions by state) with it.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+// WithClock makes storage take create times from c and expire secrets by it,
+// instead of the system clock.
+func WithClock(c clock.Clock) Option {
+	return func(s *Server) {
+		s.clock = c
+	}
+}
+
+// WithPermissionCache caches IAM permission check decisions for ttl, keyed by
+// principal, resource and permission. Policy changes made in the IAM
+// emulator take up to ttl to apply.
+func WithPermissionCache(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.cacheTTL = ttl
+	}
+}
+
+// WithListFiltering makes ListSecrets and ListSecretVersions drop the items
+// the caller may not get, checking a page's items with IAM in one batch.
+// Secret Manager only checks the list permission on the parent, so this is
+// off by default.
+func WithListFiltering() Option {
+	return func(s *Server) {
+		s.filterLists = true
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"hash/fnv"
+	"iter"
+)
+
+// secretMap is a persistent map from secret names to secrets, ordered by
+// name. Updates return a new map that shares every untouched node with the
+// old one, so an old map stays valid and keeping it costs nothing: this is
+// what makes storage checkpoints O(1). The zero value is an empty map.
+//
+// It is a treap whose node priorities are derived from the key, so the shape
+// of the tree depends only on its keys and updates copy O(log n) nodes.
+type secretMap struct {
+	root *secretNode
+}
+
+type secretNode struct {
+	key         string
+	value       *StoredSecret
+	priority    uint32
+	size        int
+	left, right *secretNode
+}
+
+// Len returns the number of secrets in the map.
+func (m secretMap) Len() int {
+	return m.root.count()
+}
+
+// Get returns the secret stored under key.
+func (m secretMap) Get(key string) (*StoredSecret, bool) {
+	n := m.root
+	for n != nil {
+		switch {
+		case key < n.key:
+			n = n.left
+		case key > n.key:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	return nil, false
+}
+
+// Set returns a map with key set to value.
+func (m secretMap) Set(key string, value *StoredSecret) secretMap {
+	if _, ok := m.Get(key); ok {
+		return secretMap{root: replace(m.root, key, value)}
+	}
+	left, right := split(m.root, key)
+	n := &secretNode{key: key, value: value, priority: keyPriority(key), size: 1}
+	return secretMap{root: merge(merge(left, n), right)}
+}
+
+// Delete returns a map without key.
+func (m secretMap) Delete(key string) secretMap {
+	if _, ok := m.Get(key); !ok {
+		return m
+	}
+	left, right := split(m.root, key)
+	_, right = split(right, key+"\x00")
+	return secretMap{root: merge(left, right)}
+}
+
+// Min returns the entry with the least key, if the map is not empty.
+func (m secretMap) Min() (string, *StoredSecret, bool) {
+	n := m.root
+	if n == nil {
+		return "", nil, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.value, true
+}
+
+// All iterates over the map in key order.
+func (m secretMap) All() iter.Seq2[string, *StoredSecret] {
+	return func(yield func(string, *StoredSecret) bool) {
+		m.root.walk(yield)
+	}
+}
+
+func (n *secretNode) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *secretNode) walk(yield func(string, *StoredSecret) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.walk(yield) && yield(n.key, n.value) && n.right.walk(yield)
+}
+
+// with returns a copy of n with new children.
+func (n *secretNode) with(left, right *secretNode) *secretNode {
+	c := *n
+	c.left, c.right = left, right
+	c.size = 1 + left.count() + right.count()
+	return &c
+}
+
+// replace copies the path to key, which must be present, and stores value
+// in the copy.
+func replace(n *secretNode, key string, value *StoredSecret) *secretNode {
+	switch {
+	case key < n.key:
+		return n.with(replace(n.left, key, value), n.right)
+	case key > n.key:
+		return n.with(n.left, replace(n.right, key, value))
+	}
+	c := n.with(n.left, n.right)
+	c.value = value
+	return c
+}
+
+// split divides a tree into the keys less than key and the rest.
+func split(n *secretNode, key string) (*secretNode, *secretNode) {
+	if n == nil {
+		return nil, nil
+	}
+	if n.key < key {
+		left, right := split(n.right, key)
+		return n.with(n.left, left), right
+	}
+	left, right := split(n.left, key)
+	return left, n.with(right, n.right)
+}
+
+// merge joins two trees, where every key of left is less than every key of
+// right.
+func merge(left, right *secretNode) *secretNode {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority > right.priority:
+		return left.with(left.left, merge(left.right, right))
+	}
+	return right.with(merge(left, right.left), right.right)
+}
+
+func keyPriority(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSecretMap(t *testing.T) {
+	var m secretMap
+	want := map[string]bool{}
+	var saved []secretMap
+	var savedKeys [][]string
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("projects/p/secrets/s%d", r.Intn(300))
+		if r.Intn(3) == 0 {
+			m = m.Delete(key)
+			delete(want, key)
+		} else {
+			m = m.Set(key, &StoredSecret{Name: key})
+			want[key] = true
+		}
+		if i%100 == 0 {
+			saved = append(saved, m)
+			savedKeys = append(savedKeys, sortedKeys(want))
+		}
+	}
+
+	if m.Len() != len(want) {
+		t.Errorf("Len() = %d, want %d", m.Len(), len(want))
+	}
+	for key := range want {
+		if v, ok := m.Get(key); !ok || v.Name != key {
+			t.Errorf("Get(%q) = %v, %v", key, v, ok)
+		}
+	}
+	// Old versions are unaffected by later updates
+	for i, old := range saved {
+		var keys []string
+		for key := range old.All() {
+			keys = append(keys, key)
+		}
+		if !slices.Equal(keys, savedKeys[i]) {
+			t.Errorf("saved map %d has keys %v, want %v", i, keys, savedKeys[i])
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -15,10 +15,18 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
+	"sync"
+	"time"
 
+	"cloud.google.com/go/iam/apiv1/iampb"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/audit"
 	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/authz"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -38,19 +46,43 @@ import (
 //	secretmanagerpb.RegisterSecretManagerServiceServer(grpcServer, server)
 type Server struct {
 	secretmanagerpb.UnimplementedSecretManagerServiceServer
-	storage   *Storage
+	tenants   *Tenants
 	iamClient *emulatorauth.Client
 	iamMode   emulatorauth.AuthMode
+	iamHost   string
+	metrics   *metrics.Metrics
+	clock     clock.Clock
+	cacheTTL  time.Duration
+	decisions *authz.DecisionCache
+
+	filterLists bool
 }
 
 // NewServer creates a new mock Secret Manager server.
-func NewServer() (*Server, error) {
+func NewServer(opts ...Option) (*Server, error) {
 	s := &Server{
-		storage: NewStorage(),
+		clock: clock.Real{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.tenants = NewTenants(s.clock)
+	if s.metrics != nil {
+		s.metrics.RegisterStorage(s.storageStats)
+	}
+	if s.cacheTTL > 0 {
+		s.decisions = authz.NewDecisionCache(s.clock, s.cacheTTL)
+		if s.metrics != nil {
+			s.metrics.RegisterIAMCache(s.cacheStats)
+		}
+		s.tenants.OnDelete(func(tenant string) {
+			s.decisions.Invalidate(tenant, "")
+		})
 	}
 
 	config := emulatorauth.LoadFromEnv()
 	s.iamMode = config.Mode
+	s.iamHost = config.Host
 
 	if config.Mode.IsEnabled() {
 		client, err := emulatorauth.NewClient(config.Host, config.Mode, "gcp-secret-manager-emulator")
@@ -63,6 +95,25 @@ func NewServer() (*Server, error) {
 	return s, nil
 }
 
+// CheckIAM is a readiness check reporting whether the IAM emulator is
+// reachable. It only fails in strict mode, where every request is denied
+// while IAM is down; permissive mode fails open and off mode never calls IAM.
+func (s *Server) CheckIAM(ctx context.Context) error {
+	if s.iamMode != emulatorauth.AuthModeStrict {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.iamHost)
+	if err != nil {
+		return fmt.Errorf("IAM emulator at %s is unreachable: %w", s.iamHost, err)
+	}
+	return conn.Close()
+}
+
 // checkPermission checks if the principal has permission to perform an operation on a resource.
 func (s *Server) checkPermission(ctx context.Context, operation string, resource string) error {
 	if s.iamClient == nil {
@@ -71,12 +122,12 @@ func (s *Server) checkPermission(ctx context.Context, operation string, resource
 
 	principal := emulatorauth.ExtractPrincipalFromContext(ctx)
 
-	permCheck, ok := authz.GetPermission(operation)
-	if !ok {
-		return nil // Unknown operation, allow
+	permission, err := s.requiredPermission(operation)
+	if err != nil || permission == "" {
+		return err
 	}
 
-	allowed, err := s.iamClient.CheckPermission(ctx, principal, resource, permCheck.Permission)
+	allowed, err := s.decide(ctx, TenantFromContext(ctx), principal, resource, permission)
 	if err != nil {
 		return status.Errorf(codes.Internal, "IAM check failed: %v", err)
 	}
@@ -88,7 +139,160 @@ func (s *Server) checkPermission(ctx context.Context, operation string, resource
 	return nil
 }
 
+// checkPermissions checks the caller's permission for operation on each of
+// resources, as list operations that filter items one by one need, and
+// reports which are allowed. Cached decisions are reused and the rest are
+// checked concurrently. A denial is not an error; a failed IAM check is.
+func (s *Server) checkPermissions(ctx context.Context, operation string, resources []string) ([]bool, error) {
+	allowed := make([]bool, len(resources))
+	permission := ""
+	if s.iamClient != nil {
+		var err error
+		if permission, err = s.requiredPermission(operation); err != nil {
+			return nil, err
+		}
+	}
+	if permission == "" {
+		for i := range allowed {
+			allowed[i] = true
+		}
+		return allowed, nil
+	}
+
+	tenant := TenantFromContext(ctx)
+	principal := emulatorauth.ExtractPrincipalFromContext(ctx)
+
+	// Check each distinct resource once
+	indexes := make(map[string][]int)
+	for i, resource := range resources {
+		indexes[resource] = append(indexes[resource], i)
+	}
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentChecks)
+	for resource, idx := range indexes {
+		g.Go(func() error {
+			ok, err := s.decide(gctx, tenant, principal, resource, permission)
+			if err != nil {
+				return status.Errorf(codes.Internal, "IAM check failed: %v", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, i := range idx {
+				allowed[i] = ok
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return allowed, nil
+}
+
+// maxConcurrentChecks bounds the IAM checks checkPermissions runs at once.
+const maxConcurrentChecks = 8
+
+// filterPermitted drops the items of a list page the caller may not read, as
+// checked by operation on each item's resource, when list filtering is
+// enabled (see WithListFiltering). A page may therefore hold fewer items than
+// were asked for while more pages follow.
+func filterPermitted[T any](ctx context.Context, s *Server, operation string, items []T, resource func(T) string) ([]T, error) {
+	if !s.filterLists || s.iamClient == nil || len(items) == 0 {
+		return items, nil
+	}
+	resources := make([]string, len(items))
+	for i, item := range items {
+		resources[i] = resource(item)
+	}
+	allowed, err := s.checkPermissions(ctx, operation, resources)
+	if err != nil {
+		return nil, err
+	}
+	permitted := items[:0]
+	for i, item := range items {
+		if allowed[i] {
+			permitted = append(permitted, item)
+		}
+	}
+	return permitted, nil
+}
+
+// requiredPermission returns the permission operation requires, or "" if it
+// requires none. In strict mode an operation without a mapping is denied.
+func (s *Server) requiredPermission(operation string) (string, error) {
+	permCheck, ok := authz.GetPermission(operation)
+	switch {
+	case ok:
+		return permCheck.Permission, nil
+	case authz.UncheckedOperations[operation]:
+		return "", nil
+	case s.iamMode == emulatorauth.AuthModeStrict:
+		// Fail closed, so a new operation cannot bypass strict mode
+		return "", status.Errorf(codes.PermissionDenied, "Permission denied: operation %s has no IAM permission mapping", operation)
+	default:
+		return "", nil // Unknown operation, allow outside strict mode
+	}
+}
+
+// decide asks IAM whether principal has permission on resource of tenant,
+// answering from the decision cache when it can. IAM is asked about the
+// tenant's own name for the resource (see authz.TenantResource), so tenants
+// do not share policies. Decisions IAM makes are cached; errors are not.
+func (s *Server) decide(ctx context.Context, tenant, principal, resource, permission string) (bool, error) {
+	if allowed, ok := s.decisions.Get(tenant, principal, resource, permission); ok {
+		audit.RecordAuthorization(ctx, resource, permission, allowed)
+		return allowed, nil
+	}
+
+	iamResource := authz.TenantResource(tenant, resource)
+	ctx, span := startIAMSpan(ctx, principal, iamResource, permission)
+	start := time.Now()
+	allowed, err := s.iamClient.CheckPermission(ctx, principal, iamResource, permission)
+	s.observePermissionCheck(permission, allowed, err, time.Since(start))
+	endIAMSpan(span, allowed, err)
+	audit.RecordAuthorization(ctx, resource, permission, allowed && err == nil)
+	if err == nil {
+		s.decisions.Put(tenant, principal, resource, permission, allowed)
+	}
+	return allowed, err
+}
+
+// observePermissionCheck records an IAM check when metrics are enabled.
+func (s *Server) observePermissionCheck(permission string, allowed bool, err error, latency time.Duration) {
+	if s.metrics == nil {
+		return
+	}
+	result := metrics.ResultAllowed
+	switch {
+	case err != nil:
+		result = metrics.ResultError
+	case !allowed:
+		result = metrics.ResultDenied
+	}
+	s.metrics.ObservePermissionCheck(permission, result, latency)
+}
+
+// cacheStats reports the IAM decision cache for the metrics.
+func (s *Server) cacheStats() metrics.IAMCacheStats {
+	stats := s.decisions.Stats()
+	return metrics.IAMCacheStats{Hits: stats.Hits, Misses: stats.Misses, Entries: stats.Entries}
+}
+
+// storageStats summarizes the storage of every tenant for the metrics gauges.
+func (s *Server) storageStats() metrics.StorageStats {
+	stats := metrics.StorageStats{Versions: make(map[string]int)}
+	for _, storage := range s.tenants.All() {
+		stats.Secrets += storage.SecretCount()
+		for state, n := range storage.VersionCounts() {
+			stats.Versions[state.String()] += n
+		}
+	}
+	return stats
+}
+
 // ListSecrets lists all secrets within a project.
+// With list filtering, only secrets the caller may get are listed.
 // Implements google.cloud.secretmanager.v1.SecretManagerService.ListSecrets
 func (s *Server) ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest) (*secretmanagerpb.ListSecretsResponse, error) {
 	if req.GetParent() == "" {
@@ -99,7 +303,18 @@ func (s *Server) ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecre
 		return nil, err
 	}
 
-	secrets, token, err := s.storage.ListSecrets(ctx, req.GetParent(), req.GetPageSize(), req.GetPageToken())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, token, err := storage.ListSecrets(ctx, req.GetParent(), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	secrets, err = filterPermitted(ctx, s, "GetSecret", secrets, func(secret *secretmanagerpb.Secret) string {
+		return authz.NormalizeSecretResource(secret.GetName())
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +342,12 @@ func (s *Server) CreateSecret(ctx context.Context, req *secretmanagerpb.CreateSe
 		return nil, err
 	}
 
-	return s.storage.CreateSecret(ctx, req.GetParent(), req.GetSecretId(), req.GetSecret())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.CreateSecret(ctx, req.GetParent(), req.GetSecretId(), req.GetSecret())
 }
 
 // GetSecret retrieves secret metadata (not version data).
@@ -141,10 +361,15 @@ func (s *Server) GetSecret(ctx context.Context, req *secretmanagerpb.GetSecretRe
 		return nil, err
 	}
 
-	return s.storage.GetSecret(ctx, req.GetName())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.GetSecret(ctx, req.GetName())
 }
 
-// UpdateSecret updates secret metadata (labels, annotations).
+// UpdateSecret updates secret metadata (labels, annotations, version aliases).
 // Implements google.cloud.secretmanager.v1.SecretManagerService.UpdateSecret
 func (s *Server) UpdateSecret(ctx context.Context, req *secretmanagerpb.UpdateSecretRequest) (*secretmanagerpb.Secret, error) {
 	if req.GetSecret() == nil || req.GetSecret().GetName() == "" {
@@ -164,6 +389,7 @@ func (s *Server) UpdateSecret(ctx context.Context, req *secretmanagerpb.UpdateSe
 
 	// Parse update mask to determine which fields to update
 	var labels, annotations map[string]string
+	var versionAliases map[string]int64
 
 	for _, path := range updateMask.GetPaths() {
 		switch path {
@@ -171,12 +397,22 @@ func (s *Server) UpdateSecret(ctx context.Context, req *secretmanagerpb.UpdateSe
 			labels = req.GetSecret().GetLabels()
 		case "annotations":
 			annotations = req.GetSecret().GetAnnotations()
+		case "version_aliases":
+			versionAliases = req.GetSecret().GetVersionAliases()
+			if versionAliases == nil {
+				versionAliases = map[string]int64{} // Clearing all aliases
+			}
 		default:
 			// Ignore unsupported fields (following GCP behavior - silently skip)
 		}
 	}
 
-	return s.storage.UpdateSecret(ctx, secretName, labels, annotations)
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.UpdateSecret(ctx, secretName, labels, annotations, versionAliases)
 }
 
 // DeleteSecret deletes a secret and all its versions.
@@ -190,11 +426,20 @@ func (s *Server) DeleteSecret(ctx context.Context, req *secretmanagerpb.DeleteSe
 		return nil, err
 	}
 
-	err := s.storage.DeleteSecret(ctx, req.GetName())
+	storage, err := s.tenantStorage(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	err = storage.DeleteSecret(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	// The secret's IAM policy goes with it, so a secret recreated under the
+	// same name must not inherit cached decisions
+	s.decisions.Invalidate(TenantFromContext(ctx), authz.NormalizeSecretResource(req.GetName()))
+
 	return &emptypb.Empty{}, nil
 }
 
@@ -212,7 +457,12 @@ func (s *Server) AddSecretVersion(ctx context.Context, req *secretmanagerpb.AddS
 		return nil, err
 	}
 
-	return s.storage.AddSecretVersion(ctx, req.GetParent(), req.GetPayload())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.AddSecretVersion(ctx, req.GetParent(), req.GetPayload())
 }
 
 // GetSecretVersion retrieves version metadata (not payload).
@@ -226,7 +476,12 @@ func (s *Server) GetSecretVersion(ctx context.Context, req *secretmanagerpb.GetS
 		return nil, err
 	}
 
-	return s.storage.GetSecretVersion(ctx, req.GetName())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.GetSecretVersion(ctx, req.GetName())
 }
 
 // AccessSecretVersion retrieves the payload data for a specific version.
@@ -241,12 +496,18 @@ func (s *Server) AccessSecretVersion(ctx context.Context, req *secretmanagerpb.A
 		return nil, err
 	}
 
-	return s.storage.AccessSecretVersion(ctx, req.GetName())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.AccessSecretVersion(ctx, req.GetName())
 }
 
 // ListSecretVersions lists all versions of a secret.
 // Supports pagination via page_size and page_token.
 // Supports filtering by state via filter parameter (e.g., "state:ENABLED").
+// With list filtering, only versions the caller may get are listed.
 // Implements google.cloud.secretmanager.v1.SecretManagerService.ListSecretVersions
 func (s *Server) ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest) (*secretmanagerpb.ListSecretVersionsResponse, error) {
 	if req.GetParent() == "" {
@@ -257,7 +518,18 @@ func (s *Server) ListSecretVersions(ctx context.Context, req *secretmanagerpb.Li
 		return nil, err
 	}
 
-	versions, token, err := s.storage.ListSecretVersions(ctx, req.GetParent(), req.GetPageSize(), req.GetPageToken(), req.GetFilter())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, token, err := storage.ListSecretVersions(ctx, req.GetParent(), req.GetPageSize(), req.GetPageToken(), req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	versions, err = filterPermitted(ctx, s, "GetSecretVersion", versions, func(version *secretmanagerpb.SecretVersion) string {
+		return authz.NormalizeSecretVersionResource(version.GetName())
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +551,12 @@ func (s *Server) EnableSecretVersion(ctx context.Context, req *secretmanagerpb.E
 		return nil, err
 	}
 
-	return s.storage.EnableSecretVersion(ctx, req.GetName())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.EnableSecretVersion(ctx, req.GetName())
 }
 
 // DisableSecretVersion disables a version (prevents access).
@@ -294,7 +571,12 @@ func (s *Server) DisableSecretVersion(ctx context.Context, req *secretmanagerpb.
 		return nil, err
 	}
 
-	return s.storage.DisableSecretVersion(ctx, req.GetName())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.DisableSecretVersion(ctx, req.GetName())
 }
 
 // DestroySecretVersion permanently destroys a version.
@@ -309,14 +591,53 @@ func (s *Server) DestroySecretVersion(ctx context.Context, req *secretmanagerpb.
 	}
 
 	// Note: etag is optional and not enforced in this implementation
-	return s.storage.DestroySecretVersion(ctx, req.GetName())
+	storage, err := s.tenantStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.DestroySecretVersion(ctx, req.GetName())
 }
 
-// IAM methods are not implemented in MVP (no authentication/authorization in mock).
-// These are optional for the Secret Manager service and vaultmux doesn't use them.
-// If needed in the future, implement using google.iam.v1 package types.
+// GetIamPolicy is not served: policies live in the IAM emulator, not on
+// secrets. The permission check still runs, so strict mode denies callers
+// without secretmanager.secrets.getIamPolicy before UNIMPLEMENTED is returned.
+// Implements google.cloud.secretmanager.v1.SecretManagerService.GetIamPolicy
+func (s *Server) GetIamPolicy(ctx context.Context, req *iampb.GetIamPolicyRequest) (*iampb.Policy, error) {
+	if err := s.checkPermission(ctx, "GetIamPolicy", authz.NormalizeSecretResource(req.GetResource())); err != nil {
+		return nil, err
+	}
+	return nil, status.Error(codes.Unimplemented, "GetIamPolicy is not supported; manage policies in the IAM emulator")
+}
+
+// SetIamPolicy is not served, as GetIamPolicy. TestIamPermissions needs no
+// permission and is left unimplemented.
+// Implements google.cloud.secretmanager.v1.SecretManagerService.SetIamPolicy
+func (s *Server) SetIamPolicy(ctx context.Context, req *iampb.SetIamPolicyRequest) (*iampb.Policy, error) {
+	if err := s.checkPermission(ctx, "SetIamPolicy", authz.NormalizeSecretResource(req.GetResource())); err != nil {
+		return nil, err
+	}
+	return nil, status.Error(codes.Unimplemented, "SetIamPolicy is not supported; manage policies in the IAM emulator")
+}
 
-// Storage returns the underlying storage (useful for testing).
+// Storage returns the default tenant's storage (useful for testing).
 func (s *Server) Storage() *Storage {
-	return s.storage
+	return s.tenants.Default()
+}
+
+// Tenants returns the storage of every tenant.
+func (s *Server) Tenants() *Tenants {
+	return s.tenants
+}
+
+// PermissionCache returns the IAM decision cache, or nil if decisions are
+// not cached.
+func (s *Server) PermissionCache() *authz.DecisionCache {
+	return s.decisions
+}
+
+// tenantStorage returns the storage of the tenant an incoming call selects
+// with TenantMetadataKey.
+func (s *Server) tenantStorage(ctx context.Context) (*Storage, error) {
+	return s.tenants.FromContext(ctx)
 }
@@ -3,12 +3,28 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"cloud.google.com/go/iam/apiv1/iampb"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/authz"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/metrics"
 )
 
 func TestServer_CreateSecret(t *testing.T) {
@@ -1318,3 +1334,416 @@ func TestServer_VersionStateManagement(t *testing.T) {
 		}
 	})
 }
+
+func TestServer_StorageMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := metrics.New()
+	server, err := NewServer(WithMetrics(m))
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	secret, err := server.CreateSecret(ctx, &secretmanagerpb.CreateSecretRequest{
+		Parent:   "projects/test-project",
+		SecretId: "measured",
+		Secret:   &secretmanagerpb.Secret{},
+	})
+	if err != nil {
+		t.Fatalf("CreateSecret() failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := server.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+			Parent:  secret.Name,
+			Payload: &secretmanagerpb.SecretPayload{Data: []byte("data")},
+		}); err != nil {
+			t.Fatalf("AddSecretVersion() failed: %v", err)
+		}
+	}
+	if _, err := server.DisableSecretVersion(ctx, &secretmanagerpb.DisableSecretVersionRequest{
+		Name: secret.Name + "/versions/1",
+	}); err != nil {
+		t.Fatalf("DisableSecretVersion() failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		"secretmanager_emulator_secrets 1",
+		`secretmanager_emulator_secret_versions{state="ENABLED"} 1`,
+		`secretmanager_emulator_secret_versions{state="DISABLED"} 1`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestServer_CheckIAM(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closed := lis.Addr().String()
+	lis.Close()
+
+	// closed is an address nothing listens on any more
+	tests := []struct {
+		mode    string
+		host    string
+		wantErr bool
+	}{
+		{"off", closed, false},
+		{"permissive", closed, false},
+		{"strict", closed, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Setenv("IAM_MODE", tt.mode)
+			t.Setenv("IAM_EMULATOR_HOST", tt.host)
+			server, err := NewServer()
+			if err != nil {
+				t.Fatalf("NewServer failed: %v", err)
+			}
+			if err := server.CheckIAM(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("CheckIAM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("strict_reachable", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Failed to listen: %v", err)
+		}
+		defer lis.Close()
+
+		t.Setenv("IAM_MODE", "strict")
+		t.Setenv("IAM_EMULATOR_HOST", lis.Addr().String())
+		server, err := NewServer()
+		if err != nil {
+			t.Fatalf("NewServer failed: %v", err)
+		}
+		if err := server.CheckIAM(context.Background()); err != nil {
+			t.Errorf("CheckIAM() error = %v, want nil", err)
+		}
+	})
+}
+
+func TestPermissionMappings(t *testing.T) {
+	// Every registered method must map to a permission, or strict mode
+	// denies it
+	for _, method := range secretmanagerpb.SecretManagerService_ServiceDesc.Methods {
+		_, mapped := authz.GetPermission(method.MethodName)
+		if !mapped && !authz.UncheckedOperations[method.MethodName] {
+			t.Errorf("method %s has no IAM permission mapping", method.MethodName)
+		}
+	}
+	for _, method := range secretmanagerpb.SecretManagerService_ServiceDesc.Streams {
+		t.Errorf("streaming method %s is not covered by checkPermission", method.StreamName)
+	}
+
+	seen := make(map[string]bool)
+	for _, perm := range authz.Catalog {
+		if !strings.HasPrefix(perm.Name, "secretmanager.") || seen[perm.Name] {
+			t.Errorf("catalog permission %q is invalid or listed twice", perm.Name)
+		}
+		seen[perm.Name] = true
+	}
+	for _, name := range []string{"secretmanager.secrets.getIamPolicy", "secretmanager.secrets.setIamPolicy", "secretmanager.locations.list"} {
+		if !seen[name] {
+			t.Errorf("catalog is missing %s", name)
+		}
+	}
+}
+
+func TestServer_CheckPermissionUnmapped(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closed := lis.Addr().String()
+	lis.Close()
+
+	tests := []struct {
+		mode      string
+		operation string
+		want      codes.Code
+	}{
+		{"strict", "ListWidgets", codes.PermissionDenied},
+		{"strict", "TestIamPermissions", codes.OK},
+		{"permissive", "ListWidgets", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+"_"+tt.operation, func(t *testing.T) {
+			t.Setenv("IAM_MODE", tt.mode)
+			t.Setenv("IAM_EMULATOR_HOST", closed)
+			server, err := NewServer()
+			if err != nil {
+				t.Fatalf("NewServer failed: %v", err)
+			}
+			if err := server.checkPermission(context.Background(), tt.operation, "projects/p"); status.Code(err) != tt.want {
+				t.Errorf("checkPermission(%s) error = %v, want %v", tt.operation, err, tt.want)
+			}
+		})
+	}
+}
+
+// fakeIAM is an IAM emulator granting every permission to one principal,
+// except on the denied resources, and counting the checks it answers and
+// recording the resources checked.
+type fakeIAM struct {
+	iampb.UnimplementedIAMPolicyServer
+	principal string
+	denied    []string
+	checks    atomic.Int32
+
+	mu        sync.Mutex
+	resources []string
+}
+
+func (f *fakeIAM) TestIamPermissions(ctx context.Context, req *iampb.TestIamPermissionsRequest) (*iampb.TestIamPermissionsResponse, error) {
+	f.checks.Add(1)
+	f.mu.Lock()
+	f.resources = append(f.resources, req.GetResource())
+	f.mu.Unlock()
+	if emulatorauth.ExtractPrincipalFromContext(ctx) != f.principal || slices.Contains(f.denied, req.GetResource()) {
+		return &iampb.TestIamPermissionsResponse{}, nil
+	}
+	return &iampb.TestIamPermissionsResponse{Permissions: req.GetPermissions()}, nil
+}
+
+// startFakeIAM serves iam on a local port and points the emulator at it in
+// strict mode.
+func startFakeIAM(t *testing.T, iam *fakeIAM) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	iampb.RegisterIAMPolicyServer(grpcServer, iam)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	t.Setenv("IAM_MODE", "strict")
+	t.Setenv("IAM_EMULATOR_HOST", lis.Addr().String())
+}
+
+func TestServer_PermissionCache(t *testing.T) {
+	iam := &fakeIAM{principal: "user:alice@example.com"}
+	startFakeIAM(t, iam)
+	c := clock.NewFrozen(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := metrics.New()
+	server, err := NewServer(WithClock(c), WithMetrics(m), WithPermissionCache(time.Minute))
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:alice@example.com"))
+	bob := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:bob@example.com"))
+
+	if _, err := server.CreateSecret(alice, &secretmanagerpb.CreateSecretRequest{Parent: "projects/p", SecretId: "db", Secret: &secretmanagerpb.Secret{}}); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if _, err := server.GetSecret(alice, &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/db"}); err != nil {
+			t.Fatalf("GetSecret() error = %v", err)
+		}
+		if _, err := server.GetSecret(bob, &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/db"}); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("GetSecret() as bob error = %v, want PermissionDenied", err)
+		}
+	}
+	if n := iam.checks.Load(); n != 3 {
+		t.Errorf("IAM answered %d checks, want 3 (create, and get for each principal)", n)
+	}
+
+	// Deleting the secret drops its decisions; expiry drops the rest
+	if _, err := server.DeleteSecret(alice, &secretmanagerpb.DeleteSecretRequest{Name: "projects/p/secrets/db"}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = server.GetSecret(alice, &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/db"})
+	if n := iam.checks.Load(); n != 5 {
+		t.Errorf("IAM answered %d checks after DeleteSecret, want 5", n)
+	}
+	_ = c.Advance(time.Minute)
+	_, _ = server.GetSecret(bob, &secretmanagerpb.GetSecretRequest{Name: "projects/p/secrets/db"})
+	if n := iam.checks.Load(); n != 6 {
+		t.Errorf("IAM answered %d checks after the TTL, want 6", n)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`secretmanager_emulator_iam_cache_requests_total{result="hit"} 4`,
+		`secretmanager_emulator_iam_cache_requests_total{result="miss"} 6`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestServer_CheckPermissions(t *testing.T) {
+	iam := &fakeIAM{principal: "user:alice@example.com"}
+	startFakeIAM(t, iam)
+	server, err := NewServer(WithPermissionCache(time.Minute))
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:alice@example.com"))
+	bob := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:bob@example.com"))
+	resources := []string{"projects/p/secrets/a", "projects/p/secrets/b", "projects/p/secrets/a"}
+
+	allowed, err := server.checkPermissions(alice, "GetSecret", resources)
+	if err != nil || len(allowed) != 3 || !allowed[0] || !allowed[1] || !allowed[2] {
+		t.Errorf("checkPermissions() as alice = %v, %v; want all allowed", allowed, err)
+	}
+	allowed, err = server.checkPermissions(bob, "GetSecret", resources)
+	if err != nil || allowed[0] || allowed[1] || allowed[2] {
+		t.Errorf("checkPermissions() as bob = %v, %v; want none allowed", allowed, err)
+	}
+	// Duplicates are checked once, and repeated batches come from the cache
+	if _, err := server.checkPermissions(alice, "GetSecret", resources); err != nil {
+		t.Fatal(err)
+	}
+	if n := iam.checks.Load(); n != 4 {
+		t.Errorf("IAM answered %d checks, want 4", n)
+	}
+	if _, err := server.checkPermissions(alice, "ListWidgets", resources); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("checkPermissions() of unmapped operation error = %v, want PermissionDenied", err)
+	}
+}
+
+func TestServer_ListFiltersPermitted(t *testing.T) {
+	iam := &fakeIAM{
+		principal: "user:alice@example.com",
+		denied:    []string{"projects/p/secrets/b", "projects/p/secrets/a/versions/2"},
+	}
+	startFakeIAM(t, iam)
+	server, err := NewServer(WithPermissionCache(time.Minute), WithListFiltering())
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:alice@example.com"))
+
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := server.CreateSecret(alice, &secretmanagerpb.CreateSecretRequest{Parent: "projects/p", SecretId: id, Secret: &secretmanagerpb.Secret{}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 3 {
+		if _, err := server.AddSecretVersion(alice, &secretmanagerpb.AddSecretVersionRequest{Parent: "projects/p/secrets/a", Payload: &secretmanagerpb.SecretPayload{Data: []byte("v")}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	secrets, err := server.ListSecrets(alice, &secretmanagerpb.ListSecretsRequest{Parent: "projects/p"})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	var names []string
+	for _, secret := range secrets.GetSecrets() {
+		names = append(names, secret.GetName())
+	}
+	slices.Sort(names)
+	if want := []string{"projects/p/secrets/a", "projects/p/secrets/c"}; !slices.Equal(names, want) {
+		t.Errorf("ListSecrets() = %v, want %v", names, want)
+	}
+
+	// A filtered page is shorter, but the next page still follows
+	page, err := server.ListSecrets(alice, &secretmanagerpb.ListSecretsRequest{Parent: "projects/p", PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	next, err := server.ListSecrets(alice, &secretmanagerpb.ListSecretsRequest{Parent: "projects/p", PageSize: 2, PageToken: page.GetNextPageToken()})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if n := len(page.GetSecrets()) + len(next.GetSecrets()); n != 2 || page.GetNextPageToken() == "" {
+		t.Errorf("ListSecrets() pages hold %d secrets (next page %q), want 2 over two pages", n, page.GetNextPageToken())
+	}
+
+	versions, err := server.ListSecretVersions(alice, &secretmanagerpb.ListSecretVersionsRequest{Parent: "projects/p/secrets/a"})
+	if err != nil {
+		t.Fatalf("ListSecretVersions() error = %v", err)
+	}
+	names = nil
+	for _, version := range versions.GetVersions() {
+		names = append(names, version.GetName())
+	}
+	slices.Sort(names)
+	if want := []string{"projects/p/secrets/a/versions/1", "projects/p/secrets/a/versions/3"}; !slices.Equal(names, want) {
+		t.Errorf("ListSecretVersions() = %v, want %v", names, want)
+	}
+
+	// Without list filtering only the list permission on the parent counts,
+	// as in Secret Manager
+	unfiltered, err := NewServer()
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if _, err := unfiltered.CreateSecret(alice, &secretmanagerpb.CreateSecretRequest{Parent: "projects/p", SecretId: id, Secret: &secretmanagerpb.Secret{}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checks := iam.checks.Load()
+	secrets, err = unfiltered.ListSecrets(alice, &secretmanagerpb.ListSecretsRequest{Parent: "projects/p"})
+	if err != nil || len(secrets.GetSecrets()) != 2 {
+		t.Errorf("ListSecrets() without filtering = %v, %v; want 2 secrets", secrets, err)
+	}
+	if n := iam.checks.Load() - checks; n != 1 {
+		t.Errorf("ListSecrets() without filtering made %d IAM checks, want 1", n)
+	}
+}
+
+func TestServer_IamPolicyChecked(t *testing.T) {
+	iam := &fakeIAM{principal: "user:alice@example.com"}
+	startFakeIAM(t, iam)
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:alice@example.com"))
+	bob := metadata.NewIncomingContext(context.Background(), metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:bob@example.com"))
+	resource := "projects/p/secrets/db"
+
+	// Strict mode checks the permission, then the method is not served
+	if _, err := server.GetIamPolicy(alice, &iampb.GetIamPolicyRequest{Resource: resource}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("GetIamPolicy() as alice error = %v, want Unimplemented", err)
+	}
+	if _, err := server.SetIamPolicy(alice, &iampb.SetIamPolicyRequest{Resource: resource}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("SetIamPolicy() as alice error = %v, want Unimplemented", err)
+	}
+	if _, err := server.GetIamPolicy(bob, &iampb.GetIamPolicyRequest{Resource: resource}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("GetIamPolicy() as bob error = %v, want PermissionDenied", err)
+	}
+	if _, err := server.SetIamPolicy(bob, &iampb.SetIamPolicyRequest{Resource: resource}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("SetIamPolicy() as bob error = %v, want PermissionDenied", err)
+	}
+}
+
+func TestServer_TenantPermissions(t *testing.T) {
+	iam := &fakeIAM{principal: "user:alice@example.com"}
+	startFakeIAM(t, iam)
+	server, err := NewServer(WithPermissionCache(time.Minute))
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	alice := metadata.Pairs(emulatorauth.PrincipalMetadataKey, "user:alice@example.com")
+	inDefault := metadata.NewIncomingContext(context.Background(), alice)
+	inTenant := metadata.NewIncomingContext(context.Background(), metadata.Join(alice, metadata.Pairs(TenantMetadataKey, "job-42")))
+
+	for _, ctx := range []context.Context{inDefault, inTenant, inTenant} {
+		if _, err := server.ListSecrets(ctx, &secretmanagerpb.ListSecretsRequest{Parent: "projects/p"}); err != nil {
+			t.Fatalf("ListSecrets() error = %v", err)
+		}
+	}
+
+	// Each tenant has its own IAM resources and cached decisions
+	want := []string{"projects/p", "tenants/job-42/projects/p"}
+	if !slices.Equal(iam.resources, want) {
+		t.Errorf("IAM checked %v, want %v", iam.resources, want)
+	}
+}
@@ -7,21 +7,69 @@ package server
 import (
 	"context"
 	"fmt"
+	"hash/crc32"
+	"maps"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
 )
 
+// crc32cTable is the Castagnoli table used for payload checksums (data_crc32c).
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
 // Storage is the in-memory storage for secrets and versions.
 // All operations are thread-safe using sync.RWMutex.
+//
+// Stored secrets are never modified in place: writes store a modified copy
+// in a persistent map, so checkpoints can share the state they save.
 type Storage struct {
-	mu      sync.RWMutex
-	secrets map[string]*StoredSecret // key: "projects/{project}/secrets/{secret-id}"
+	mu       sync.RWMutex
+	secrets  secretMap // key: "projects/{project}/secrets/{secret-id}"
+	expiring secretMap // the secrets that have an expire time, by expiryKey
+	clock    clock.Clock
+
+	// One timer, for the secret that expires first
+	expiryTimer clock.Timer
+	expiryKey   string // key in expiring the timer is armed for
+	expiryGen   int    // incremented when the timer is replaced
+
+	checkpoints    map[string]*checkpoint
+	nextCheckpoint int
+
+	deleted bool // the tenant owning the storage was deleted
+}
+
+// errTenantDeleted fails writes to the storage of a deleted tenant, made by
+// calls that were in flight when it was deleted.
+var errTenantDeleted = status.Error(codes.Aborted, "Tenant was deleted; retry to act in a new tenant")
+
+// checkWritable fails writes once the storage's tenant was deleted. s.mu must
+// be held.
+func (s *Storage) checkWritable() error {
+	if s.deleted {
+		return errTenantDeleted
+	}
+	return nil
+}
+
+// markDeleted empties the storage of a deleted tenant and makes later writes
+// fail.
+func (s *Storage) markDeleted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = true
+	s.secrets, s.expiring = secretMap{}, secretMap{}
+	s.armExpiry()
 }
 
 // StoredSecret represents a secret with all its versions in memory.
@@ -32,6 +80,10 @@ type StoredSecret struct {
 	Labels      map[string]string
 	Annotations map[string]string
 	Replication *secretmanagerpb.Replication
+	ExpireTime  *timestamppb.Timestamp // When the secret expires; nil if it never does
+
+	// VersionAliases maps alias names to version numbers (e.g. "current" -> 3).
+	VersionAliases map[string]int64
 
 	// Version management
 	Versions    map[string]*StoredVersion // key: "1", "2", "3", etc. (not "latest")
@@ -49,29 +101,52 @@ type StoredVersion struct {
 	Payload []byte // The secret content
 }
 
-// NewStorage creates a new empty storage instance.
+// NewStorage creates a new empty storage instance using the system clock.
 func NewStorage() *Storage {
+	return NewStorageWithClock(clock.Real{})
+}
+
+// NewStorageWithClock creates a new empty storage instance that takes create
+// times from c and expires secrets when c reaches their expire time.
+func NewStorageWithClock(c clock.Clock) *Storage {
 	return &Storage{
-		secrets: make(map[string]*StoredSecret),
+		clock:       c,
+		checkpoints: make(map[string]*checkpoint),
 	}
 }
 
+// Clock returns the clock storage takes its timestamps from.
+func (s *Storage) Clock() clock.Clock {
+	return s.clock
+}
+
 // CreateSecret creates a new secret (metadata only, no versions yet).
 // Returns AlreadyExists if secret already exists.
 func (s *Storage) CreateSecret(ctx context.Context, parent, secretID string, secret *secretmanagerpb.Secret) (*secretmanagerpb.Secret, error) {
+	span := startStorageSpan(ctx, "CreateSecret", parent)
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 
 	// Build full resource name
 	secretName := fmt.Sprintf("%s/secrets/%s", parent, secretID)
 
 	// Check if already exists
-	if _, exists := s.secrets[secretName]; exists {
+	if _, exists := s.secrets.Get(secretName); exists {
 		return nil, status.Errorf(codes.AlreadyExists, "Secret [%s] already exists", secretName)
 	}
 
+	// A new secret has no versions for aliases to point at
+	if len(secret.GetVersionAliases()) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Secret [%s] has no versions to alias", secretName)
+	}
+
 	// Create stored secret
-	now := timestamppb.Now()
+	now := timestamppb.New(s.clock.Now())
 	stored := &StoredSecret{
 		Name:        secretName,
 		CreateTime:  now,
@@ -82,6 +157,14 @@ func (s *Storage) CreateSecret(ctx context.Context, parent, secretID string, sec
 		NextVersion: 1,
 	}
 
+	// A TTL is converted to the absolute time it expires at, as GCP does
+	switch {
+	case secret.GetTtl() != nil:
+		stored.ExpireTime = timestamppb.New(now.AsTime().Add(secret.GetTtl().AsDuration()))
+	case secret.GetExpireTime() != nil:
+		stored.ExpireTime = secret.GetExpireTime()
+	}
+
 	// Default to automatic replication if not specified
 	if stored.Replication == nil {
 		stored.Replication = &secretmanagerpb.Replication{
@@ -91,41 +174,144 @@ func (s *Storage) CreateSecret(ctx context.Context, parent, secretID string, sec
 		}
 	}
 
-	s.secrets[secretName] = stored
+	s.put(stored)
 
 	// Return secret metadata
-	return &secretmanagerpb.Secret{
-		Name:        secretName,
-		CreateTime:  now,
-		Labels:      stored.Labels,
-		Annotations: stored.Annotations,
-		Replication: stored.Replication,
-	}, nil
+	return stored.toProto(), nil
+}
+
+// put stores a secret, replacing the secret of the same name.
+// Must be called with write lock held.
+func (s *Storage) put(stored *StoredSecret) {
+	s.unlink(stored.Name)
+	s.secrets = s.secrets.Set(stored.Name, stored)
+	if stored.ExpireTime != nil {
+		s.expiring = s.expiring.Set(expiryKey(stored), stored)
+	}
+	s.armExpiry()
+}
+
+// remove deletes a secret.
+// Must be called with write lock held.
+func (s *Storage) remove(secretName string) {
+	s.unlink(secretName)
+	s.armExpiry()
+}
+
+// unlink deletes a secret without rearming the expiry timer.
+// Must be called with write lock held.
+func (s *Storage) unlink(secretName string) {
+	stored, ok := s.secrets.Get(secretName)
+	if !ok {
+		return
+	}
+	s.secrets = s.secrets.Delete(secretName)
+	if stored.ExpireTime != nil {
+		s.expiring = s.expiring.Delete(expiryKey(stored))
+	}
+}
+
+// expiryKey orders secrets in expiring by expire time, then name.
+func expiryKey(stored *StoredSecret) string {
+	t := stored.ExpireTime
+	// Flipping the sign bit orders negative seconds before positive ones
+	return fmt.Sprintf("%016x%08x/%s", uint64(t.GetSeconds())^1<<63, t.GetNanos(), stored.Name)
+}
+
+// armExpiry arms the timer deleting secrets as they expire, as GCP does, for
+// the secret that expires first. Storage holds a single timer, so replacing
+// the state (Restore, Import, Reset) costs no more than a write.
+// Must be called with write lock held.
+func (s *Storage) armExpiry() {
+	key, first, ok := s.expiring.Min()
+	if s.expiryTimer != nil {
+		if ok && key == s.expiryKey {
+			return
+		}
+		s.expiryTimer.Stop()
+		s.expiryTimer, s.expiryKey = nil, ""
+	}
+	if !ok {
+		return
+	}
+
+	s.expiryGen++
+	gen := s.expiryGen
+	s.expiryKey = key
+	s.expiryTimer = s.clock.AfterFunc(first.ExpireTime.AsTime().Sub(s.clock.Now()), func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// A timer stopped too late to keep it from firing is harmless
+		if gen == s.expiryGen {
+			s.expiryTimer, s.expiryKey = nil, ""
+		}
+		s.expireDue()
+	})
+}
+
+// expireDue deletes the secrets whose expire time the clock has reached and
+// arms the timer for the next one.
+// Must be called with write lock held.
+func (s *Storage) expireDue() {
+	now := s.clock.Now()
+	for {
+		_, first, ok := s.expiring.Min()
+		if !ok || now.Before(first.ExpireTime.AsTime()) {
+			break
+		}
+		s.unlink(first.Name)
+	}
+	s.armExpiry()
+}
+
+// copyForWrite returns a copy of the secret that can be modified and stored
+// in its place. Versions are shared with the original, so a version must be
+// replaced, not modified.
+func (stored *StoredSecret) copyForWrite() *StoredSecret {
+	c := *stored
+	c.Versions = maps.Clone(stored.Versions)
+	return &c
+}
+
+// toProto returns the secret metadata as a Secret message.
+func (stored *StoredSecret) toProto() *secretmanagerpb.Secret {
+	secret := &secretmanagerpb.Secret{
+		Name:           stored.Name,
+		CreateTime:     stored.CreateTime,
+		Labels:         stored.Labels,
+		Annotations:    stored.Annotations,
+		Replication:    stored.Replication,
+		VersionAliases: stored.VersionAliases,
+	}
+	if stored.ExpireTime != nil {
+		secret.Expiration = &secretmanagerpb.Secret_ExpireTime{ExpireTime: stored.ExpireTime}
+	}
+	return secret
 }
 
 // GetSecret retrieves secret metadata (not version data).
 // Returns NotFound if secret doesn't exist.
 func (s *Storage) GetSecret(ctx context.Context, secretName string) (*secretmanagerpb.Secret, error) {
+	span := startStorageSpan(ctx, "GetSecret", secretName)
+	defer span.End()
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	stored, exists := s.secrets[secretName]
+	stored, exists := s.secrets.Get(secretName)
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found", secretName)
 	}
 
-	return &secretmanagerpb.Secret{
-		Name:        stored.Name,
-		CreateTime:  stored.CreateTime,
-		Labels:      stored.Labels,
-		Annotations: stored.Annotations,
-		Replication: stored.Replication,
-	}, nil
+	return stored.toProto(), nil
 }
 
 // ListSecrets returns all secrets under the parent project.
 // Supports pagination via pageSize and pageToken.
 func (s *Storage) ListSecrets(ctx context.Context, parent string, pageSize int32, pageToken string) ([]*secretmanagerpb.Secret, string, error) {
+	span := startStorageSpan(ctx, "ListSecrets", parent)
+	defer span.End()
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -133,15 +319,9 @@ func (s *Storage) ListSecrets(ctx context.Context, parent string, pageSize int32
 	var allSecrets []*secretmanagerpb.Secret
 	prefix := parent + "/secrets/"
 
-	for name, stored := range s.secrets {
+	for name, stored := range s.secrets.All() {
 		if strings.HasPrefix(name, prefix) {
-			allSecrets = append(allSecrets, &secretmanagerpb.Secret{
-				Name:        stored.Name,
-				CreateTime:  stored.CreateTime,
-				Labels:      stored.Labels,
-				Annotations: stored.Annotations,
-				Replication: stored.Replication,
-			})
+			allSecrets = append(allSecrets, stored.toProto())
 		}
 	}
 
@@ -175,67 +355,99 @@ func (s *Storage) ListSecrets(ctx context.Context, parent string, pageSize int32
 	return results, "", nil
 }
 
-// UpdateSecret updates mutable fields of a secret (labels, annotations).
+// UpdateSecret updates mutable fields of a secret (labels, annotations, version aliases).
+// Nil maps leave the corresponding field unchanged.
 // Returns NotFound if secret doesn't exist.
-func (s *Storage) UpdateSecret(ctx context.Context, secretName string, labels, annotations map[string]string) (*secretmanagerpb.Secret, error) {
+// Returns InvalidArgument if a version alias points at a missing version.
+func (s *Storage) UpdateSecret(ctx context.Context, secretName string, labels, annotations map[string]string, versionAliases map[string]int64) (*secretmanagerpb.Secret, error) {
+	span := startStorageSpan(ctx, "UpdateSecret", secretName)
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 
-	stored, exists := s.secrets[secretName]
+	stored, exists := s.secrets.Get(secretName)
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found", secretName)
 	}
 
+	// Aliases must point at existing versions
+	for alias, version := range versionAliases {
+		if _, ok := stored.Versions[fmt.Sprintf("%d", version)]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Version alias [%s] refers to version [%d] which does not exist", alias, version)
+		}
+	}
+
 	// Update mutable fields
+	stored = stored.copyForWrite()
 	if labels != nil {
 		stored.Labels = labels
 	}
 	if annotations != nil {
 		stored.Annotations = annotations
 	}
+	if versionAliases != nil {
+		stored.VersionAliases = versionAliases
+	}
+	s.put(stored)
 
 	// Return updated secret
-	return &secretmanagerpb.Secret{
-		Name:        stored.Name,
-		CreateTime:  stored.CreateTime,
-		Labels:      stored.Labels,
-		Annotations: stored.Annotations,
-		Replication: stored.Replication,
-	}, nil
+	return stored.toProto(), nil
 }
 
 // DeleteSecret deletes a secret and all its versions.
 // Returns NotFound if secret doesn't exist.
 func (s *Storage) DeleteSecret(ctx context.Context, secretName string) error {
+	span := startStorageSpan(ctx, "DeleteSecret", secretName)
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 
-	if _, exists := s.secrets[secretName]; !exists {
+	if _, exists := s.secrets.Get(secretName); !exists {
 		return status.Errorf(codes.NotFound, "Secret [%s] not found", secretName)
 	}
 
-	delete(s.secrets, secretName)
+	s.remove(secretName)
 	return nil
 }
 
 // AddSecretVersion adds a new version to an existing secret.
 // Returns NotFound if secret doesn't exist.
 func (s *Storage) AddSecretVersion(ctx context.Context, parent string, payload *secretmanagerpb.SecretPayload) (*secretmanagerpb.SecretVersion, error) {
+	span := startStorageSpan(ctx, "AddSecretVersion", parent)
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 
 	// Parent is the secret name
-	stored, exists := s.secrets[parent]
+	stored, exists := s.secrets.Get(parent)
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found", parent)
 	}
 
+	// Verify the client-supplied checksum before storing anything
+	if payload.DataCrc32C != nil && payload.GetDataCrc32C() != int64(crc32.Checksum(payload.GetData(), crc32cTable)) {
+		return nil, status.Error(codes.InvalidArgument, "Checksum mismatch: payload data does not match data_crc32c")
+	}
+
 	// Generate version number
+	stored = stored.copyForWrite()
 	versionID := fmt.Sprintf("%d", stored.NextVersion)
 	stored.NextVersion++
 
 	// Create version
-	now := timestamppb.Now()
+	now := timestamppb.New(s.clock.Now())
 	versionName := fmt.Sprintf("%s/versions/%s", parent, versionID)
 	version := &StoredVersion{
 		Name:       versionName,
@@ -245,6 +457,7 @@ func (s *Storage) AddSecretVersion(ctx context.Context, parent string, payload *
 	}
 
 	stored.Versions[versionID] = version
+	s.put(stored)
 
 	return &secretmanagerpb.SecretVersion{
 		Name:       versionName,
@@ -257,6 +470,9 @@ func (s *Storage) AddSecretVersion(ctx context.Context, parent string, payload *
 // Supports version aliases: "latest" resolves to highest ENABLED version.
 // Returns NotFound if secret or version doesn't exist.
 func (s *Storage) AccessSecretVersion(ctx context.Context, versionName string) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	span := startStorageSpan(ctx, "AccessSecretVersion", versionName)
+	defer span.End()
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -270,18 +486,16 @@ func (s *Storage) AccessSecretVersion(ctx context.Context, versionName string) (
 	versionID := parts[1]
 
 	// Get secret
-	stored, exists := s.secrets[secretName]
+	stored, exists := s.secrets.Get(secretName)
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found", secretName)
 	}
 
-	// Resolve "latest" alias to highest ENABLED version
-	if versionID == "latest" {
-		latestID, err := s.resolveLatestVersion(stored)
-		if err != nil {
-			return nil, err
-		}
-		versionID = latestID
+	// Resolve "latest" and user-defined aliases to a version number
+	if resolved, err := s.resolveVersionAlias(stored, versionID); err != nil {
+		return nil, err
+	} else if resolved != versionID {
+		versionID = resolved
 		versionName = fmt.Sprintf("%s/versions/%s", secretName, versionID)
 	}
 
@@ -296,14 +510,31 @@ func (s *Storage) AccessSecretVersion(ctx context.Context, versionName string) (
 		return nil, status.Errorf(codes.FailedPrecondition, "Version [%s] is not enabled (state: %s)", versionName, version.State)
 	}
 
+	// GCP always returns the CRC32C checksum so clients can verify the payload
+	crc := int64(crc32.Checksum(version.Payload, crc32cTable))
+
 	return &secretmanagerpb.AccessSecretVersionResponse{
 		Name: versionName,
 		Payload: &secretmanagerpb.SecretPayload{
-			Data: version.Payload,
+			Data:       version.Payload,
+			DataCrc32C: &crc,
 		},
 	}, nil
 }
 
+// resolveVersionAlias resolves "latest" and the secret's version aliases to a
+// version number. Other version IDs are returned unchanged.
+// Must be called with read lock held.
+func (s *Storage) resolveVersionAlias(stored *StoredSecret, versionID string) (string, error) {
+	if versionID == "latest" {
+		return s.resolveLatestVersion(stored)
+	}
+	if version, ok := stored.VersionAliases[versionID]; ok {
+		return fmt.Sprintf("%d", version), nil
+	}
+	return versionID, nil
+}
+
 // resolveLatestVersion finds the highest version number with ENABLED state.
 // Must be called with read lock held.
 func (s *Storage) resolveLatestVersion(stored *StoredSecret) (string, error) {
@@ -331,6 +562,9 @@ func (s *Storage) resolveLatestVersion(stored *StoredSecret) (string, error) {
 // GetSecretVersion retrieves version metadata (not payload).
 // Returns NotFound if secret or version doesn't exist.
 func (s *Storage) GetSecretVersion(ctx context.Context, versionName string) (*secretmanagerpb.SecretVersion, error) {
+	span := startStorageSpan(ctx, "GetSecretVersion", versionName)
+	defer span.End()
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -344,18 +578,16 @@ func (s *Storage) GetSecretVersion(ctx context.Context, versionName string) (*se
 	versionID := parts[1]
 
 	// Get secret
-	stored, exists := s.secrets[secretName]
+	stored, exists := s.secrets.Get(secretName)
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found", secretName)
 	}
 
-	// Resolve "latest" if needed
-	if versionID == "latest" {
-		latestID, err := s.resolveLatestVersion(stored)
-		if err != nil {
-			return nil, err
-		}
-		versionID = latestID
+	// Resolve "latest" and user-defined aliases to a version number
+	if resolved, err := s.resolveVersionAlias(stored, versionID); err != nil {
+		return nil, err
+	} else if resolved != versionID {
+		versionID = resolved
 		versionName = fmt.Sprintf("%s/versions/%s", secretName, versionID)
 	}
 
@@ -408,11 +640,14 @@ func parseStateFilter(filter string) map[secretmanagerpb.SecretVersion_State]boo
 // Supports filtering by state (e.g., "state:ENABLED", "state:DISABLED").
 // Returns NotFound if secret doesn't exist.
 func (s *Storage) ListSecretVersions(ctx context.Context, parent string, pageSize int32, pageToken, filter string) ([]*secretmanagerpb.SecretVersion, string, error) {
+	span := startStorageSpan(ctx, "ListSecretVersions", parent)
+	defer span.End()
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	// Parent is the secret name
-	stored, exists := s.secrets[parent]
+	stored, exists := s.secrets.Get(parent)
 	if !exists {
 		return nil, "", status.Errorf(codes.NotFound, "Secret [%s] not found", parent)
 	}
@@ -478,8 +713,14 @@ func (s *Storage) ListSecretVersions(ctx context.Context, parent string, pageSiz
 // Returns NotFound if secret or version doesn't exist.
 // Returns FailedPrecondition if version is already DESTROYED.
 func (s *Storage) DisableSecretVersion(ctx context.Context, versionName string) (*secretmanagerpb.SecretVersion, error) {
+	span := startStorageSpan(ctx, "DisableSecretVersion", versionName)
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 
 	// Parse resource name
 	parts := strings.Split(versionName, "/versions/")
@@ -491,7 +732,7 @@ func (s *Storage) DisableSecretVersion(ctx context.Context, versionName string)
 	versionID := parts[1]
 
 	// Get secret
-	stored, exists := s.secrets[secretName]
+	stored, exists := s.secrets.Get(secretName)
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found", secretName)
 	}
@@ -508,7 +749,9 @@ func (s *Storage) DisableSecretVersion(ctx context.Context, versionName string)
 	}
 
 	// Set state to DISABLED
-	version.State = secretmanagerpb.SecretVersion_DISABLED
+	s.putVersion(stored, versionID, func(v *StoredVersion) {
+		v.State = secretmanagerpb.SecretVersion_DISABLED
+	})
 
 	return &secretmanagerpb.SecretVersion{
 		Name:       versionName,
@@ -521,8 +764,14 @@ func (s *Storage) DisableSecretVersion(ctx context.Context, versionName string)
 // Returns NotFound if secret or version doesn't exist.
 // Returns FailedPrecondition if version is DESTROYED.
 func (s *Storage) EnableSecretVersion(ctx context.Context, versionName string) (*secretmanagerpb.SecretVersion, error) {
+	span := startStorageSpan(ctx, "EnableSecretVersion", versionName)
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 
 	// Parse resource name
 	parts := strings.Split(versionName, "/versions/")
@@ -534,7 +783,7 @@ func (s *Storage) EnableSecretVersion(ctx context.Context, versionName string) (
 	versionID := parts[1]
 
 	// Get secret
-	stored, exists := s.secrets[secretName]
+	stored, exists := s.secrets.Get(secretName)
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found", secretName)
 	}
@@ -551,7 +800,9 @@ func (s *Storage) EnableSecretVersion(ctx context.Context, versionName string) (
 	}
 
 	// Set state to ENABLED
-	version.State = secretmanagerpb.SecretVersion_ENABLED
+	s.putVersion(stored, versionID, func(v *StoredVersion) {
+		v.State = secretmanagerpb.SecretVersion_ENABLED
+	})
 
 	return &secretmanagerpb.SecretVersion{
 		Name:       versionName,
@@ -564,8 +815,14 @@ func (s *Storage) EnableSecretVersion(ctx context.Context, versionName string) (
 // Returns NotFound if secret or version doesn't exist.
 // Returns FailedPrecondition if version is already DESTROYED.
 func (s *Storage) DestroySecretVersion(ctx context.Context, versionName string) (*secretmanagerpb.SecretVersion, error) {
+	span := startStorageSpan(ctx, "DestroySecretVersion", versionName)
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 
 	// Parse resource name
 	parts := strings.Split(versionName, "/versions/")
@@ -577,7 +834,7 @@ func (s *Storage) DestroySecretVersion(ctx context.Context, versionName string)
 	versionID := parts[1]
 
 	// Get secret
-	stored, exists := s.secrets[secretName]
+	stored, exists := s.secrets.Get(secretName)
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found", secretName)
 	}
@@ -598,8 +855,10 @@ func (s *Storage) DestroySecretVersion(ctx context.Context, versionName string)
 	}
 
 	// Set state to DESTROYED and clear payload
-	version.State = secretmanagerpb.SecretVersion_DESTROYED
-	version.Payload = nil // Permanently remove the payload data
+	s.putVersion(stored, versionID, func(v *StoredVersion) {
+		v.State = secretmanagerpb.SecretVersion_DESTROYED
+		v.Payload = nil // Permanently remove the payload data
+	})
 
 	return &secretmanagerpb.SecretVersion{
 		Name:       versionName,
@@ -608,16 +867,230 @@ func (s *Storage) DestroySecretVersion(ctx context.Context, versionName string)
 	}, nil
 }
 
+// putVersion stores a copy of a secret in which a copy of one of its
+// versions has been changed by update.
+// Must be called with write lock held.
+func (s *Storage) putVersion(stored *StoredSecret, versionID string, update func(*StoredVersion)) {
+	stored = stored.copyForWrite()
+	version := *stored.Versions[versionID]
+	update(&version)
+	stored.Versions[versionID] = &version
+	s.put(stored)
+}
+
 // Clear removes all secrets from storage (useful for testing).
+// Checkpoints are kept.
 func (s *Storage) Clear() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.secrets = make(map[string]*StoredSecret)
+	s.secrets, s.expiring = secretMap{}, secretMap{}
+	s.armExpiry()
 }
 
 // SecretCount returns the number of secrets in storage (useful for testing).
 func (s *Storage) SecretCount() int {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return len(s.secrets)
+	return s.secrets.Len()
+}
+
+// VersionCounts returns the number of secret versions in each state.
+func (s *Storage) VersionCounts() map[secretmanagerpb.SecretVersion_State]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[secretmanagerpb.SecretVersion_State]int)
+	for _, secret := range s.secrets.All() {
+		for _, version := range secret.Versions {
+			counts[version.State]++
+		}
+	}
+	return counts
+}
+
+// Reset deletes every secret of a project ("projects/{project}"), or every
+// secret when parent is empty. It returns the number of secrets deleted.
+func (s *Storage) Reset(parent string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if parent == "" {
+		n := s.secrets.Len()
+		s.secrets, s.expiring = secretMap{}, secretMap{}
+		s.armExpiry()
+		return n
+	}
+
+	n := 0
+	for name := range s.secrets.All() {
+		if inProject(name, parent) {
+			s.remove(name)
+			n++
+		}
+	}
+	return n
+}
+
+// inProject reports whether a secret name belongs to a project, including
+// regional secrets (projects/{p}/locations/{l}/secrets/{s}).
+func inProject(secretName, project string) bool {
+	return strings.HasPrefix(secretName, project+"/secrets/") ||
+		strings.HasPrefix(secretName, project+"/locations/")
+}
+
+// Secrets returns copies of all secrets, with their versions and payloads,
+// ordered by name. Changes to the copies do not affect storage.
+func (s *Storage) Secrets() []*StoredSecret {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	secrets := make([]*StoredSecret, 0, s.secrets.Len())
+	for _, stored := range s.secrets.All() {
+		secrets = append(secrets, stored.clone())
+	}
+	return secrets
+}
+
+// Import adds secrets to storage as given, preserving version numbers,
+// states and timestamps. With replace, all existing secrets are deleted
+// first; otherwise a secret that already exists fails the import with
+// AlreadyExists. Either all secrets are imported or none are.
+func (s *Storage) Import(secrets []*StoredSecret, replace bool) error {
+	if err := validateImports(secrets); err != nil {
+		return err
+	}
+	imported := make([]*StoredSecret, len(secrets))
+	for i, stored := range secrets {
+		imported[i] = stored.clone()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+
+	if !replace {
+		if err := s.checkExisting(imported); err != nil {
+			return err
+		}
+	} else {
+		s.secrets, s.expiring = secretMap{}, secretMap{}
+	}
+	for _, stored := range imported {
+		s.put(stored)
+	}
+	s.armExpiry()
+	return nil
+}
+
+// ValidateImport returns the error Import would fail with, without importing
+// anything, so that imports into several storages can be checked up front.
+// A nil Storage is treated as empty.
+func (s *Storage) ValidateImport(secrets []*StoredSecret, replace bool) error {
+	if err := validateImports(secrets); err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	if replace {
+		return nil
+	}
+	return s.checkExisting(secrets)
+}
+
+// validateImports checks each imported secret with validateImport and that
+// none appears more than once.
+func validateImports(secrets []*StoredSecret) error {
+	seen := make(map[string]bool, len(secrets))
+	for _, stored := range secrets {
+		if err := validateImport(stored); err != nil {
+			return err
+		}
+		if seen[stored.Name] {
+			return status.Errorf(codes.InvalidArgument, "Secret [%s] appears more than once", stored.Name)
+		}
+		seen[stored.Name] = true
+	}
+	return nil
+}
+
+// checkExisting fails with AlreadyExists if any of secrets is already
+// stored. s.mu must be held.
+func (s *Storage) checkExisting(secrets []*StoredSecret) error {
+	for _, stored := range secrets {
+		if _, exists := s.secrets.Get(stored.Name); exists {
+			return status.Errorf(codes.AlreadyExists, "Secret [%s] already exists", stored.Name)
+		}
+	}
+	return nil
+}
+
+// validateImport checks that an imported secret is consistent: version keys
+// match version names, NextVersion is past every version, and aliases point
+// at existing versions.
+func validateImport(stored *StoredSecret) error {
+	parts := strings.Split(stored.Name, "/")
+	regional := len(parts) == 6 && parts[2] == "locations" && parts[4] == "secrets"
+	if parts[0] != "projects" || !(len(parts) == 4 && parts[2] == "secrets" || regional) {
+		return status.Errorf(codes.InvalidArgument, "Invalid secret name: %s", stored.Name)
+	}
+
+	var highest int64
+	for id, version := range stored.Versions {
+		n, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || n < 1 || version.Name != fmt.Sprintf("%s/versions/%d", stored.Name, n) {
+			return status.Errorf(codes.InvalidArgument, "Invalid version [%s] of secret [%s]", version.Name, stored.Name)
+		}
+		highest = max(highest, n)
+	}
+	if stored.NextVersion <= highest {
+		return status.Errorf(codes.InvalidArgument, "Secret [%s] next version %d must be greater than %d", stored.Name, stored.NextVersion, highest)
+	}
+	for alias, n := range stored.VersionAliases {
+		if _, ok := stored.Versions[strconv.FormatInt(n, 10)]; !ok {
+			return status.Errorf(codes.InvalidArgument, "Secret [%s] alias %q points at missing version %d", stored.Name, alias, n)
+		}
+	}
+	return nil
+}
+
+// clone returns a deep copy of the secret and its versions.
+func (stored *StoredSecret) clone() *StoredSecret {
+	c := &StoredSecret{
+		Name:           stored.Name,
+		CreateTime:     cloneTimestamp(stored.CreateTime),
+		ExpireTime:     cloneTimestamp(stored.ExpireTime),
+		Labels:         maps.Clone(stored.Labels),
+		Annotations:    maps.Clone(stored.Annotations),
+		VersionAliases: maps.Clone(stored.VersionAliases),
+		Versions:       make(map[string]*StoredVersion, len(stored.Versions)),
+		NextVersion:    stored.NextVersion,
+	}
+	if stored.Replication != nil {
+		c.Replication = proto.Clone(stored.Replication).(*secretmanagerpb.Replication)
+	}
+	for id, version := range stored.Versions {
+		c.Versions[id] = &StoredVersion{
+			Name:       version.Name,
+			CreateTime: cloneTimestamp(version.CreateTime),
+			State:      version.State,
+			Payload:    slices.Clone(version.Payload),
+		}
+	}
+	return c
+}
+
+func cloneTimestamp(ts *timestamppb.Timestamp) *timestamppb.Timestamp {
+	if ts == nil {
+		return nil
+	}
+	return proto.Clone(ts).(*timestamppb.Timestamp)
 }
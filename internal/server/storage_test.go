@@ -3,11 +3,17 @@ package server
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
 )
 
 func TestStorage_CreateSecret(t *testing.T) {
@@ -242,6 +248,17 @@ func TestStorage_AddSecretVersion(t *testing.T) {
 		}
 	})
 
+	t.Run("ChecksumMismatch", func(t *testing.T) {
+		checksum := int64(1)
+		payload := &secretmanagerpb.SecretPayload{Data: []byte("data"), DataCrc32C: &checksum}
+		_, err := storage.AddSecretVersion(ctx, "projects/test-project/secrets/my-secret", payload)
+
+		st, ok := status.FromError(err)
+		if !ok || st.Code() != codes.InvalidArgument {
+			t.Errorf("AddSecretVersion() error = %v, want InvalidArgument", err)
+		}
+	})
+
 	t.Run("SecretNotFound", func(t *testing.T) {
 		payload := &secretmanagerpb.SecretPayload{Data: []byte("data")}
 		_, err := storage.AddSecretVersion(ctx, "projects/test-project/secrets/nonexistent", payload)
@@ -329,6 +346,33 @@ func TestStorage_AccessSecretVersion(t *testing.T) {
 			t.Errorf("AccessSecretVersion() error code = %v, want NotFound", st.Code())
 		}
 	})
+
+	t.Run("VersionAlias", func(t *testing.T) {
+		_, err := storage.UpdateSecret(ctx, "projects/test-project/secrets/my-secret", nil, nil, map[string]int64{"stable": 1})
+		if err != nil {
+			t.Fatalf("UpdateSecret() error = %v", err)
+		}
+
+		response, err := storage.AccessSecretVersion(ctx, "projects/test-project/secrets/my-secret/versions/stable")
+		if err != nil {
+			t.Fatalf("AccessSecretVersion() error = %v", err)
+		}
+		if response.Name != "projects/test-project/secrets/my-secret/versions/1" {
+			t.Errorf("Name = %s, want versions/1", response.Name)
+		}
+		if response.Payload.DataCrc32C == nil {
+			t.Error("Payload.DataCrc32C is nil, want checksum")
+		}
+	})
+
+	t.Run("VersionAliasUnknownTarget", func(t *testing.T) {
+		_, err := storage.UpdateSecret(ctx, "projects/test-project/secrets/my-secret", nil, nil, map[string]int64{"stable": 999})
+
+		st, ok := status.FromError(err)
+		if !ok || st.Code() != codes.InvalidArgument {
+			t.Errorf("UpdateSecret() error = %v, want InvalidArgument", err)
+		}
+	})
 }
 
 func TestStorage_Concurrent(t *testing.T) {
@@ -392,3 +436,320 @@ func TestStorage_ClearAndCount(t *testing.T) {
 		t.Errorf("SecretCount() after Clear() = %d, want 0", count)
 	}
 }
+
+func TestStorage_Reset(t *testing.T) {
+	storage := NewStorage()
+	ctx := context.Background()
+
+	for _, parent := range []string{"projects/a", "projects/a/locations/us-east1", "projects/b"} {
+		if _, err := storage.CreateSecret(ctx, parent, "s", &secretmanagerpb.Secret{}); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+	}
+
+	if n := storage.Reset("projects/a"); n != 2 {
+		t.Errorf("Reset(projects/a) = %d, want 2", n)
+	}
+	if _, err := storage.GetSecret(ctx, "projects/b/secrets/s"); err != nil {
+		t.Errorf("Reset(projects/a) deleted projects/b: %v", err)
+	}
+
+	if n := storage.Reset(""); n != 1 {
+		t.Errorf("Reset(\"\") = %d, want 1", n)
+	}
+	if count := storage.SecretCount(); count != 0 {
+		t.Errorf("SecretCount() after Reset = %d, want 0", count)
+	}
+}
+
+func TestStorage_Import(t *testing.T) {
+	ctx := context.Background()
+	source := NewStorage()
+	if _, err := source.CreateSecret(ctx, "projects/p", "s", &secretmanagerpb.Secret{}); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := source.AddSecretVersion(ctx, "projects/p/secrets/s", &secretmanagerpb.SecretPayload{Data: []byte("v")}); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+	}
+	if _, err := source.DestroySecretVersion(ctx, "projects/p/secrets/s/versions/3"); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	secrets := source.Secrets()
+
+	t.Run("PreservesVersions", func(t *testing.T) {
+		storage := NewStorage()
+		if err := storage.Import(secrets, false); err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+
+		version, err := storage.GetSecretVersion(ctx, "projects/p/secrets/s/versions/3")
+		if err != nil {
+			t.Fatalf("GetSecretVersion() error = %v", err)
+		}
+		if version.State != secretmanagerpb.SecretVersion_DESTROYED {
+			t.Errorf("version 3 state = %v, want DESTROYED", version.State)
+		}
+
+		added, err := storage.AddSecretVersion(ctx, "projects/p/secrets/s", &secretmanagerpb.SecretPayload{Data: []byte("v")})
+		if err != nil {
+			t.Fatalf("AddSecretVersion() error = %v", err)
+		}
+		if added.Name != "projects/p/secrets/s/versions/4" {
+			t.Errorf("next version = %s, want versions/4", added.Name)
+		}
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		err := source.Import(secrets, false)
+		if status.Code(err) != codes.AlreadyExists {
+			t.Errorf("Import() error = %v, want AlreadyExists", err)
+		}
+		if err := source.Import(secrets, true); err != nil {
+			t.Errorf("Import(replace) error = %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		bad := source.Secrets()
+		bad[0].NextVersion = 2
+		err := NewStorage().Import(bad, false)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Import() error = %v, want InvalidArgument", err)
+		}
+	})
+}
+
+func TestStorage_CreateSecretExpiration(t *testing.T) {
+	storage := NewStorage()
+	ctx := context.Background()
+
+	secret, err := storage.CreateSecret(ctx, "projects/p", "ttl", &secretmanagerpb.Secret{
+		Expiration: &secretmanagerpb.Secret_Ttl{Ttl: durationpb.New(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("CreateSecret() error = %v", err)
+	}
+	// A TTL is reported as the time the secret expires
+	if got := secret.GetExpireTime().AsTime().Sub(secret.GetCreateTime().AsTime()); got != time.Hour {
+		t.Errorf("expire_time - create_time = %v, want 1h", got)
+	}
+
+	expireTime := timestamppb.New(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	secret, err = storage.CreateSecret(ctx, "projects/p", "expire-time", &secretmanagerpb.Secret{
+		Expiration: &secretmanagerpb.Secret_ExpireTime{ExpireTime: expireTime},
+	})
+	if err != nil {
+		t.Fatalf("CreateSecret() error = %v", err)
+	}
+	if !secret.GetExpireTime().AsTime().Equal(expireTime.AsTime()) {
+		t.Errorf("expire_time = %v, want %v", secret.GetExpireTime(), expireTime)
+	}
+}
+
+func TestStorage_Clock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := clock.NewFrozen(start)
+	storage := NewStorageWithClock(c)
+	ctx := context.Background()
+
+	secret, err := storage.CreateSecret(ctx, "projects/p", "ttl", &secretmanagerpb.Secret{
+		Expiration: &secretmanagerpb.Secret_Ttl{Ttl: durationpb.New(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("CreateSecret() error = %v", err)
+	}
+	if !secret.GetCreateTime().AsTime().Equal(start) {
+		t.Errorf("create_time = %v, want %v", secret.GetCreateTime().AsTime(), start)
+	}
+	_ = c.Advance(time.Minute)
+	version, err := storage.AddSecretVersion(ctx, secret.GetName(), &secretmanagerpb.SecretPayload{Data: []byte("v1")})
+	if err != nil {
+		t.Fatalf("AddSecretVersion() error = %v", err)
+	}
+	if !version.GetCreateTime().AsTime().Equal(start.Add(time.Minute)) {
+		t.Errorf("version create_time = %v, want a minute after start", version.GetCreateTime().AsTime())
+	}
+
+	// The secret is deleted once the clock reaches its expire time
+	_ = c.Advance(58 * time.Minute)
+	if _, err := storage.GetSecret(ctx, secret.GetName()); err != nil {
+		t.Fatalf("GetSecret() before expiry error = %v", err)
+	}
+	_ = c.Advance(time.Minute)
+	if _, err := storage.GetSecret(ctx, secret.GetName()); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret() after expiry error = %v, want NotFound", err)
+	}
+
+	// A secret recreated under the same name is not deleted by the old timer
+	if _, err := storage.CreateSecret(ctx, "projects/p", "recreated", &secretmanagerpb.Secret{
+		Expiration: &secretmanagerpb.Secret_Ttl{Ttl: durationpb.New(time.Hour)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.DeleteSecret(ctx, "projects/p/secrets/recreated"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.CreateSecret(ctx, "projects/p", "recreated", &secretmanagerpb.Secret{}); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.Advance(2 * time.Hour)
+	if _, err := storage.GetSecret(ctx, "projects/p/secrets/recreated"); err != nil {
+		t.Errorf("GetSecret() of recreated secret error = %v", err)
+	}
+}
+
+func TestStorage_Checkpoint(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := clock.NewFrozen(start)
+	storage := NewStorageWithClock(c)
+	ctx := context.Background()
+
+	if _, err := storage.CreateSecret(ctx, "projects/p", "db", &secretmanagerpb.Secret{Labels: map[string]string{"env": "test"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.AddSecretVersion(ctx, "projects/p/secrets/db", &secretmanagerpb.SecretPayload{Data: []byte("v1")}); err != nil {
+		t.Fatal(err)
+	}
+	id := storage.Checkpoint()
+
+	// Change everything the checkpoint shares with the current state
+	if _, err := storage.UpdateSecret(ctx, "projects/p/secrets/db", map[string]string{"env": "prod"}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.AddSecretVersion(ctx, "projects/p/secrets/db", &secretmanagerpb.SecretPayload{Data: []byte("v2")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.DestroySecretVersion(ctx, "projects/p/secrets/db/versions/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.CreateSecret(ctx, "projects/p", "ttl", &secretmanagerpb.Secret{
+		Expiration: &secretmanagerpb.Secret_Ttl{Ttl: durationpb.New(time.Hour)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	later := storage.Checkpoint()
+
+	if err := storage.Restore(id); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if n := storage.SecretCount(); n != 1 {
+		t.Errorf("SecretCount() after Restore() = %d, want 1", n)
+	}
+	secret, _ := storage.GetSecret(ctx, "projects/p/secrets/db")
+	if secret.GetLabels()["env"] != "test" {
+		t.Errorf("labels after Restore() = %v, want env=test", secret.GetLabels())
+	}
+	resp, err := storage.AccessSecretVersion(ctx, "projects/p/secrets/db/versions/latest")
+	if err != nil || string(resp.GetPayload().GetData()) != "v1" {
+		t.Errorf("AccessSecretVersion(latest) after Restore() = %v, %v; want v1", resp, err)
+	}
+
+	// Checkpoints can be restored again, and expirations are re-armed
+	if err := storage.Restore(later); err != nil {
+		t.Fatal(err)
+	}
+	if n := storage.SecretCount(); n != 2 {
+		t.Errorf("SecretCount() after second Restore() = %d, want 2", n)
+	}
+	_ = c.Advance(time.Hour)
+	if _, err := storage.GetSecret(ctx, "projects/p/secrets/ttl"); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret(ttl) after expiry error = %v, want NotFound", err)
+	}
+	if err := storage.Restore(later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.GetSecret(ctx, "projects/p/secrets/ttl"); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret(ttl) restored after expiry error = %v, want NotFound", err)
+	}
+
+	if cps := storage.Checkpoints(); len(cps) != 2 || cps[0].ID != id || cps[0].Secrets != 1 || cps[1].Secrets != 2 || !cps[0].CreateTime.Equal(start) {
+		t.Errorf("Checkpoints() = %+v", cps)
+	}
+	if err := storage.DeleteCheckpoint(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Restore(id); status.Code(err) != codes.NotFound {
+		t.Errorf("Restore() of deleted checkpoint error = %v, want NotFound", err)
+	}
+}
+
+// timerCountingClock counts the timers pending on a clock.
+type timerCountingClock struct {
+	*clock.Virtual
+	mu      sync.Mutex
+	pending int
+}
+
+type countedTimer struct {
+	clock.Timer
+	c *timerCountingClock
+}
+
+func (c *timerCountingClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	c.mu.Lock()
+	c.pending++
+	c.mu.Unlock()
+	return countedTimer{
+		Timer: c.Virtual.AfterFunc(d, func() {
+			c.mu.Lock()
+			c.pending--
+			c.mu.Unlock()
+			f()
+		}),
+		c: c,
+	}
+}
+
+func (t countedTimer) Stop() bool {
+	stopped := t.Timer.Stop()
+	if stopped {
+		t.c.mu.Lock()
+		t.c.pending--
+		t.c.mu.Unlock()
+	}
+	return stopped
+}
+
+func TestStorage_RestoreTimers(t *testing.T) {
+	c := &timerCountingClock{Virtual: clock.NewFrozen(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))}
+	storage := NewStorageWithClock(c)
+	ctx := context.Background()
+
+	empty := storage.Checkpoint()
+	for i := range 10 {
+		if _, err := storage.CreateSecret(ctx, "projects/p", fmt.Sprintf("s%d", i), &secretmanagerpb.Secret{
+			Expiration: &secretmanagerpb.Secret_Ttl{Ttl: durationpb.New(time.Duration(i+1) * time.Hour)},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	full := storage.Checkpoint()
+
+	// Storage keeps one timer however often it is restored
+	for range 100 {
+		if err := storage.Restore(empty); err != nil {
+			t.Fatal(err)
+		}
+		if err := storage.Restore(full); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.mu.Lock()
+	pending := c.pending
+	c.mu.Unlock()
+	if pending != 1 {
+		t.Errorf("%d timers pending, want 1", pending)
+	}
+
+	// Secrets still expire in order
+	_ = c.Advance(90 * time.Minute)
+	if n := storage.SecretCount(); n != 9 {
+		t.Errorf("SecretCount() after the first expiry = %d, want 9", n)
+	}
+	_ = c.Advance(9 * time.Hour)
+	if n := storage.SecretCount(); n != 0 {
+		t.Errorf("SecretCount() after every expiry = %d, want 0", n)
+	}
+}
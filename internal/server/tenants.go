@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+)
+
+// TenantMetadataKey is the gRPC metadata key selecting the tenant a call
+// acts in. The REST gateway sets it from the X-Emulator-Tenant header or a
+// /tenants/{tenant} URL prefix.
+const TenantMetadataKey = "x-emulator-tenant"
+
+// DefaultTenant is the tenant of calls that select none.
+const DefaultTenant = ""
+
+// tenantPattern is the syntax of tenant names, which appear in URL paths.
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,62}$`)
+
+// Tenants holds an isolated Storage per tenant, so that parallel test jobs
+// sharing one emulator do not see each other's secrets. Tenants are created
+// on first use and share the emulator clock.
+type Tenants struct {
+	clock clock.Clock
+
+	mu       sync.Mutex
+	storages map[string]*Storage
+	onDelete []func(tenant string)
+}
+
+// NewTenants creates a tenant registry holding only the default tenant.
+func NewTenants(c clock.Clock) *Tenants {
+	return &Tenants{
+		clock:    c,
+		storages: map[string]*Storage{DefaultTenant: NewStorageWithClock(c)},
+	}
+}
+
+// TenantFromContext returns the tenant an incoming call selects, or
+// DefaultTenant.
+func TenantFromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(TenantMetadataKey); len(v) > 0 {
+		return v[0]
+	}
+	return DefaultTenant
+}
+
+// ValidateTenant reports whether name is a valid tenant name: 1-63 letters,
+// digits, hyphens and underscores, starting with a letter or digit.
+func ValidateTenant(name string) error {
+	if !tenantPattern.MatchString(name) {
+		return status.Errorf(codes.InvalidArgument, "Invalid tenant %q: want 1-63 letters, digits, '-' or '_', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// Default returns the default tenant's storage.
+func (t *Tenants) Default() *Storage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.storages[DefaultTenant]
+}
+
+// Get returns the storage of a tenant, creating the tenant if it does not
+// exist.
+func (t *Tenants) Get(tenant string) (*Storage, error) {
+	if tenant != DefaultTenant {
+		if err := ValidateTenant(tenant); err != nil {
+			return nil, err
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	storage, ok := t.storages[tenant]
+	if !ok {
+		storage = NewStorageWithClock(t.clock)
+		t.storages[tenant] = storage
+	}
+	return storage, nil
+}
+
+// Lookup returns the storage of an existing tenant.
+func (t *Tenants) Lookup(tenant string) (*Storage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	storage, ok := t.storages[tenant]
+	return storage, ok
+}
+
+// FromContext returns the storage of the tenant an incoming call selects.
+func (t *Tenants) FromContext(ctx context.Context) (*Storage, error) {
+	return t.Get(TenantFromContext(ctx))
+}
+
+// List returns the names of the tenants other than the default, sorted.
+func (t *Tenants) List() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := make([]string, 0, len(t.storages)-1)
+	for name := range t.storages {
+		if name != DefaultTenant {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// OnDelete registers fn to be called with the name of each deleted tenant,
+// to drop state kept for it outside its storage, such as quota buckets.
+func (t *Tenants) OnDelete(fn func(tenant string)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onDelete = append(t.onDelete, fn)
+}
+
+// Delete deletes a tenant and all its secrets, reporting whether it
+// existed. The default tenant cannot be deleted. Writes to the deleted
+// tenant's storage by calls still in flight fail, and a tenant created later
+// under the same name starts empty.
+func (t *Tenants) Delete(tenant string) bool {
+	if tenant == DefaultTenant {
+		return false
+	}
+	t.mu.Lock()
+	storage, ok := t.storages[tenant]
+	delete(t.storages, tenant)
+	hooks := slices.Clone(t.onDelete)
+	t.mu.Unlock()
+	if !ok {
+		return false
+	}
+	storage.markDeleted()
+	for _, fn := range hooks {
+		fn(tenant)
+	}
+	return true
+}
+
+// All returns the storage of every tenant, including the default.
+func (t *Tenants) All() []*Storage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	storages := make([]*Storage, 0, len(t.storages))
+	for _, storage := range t.storages {
+		storages = append(storages, storage)
+	}
+	return storages
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// inTenant returns a context selecting tenant, as an incoming call would.
+func inTenant(tenant string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(TenantMetadataKey, tenant))
+}
+
+func TestServer_Tenants(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	create := func(ctx context.Context) error {
+		_, err := server.CreateSecret(ctx, &secretmanagerpb.CreateSecretRequest{
+			Parent:   "projects/test-project",
+			SecretId: "db",
+			Secret:   &secretmanagerpb.Secret{},
+		})
+		return err
+	}
+
+	// The same secret can be created in the default tenant and in each
+	// named tenant, which are created on first use
+	for _, ctx := range []context.Context{context.Background(), inTenant("job-1"), inTenant("job-2")} {
+		if err := create(ctx); err != nil {
+			t.Fatalf("CreateSecret() error = %v", err)
+		}
+	}
+	if err := create(inTenant("job-1")); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("CreateSecret() again in job-1 error = %v, want AlreadyExists", err)
+	}
+	if got := server.Tenants().List(); !slices.Equal(got, []string{"job-1", "job-2"}) {
+		t.Errorf("List() = %v", got)
+	}
+	if stats := server.storageStats(); stats.Secrets != 3 {
+		t.Errorf("storageStats().Secrets = %d, want 3 across tenants", stats.Secrets)
+	}
+
+	// Deleting a tenant leaves the others alone
+	if !server.Tenants().Delete("job-1") || server.Tenants().Delete("job-1") || server.Tenants().Delete(DefaultTenant) {
+		t.Error("Delete() did not delete job-1 exactly once, or deleted the default tenant")
+	}
+	if _, err := server.GetSecret(inTenant("job-1"), &secretmanagerpb.GetSecretRequest{Name: "projects/test-project/secrets/db"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSecret() in deleted tenant error = %v, want NotFound", err)
+	}
+	if _, err := server.GetSecret(inTenant("job-2"), &secretmanagerpb.GetSecretRequest{Name: "projects/test-project/secrets/db"}); err != nil {
+		t.Errorf("GetSecret() in job-2 error = %v", err)
+	}
+	if server.Storage().SecretCount() != 1 {
+		t.Errorf("default tenant has %d secrets, want 1", server.Storage().SecretCount())
+	}
+
+	// Deleted tenants leave no state behind, and calls still holding the
+	// deleted storage cannot write to it
+	storage, _ := server.Tenants().Lookup("job-2")
+	var deleted []string
+	server.Tenants().OnDelete(func(tenant string) { deleted = append(deleted, tenant) })
+	server.Tenants().Delete("job-2")
+	if !slices.Equal(deleted, []string{"job-2"}) {
+		t.Errorf("OnDelete hooks saw %v, want [job-2]", deleted)
+	}
+	if _, err := storage.CreateSecret(context.Background(), "projects/test-project", "late", &secretmanagerpb.Secret{}); status.Code(err) != codes.Aborted {
+		t.Errorf("CreateSecret() in deleted storage error = %v, want Aborted", err)
+	}
+	if err := create(inTenant("job-2")); err != nil {
+		t.Errorf("CreateSecret() in recreated job-2 error = %v", err)
+	}
+	if recreated, _ := server.Tenants().Lookup("job-2"); recreated == storage || recreated.SecretCount() != 1 {
+		t.Error("recreated job-2 does not start from empty storage")
+	}
+
+	for _, name := range []string{"-job", "job/1", "job 1", string(make([]byte, 64))} {
+		if err := create(inTenant(name)); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("CreateSecret() in tenant %q error = %v, want InvalidArgument", name, err)
+		}
+	}
+}
+
+func TestServer_DeleteTenantDecisions(t *testing.T) {
+	server, err := NewServer(WithPermissionCache(time.Minute))
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	cache := server.PermissionCache()
+	for _, tenant := range []string{"job-1", "job-2"} {
+		if _, err := server.Tenants().Get(tenant); err != nil {
+			t.Fatal(err)
+		}
+		cache.Put(tenant, "user:alice@example.com", "projects/p", "secretmanager.secrets.list", true)
+	}
+
+	server.Tenants().Delete("job-1")
+	if _, ok := cache.Get("job-1", "user:alice@example.com", "projects/p", "secretmanager.secrets.list"); ok {
+		t.Error("decision of deleted tenant still cached")
+	}
+	if _, ok := cache.Get("job-2", "user:alice@example.com", "projects/p", "secretmanager.secrets.list"); !ok {
+		t.Error("decision of another tenant dropped")
+	}
+}
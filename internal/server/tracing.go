@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/tracing"
+)
+
+// startStorageSpan starts a span for a storage operation on resource. RPC
+// spans come from the otelgrpc server handler; storage spans are their children.
+func startStorageSpan(ctx context.Context, op, resource string) trace.Span {
+	_, span := tracing.Tracer().Start(ctx, "Storage."+op,
+		trace.WithAttributes(attribute.String("secretmanager.resource", resource)),
+	)
+	return span
+}
+
+// startIAMSpan starts a span for an emulatorauth permission check.
+func startIAMSpan(ctx context.Context, principal, resource, permission string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "IAM.CheckPermission",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("iam.principal", principal),
+			attribute.String("iam.resource", resource),
+			attribute.String("iam.permission", permission),
+		),
+	)
+}
+
+// endIAMSpan records the outcome of a permission check and ends its span.
+func endIAMSpan(span trace.Span, allowed bool, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attribute.Bool("iam.allowed", allowed))
+	span.End()
+}
@@ -0,0 +1,125 @@
+package tlsutil
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"strings"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// gatewayCommonName is the subject of the gateway's internal client certificate.
+const gatewayCommonName = "gcp-secret-manager-emulator-gateway"
+
+// serviceAccountDomain identifies service account email addresses.
+const serviceAccountDomain = ".gserviceaccount.com"
+
+// PrincipalFromCertificate maps a client certificate to an IAM principal:
+//
+//   - an email SAN becomes "serviceAccount:<email>" for addresses ending in
+//     .gserviceaccount.com and "user:<email>" otherwise;
+//   - a URI SAN is used verbatim, e.g. "principal://..." or "spiffe://...".
+//
+// It returns "" when the certificate has neither.
+func PrincipalFromCertificate(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	if len(cert.EmailAddresses) > 0 {
+		email := cert.EmailAddresses[0]
+		if strings.HasSuffix(email, serviceAccountDomain) {
+			return "serviceAccount:" + email
+		}
+		return "user:" + email
+	}
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return ""
+}
+
+// PrincipalFromRequest returns the principal of a REST request: the verified
+// client certificate's principal under mutual TLS, otherwise the
+// X-Emulator-Principal header.
+func PrincipalFromRequest(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return PrincipalFromCertificate(r.TLS.VerifiedChains[0][0])
+	}
+	return emulatorauth.ExtractPrincipalFromRequest(r)
+}
+
+// ServerOptions returns the gRPC server options serving TLS with c and, under
+// mutual TLS, deriving principals from client certificates. Pass them before
+// other interceptors so those see the derived principal. A nil c yields none.
+func (c *Credentials) ServerOptions() []grpc.ServerOption {
+	if c == nil {
+		return nil
+	}
+	opts := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(c.Server))}
+	if c.MutualTLS {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(c.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(c.StreamServerInterceptor()),
+		)
+	}
+	return opts
+}
+
+// UnaryServerInterceptor makes a verified client certificate the principal of
+// every unary RPC. See principalContext.
+func (c *Credentials) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(c.principalContext(ctx), req)
+	}
+}
+
+// StreamServerInterceptor makes a verified client certificate the principal
+// of every streaming RPC. See principalContext.
+func (c *Credentials) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &principalStream{ServerStream: ss, ctx: c.principalContext(ss.Context())})
+	}
+}
+
+// principalContext replaces the principal metadata with the one derived from
+// the peer's verified client certificate. Principals forwarded by the REST
+// gateway's internal certificate are kept, since the gateway has already
+// authenticated the REST caller. Connections without a verified certificate
+// (plain TLS) are left unchanged.
+func (c *Credentials) principalContext(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 {
+		return ctx
+	}
+	chain := info.State.VerifiedChains[0]
+	if c.gatewayCA != nil && chain[len(chain)-1].Equal(c.gatewayCA) {
+		return ctx
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Delete(emulatorauth.PrincipalMetadataKey)
+	if principal := PrincipalFromCertificate(chain[0]); principal != "" {
+		md.Set(emulatorauth.PrincipalMetadataKey, principal)
+	}
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// principalStream overrides the context of a server stream.
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,156 @@
+// Package tlsutil builds TLS and mutual TLS configuration for the emulator's
+// gRPC and REST listeners.
+//
+// Certificates come either from PEM files (--tls-cert/--tls-key) or from an
+// auto-generated self-signed CA whose bundle is written to disk for clients.
+// With a client CA (--client-ca) the listeners require client certificates
+// and the certificate's SAN becomes the principal for IAM checks.
+//
+// The REST gateway dials the gRPC backend with an internal client
+// certificate issued by an ephemeral CA created at startup. The backend
+// trusts principals forwarded by that certificate only, so REST callers are
+// authorized as themselves while direct gRPC callers cannot impersonate
+// anyone.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Options configures TLS. The zero value disables TLS.
+type Options struct {
+	// CertFile and KeyFile are the PEM server certificate and key.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM bundle of CAs trusted for client certificates.
+	// Setting it enables mutual TLS.
+	ClientCAFile string
+	// SelfSigned generates a CA and server certificate at startup instead of
+	// loading CertFile and KeyFile.
+	SelfSigned bool
+	// CAOut is where the self-signed CA bundle is written for clients.
+	CAOut string
+	// Hosts are extra DNS names or IPs for the self-signed certificate, in
+	// addition to localhost, 127.0.0.1, ::1 and the machine's hostname.
+	Hosts []string
+}
+
+// Enabled reports whether TLS is configured.
+func (o Options) Enabled() bool {
+	return o.SelfSigned || o.CertFile != "" || o.KeyFile != ""
+}
+
+// Credentials holds the TLS configuration for the emulator's listeners and
+// for the REST gateway's connection to the gRPC backend.
+type Credentials struct {
+	// Server is the configuration for gRPC and HTTP listeners.
+	Server *tls.Config
+	// Gateway is the client configuration for the gateway's backend connection.
+	Gateway *tls.Config
+	// MutualTLS reports whether client certificates are required.
+	MutualTLS bool
+	// CAFile is the path of the written self-signed CA bundle, if any.
+	CAFile string
+
+	// gatewayCA issues the gateway's internal client certificate.
+	gatewayCA *x509.Certificate
+}
+
+// Load builds credentials from opts. It returns nil credentials when TLS is
+// not enabled.
+func Load(opts Options) (*Credentials, error) {
+	if !opts.Enabled() {
+		if opts.ClientCAFile != "" {
+			return nil, errors.New("--client-ca requires --tls-cert/--tls-key or --tls-self-signed")
+		}
+		return nil, nil
+	}
+
+	creds := &Credentials{}
+	var serverCert tls.Certificate
+	if opts.SelfSigned {
+		ca, err := newCA("GCP Secret Manager Emulator CA")
+		if err != nil {
+			return nil, err
+		}
+		serverCert, err = ca.issue("gcp-secret-manager-emulator", serverHosts(opts.Hosts), x509.ExtKeyUsageServerAuth)
+		if err != nil {
+			return nil, err
+		}
+		creds.CAFile = opts.CAOut
+		if creds.CAFile == "" {
+			creds.CAFile = DefaultCAOut
+		}
+		if err := writeCABundle(creds.CAFile, ca.cert); err != nil {
+			return nil, err
+		}
+	} else {
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, errors.New("--tls-cert and --tls-key must be set together")
+		}
+		var err error
+		serverCert, err = tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+		}
+	}
+
+	creds.Server = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	// The gateway always gets its own client certificate so it can reach an
+	// mTLS backend; the backend pins the certificate it serves.
+	gatewayCA, err := newCA("GCP Secret Manager Emulator internal gateway CA")
+	if err != nil {
+		return nil, err
+	}
+	gatewayCert, err := gatewayCA.issue(gatewayCommonName, nil, x509.ExtKeyUsageClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	creds.gatewayCA = gatewayCA.cert
+	creds.Gateway = pinnedClientConfig(serverCert, gatewayCert)
+
+	if opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA bundle %s", opts.ClientCAFile)
+		}
+		pool.AddCert(gatewayCA.cert)
+		creds.Server.ClientCAs = pool
+		creds.Server.ClientAuth = tls.RequireAndVerifyClientCert
+		creds.MutualTLS = true
+	}
+
+	return creds, nil
+}
+
+// pinnedClientConfig returns a client configuration that accepts exactly the
+// server certificate the emulator itself serves, presenting clientCert.
+func pinnedClientConfig(serverCert, clientCert tls.Certificate) *tls.Config {
+	pinned := serverCert.Certificate[0]
+	return &tls.Config{
+		Certificates: []tls.Certificate{clientCert},
+		MinVersion:   tls.VersionTLS12,
+		// Hostname verification is replaced by pinning: the gateway only
+		// ever dials this process's own backend.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || string(rawCerts[0]) != string(pinned) {
+				return errors.New("backend certificate does not match the emulator's certificate")
+			}
+			return nil
+		},
+	}
+}
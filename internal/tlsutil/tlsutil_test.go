@@ -0,0 +1,213 @@
+package tlsutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLoad_Disabled(t *testing.T) {
+	creds, err := Load(Options{})
+	if err != nil || creds != nil {
+		t.Fatalf("Load() = %v, %v; want nil, nil", creds, err)
+	}
+
+	if _, err := Load(Options{ClientCAFile: "ca.pem"}); err == nil {
+		t.Error("expected an error for --client-ca without TLS")
+	}
+	if _, err := Load(Options{CertFile: "cert.pem"}); err == nil {
+		t.Error("expected an error for --tls-cert without --tls-key")
+	}
+}
+
+func TestLoad_SelfSigned(t *testing.T) {
+	caOut := filepath.Join(t.TempDir(), "certs", "ca.pem")
+	creds, err := Load(Options{SelfSigned: true, CAOut: caOut, Hosts: []string{"emulator.test"}})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if creds.CAFile != caOut {
+		t.Errorf("CAFile = %q, want %q", creds.CAFile, caOut)
+	}
+	if creds.MutualTLS {
+		t.Error("MutualTLS = true without a client CA")
+	}
+
+	data, err := os.ReadFile(caOut)
+	if err != nil {
+		t.Fatalf("CA bundle not written: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		t.Fatal("CA bundle contains no certificates")
+	}
+
+	leaf := creds.Server.Certificates[0].Leaf
+	for _, host := range []string{"localhost", "127.0.0.1", "emulator.test"} {
+		if _, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: pool}); err != nil {
+			t.Errorf("server certificate does not verify for %s: %v", host, err)
+		}
+	}
+}
+
+func TestPrincipalFromCertificate(t *testing.T) {
+	spiffe, _ := url.Parse("spiffe://example.org/ns/default/sa/app")
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want string
+	}{
+		{"nil", nil, ""},
+		{"service account", &x509.Certificate{EmailAddresses: []string{"ci@my-project.iam.gserviceaccount.com"}}, "serviceAccount:ci@my-project.iam.gserviceaccount.com"},
+		{"user", &x509.Certificate{EmailAddresses: []string{"alice@example.com"}}, "user:alice@example.com"},
+		{"uri", &x509.Certificate{URIs: []*url.URL{spiffe}}, "spiffe://example.org/ns/default/sa/app"},
+		{"no SAN", &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrincipalFromCertificate(tt.cert); got != tt.want {
+				t.Errorf("PrincipalFromCertificate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentials_MutualTLSPrincipal(t *testing.T) {
+	dir := t.TempDir()
+	clientCA, err := newCA("test client CA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAFile := filepath.Join(dir, "client-ca.pem")
+	if err := writeCABundle(clientCAFile, clientCA.cert); err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := Load(Options{SelfSigned: true, CAOut: filepath.Join(dir, "ca.pem"), ClientCAFile: clientCAFile})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !creds.MutualTLS {
+		t.Fatal("MutualTLS = false with a client CA")
+	}
+
+	// Record the principal each RPC is authorized as
+	principals := make(chan string, 1)
+	record := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		principals <- emulatorauth.ExtractPrincipalFromContext(ctx)
+		return handler(ctx, req)
+	}
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(append(creds.ServerOptions(), grpc.ChainUnaryInterceptor(record))...)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	caPEM, err := os.ReadFile(filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caPEM)
+
+	check := func(t *testing.T, cfg *tls.Config, claimed string) string {
+		t.Helper()
+		conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		ctx := metadata.AppendToOutgoingContext(context.Background(), emulatorauth.PrincipalMetadataKey, claimed)
+		if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		return <-principals
+	}
+
+	t.Run("ClientCertificate", func(t *testing.T) {
+		cfg := &tls.Config{
+			RootCAs:      roots,
+			ServerName:   "localhost",
+			Certificates: []tls.Certificate{issueClient(t, clientCA, "ci@my-project.iam.gserviceaccount.com")},
+		}
+		if got := check(t, cfg, "user:admin@example.com"); got != "serviceAccount:ci@my-project.iam.gserviceaccount.com" {
+			t.Errorf("principal = %q, want the certificate's service account", got)
+		}
+	})
+
+	t.Run("GatewayForwardsPrincipal", func(t *testing.T) {
+		if got := check(t, creds.Gateway, "user:alice@example.com"); got != "user:alice@example.com" {
+			t.Errorf("principal = %q, want the forwarded user:alice@example.com", got)
+		}
+	})
+
+	t.Run("NoClientCertificate", func(t *testing.T) {
+		conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: roots, ServerName: "localhost"})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err == nil {
+			t.Error("expected the handshake to fail without a client certificate")
+		}
+	})
+}
+
+// issueClient issues a client certificate with an email SAN signed by ca.
+func issueClient(t *testing.T, ca *authority, email string) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:   serial,
+		Subject:        pkix.Name{CommonName: email},
+		EmailAddresses: []string{email},
+		NotBefore:      time.Now().Add(-time.Minute),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
@@ -0,0 +1,98 @@
+package token
+
+import (
+	"context"
+	"strings"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationKey is the metadata key gRPC clients send bearer tokens in.
+const authorizationKey = "authorization"
+
+// ServerOptions returns the gRPC server options deriving principals from
+// bearer tokens. Pass them before other interceptors so those see the
+// derived principal, but after TLS options so that client certificates win.
+// A nil r yields none.
+func (r *Resolver) ServerOptions() []grpc.ServerOption {
+	if r == nil {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(r.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(r.StreamServerInterceptor()),
+	}
+}
+
+// UnaryServerInterceptor makes the bearer token's principal the principal of
+// every unary RPC. See principalContext.
+func (r *Resolver) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := r.principalContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor makes the bearer token's principal the principal
+// of every streaming RPC. See principalContext.
+func (r *Resolver) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := r.principalContext(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &principalStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// principalContext sets the principal metadata from the call's bearer token.
+// An explicit principal, from the x-emulator-principal key or a client
+// certificate, is kept. Expired JWTs fail with Unauthenticated, as GCP does.
+func (r *Resolver) principalContext(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get(emulatorauth.PrincipalMetadataKey)) > 0 {
+		return ctx, nil
+	}
+	bearer := BearerToken(md.Get(authorizationKey))
+	if bearer == "" {
+		return ctx, nil
+	}
+	principal, err := r.Principal(bearer)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Request had invalid authentication credentials: %v", err)
+	}
+	if principal == "" {
+		return ctx, nil
+	}
+	md = md.Copy()
+	md.Set(emulatorauth.PrincipalMetadataKey, principal)
+	return metadata.NewIncomingContext(ctx, md), nil
+}
+
+// BearerToken returns the token of the first "Bearer" authorization value,
+// or "" if there is none.
+func BearerToken(values []string) string {
+	for _, v := range values {
+		if scheme, token, ok := strings.Cut(v, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// principalStream overrides the context of a server stream.
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalStream) Context() context.Context {
+	return s.ctx
+}
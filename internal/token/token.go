@@ -0,0 +1,173 @@
+// Package token derives IAM principals from the bearer tokens Google client
+// libraries send, so strict-mode IAM can be exercised with unmodified client
+// code.
+//
+// Tokens are looked up in a static token-to-principal map first. Otherwise a
+// JWT (a self-signed service account token, an ID token, or a token minted
+// by Mint) is decoded without verifying its signature, and its email or sub
+// claim becomes the principal. Other tokens, such as OAuth2 access tokens,
+// carry no principal.
+package token
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+)
+
+// issuer is the iss claim of minted tokens.
+const issuer = "gcp-secret-manager-emulator"
+
+// serviceAccountDomain identifies service account email addresses.
+const serviceAccountDomain = ".gserviceaccount.com"
+
+// DefaultLifetime is how long minted tokens are valid for by default, as
+// for Google access tokens.
+const DefaultLifetime = time.Hour
+
+// ErrExpired is returned for a JWT whose exp claim the clock has reached.
+var ErrExpired = errors.New("token has expired")
+
+// Claims are the JWT claims used to derive a principal.
+type Claims struct {
+	Issuer   string `json:"iss,omitempty"`
+	Subject  string `json:"sub,omitempty"`
+	Email    string `json:"email,omitempty"`
+	IssuedAt int64  `json:"iat,omitempty"`
+	Expiry   int64  `json:"exp,omitempty"`
+}
+
+// Decode returns the claims of a JWT without verifying its signature, which
+// may be absent ("alg": "none").
+func Decode(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("invalid JWT claims: %w", err)
+	}
+	return &claims, nil
+}
+
+// Principal maps the claims to an IAM principal: the email claim, or else
+// the sub claim, becomes "serviceAccount:<email>" for addresses ending in
+// .gserviceaccount.com and "user:<id>" otherwise. A sub claim that already
+// names a principal ("group:...", "principal://...") is used verbatim.
+func (c *Claims) Principal() string {
+	switch {
+	case c.Email != "":
+		return principalFor(c.Email)
+	case strings.Contains(c.Subject, ":"):
+		return c.Subject
+	case c.Subject != "":
+		return principalFor(c.Subject)
+	}
+	return ""
+}
+
+func principalFor(email string) string {
+	if strings.HasSuffix(email, serviceAccountDomain) {
+		return "serviceAccount:" + email
+	}
+	return "user:" + email
+}
+
+// Resolver maps bearer tokens to principals.
+type Resolver struct {
+	clock  clock.Clock
+	tokens map[string]string
+}
+
+// NewResolver creates a resolver that checks JWT expiry against c and looks
+// tokens up in tokens, a static token-to-principal map, before decoding them.
+func NewResolver(c clock.Clock, tokens map[string]string) *Resolver {
+	return &Resolver{clock: c, tokens: tokens}
+}
+
+// Principal returns the principal of a bearer token, or "" if the token
+// carries none. It fails with ErrExpired for an expired JWT.
+func (r *Resolver) Principal(token string) (string, error) {
+	if principal, ok := r.tokens[token]; ok {
+		return principal, nil
+	}
+	claims, err := Decode(token)
+	if err != nil {
+		return "", nil // Opaque token
+	}
+	if claims.Expiry != 0 && !r.clock.Now().Before(time.Unix(claims.Expiry, 0)) {
+		return "", ErrExpired
+	}
+	return claims.Principal(), nil
+}
+
+// Mint returns an unsigned JWT for principal ("user:...", "serviceAccount:..."
+// or any other IAM principal) that expires after lifetime, and its expiry.
+func (r *Resolver) Mint(principal string, lifetime time.Duration) (string, time.Time, error) {
+	kind, id, ok := strings.Cut(principal, ":")
+	if !ok || kind == "" || id == "" {
+		return "", time.Time{}, fmt.Errorf("invalid principal %q: want type:id, e.g. user:alice@example.com", principal)
+	}
+	if lifetime <= 0 {
+		lifetime = DefaultLifetime
+	}
+	now := r.clock.Now()
+	expiry := now.Add(lifetime)
+
+	claims := Claims{Issuer: issuer, Subject: principal, IssuedAt: now.Unix(), Expiry: expiry.Unix()}
+	if kind == "user" || kind == "serviceAccount" {
+		claims.Subject, claims.Email = id, id
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".", expiry, nil
+}
+
+// tokenFile is the format of a static token file.
+type tokenFile struct {
+	// Tokens maps bearer tokens to IAM principals.
+	Tokens map[string]string `yaml:"tokens"`
+}
+
+// Load reads a YAML or JSON file mapping bearer tokens to principals:
+//
+//	tokens:
+//	  ci-token: serviceAccount:ci@my-project.iam.gserviceaccount.com
+//	  alice-token: user:alice@example.com
+func Load(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f tokenFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for token, principal := range f.Tokens {
+		if token == "" || !strings.Contains(principal, ":") {
+			return nil, fmt.Errorf("%s: token %q: invalid principal %q: want type:id, e.g. user:alice@example.com", path, token, principal)
+		}
+	}
+	return f.Tokens, nil
+}
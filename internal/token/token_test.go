@@ -0,0 +1,130 @@
+package token
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	emulatorauth "github.com/blackwell-systems/gcp-emulator-auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/blackwell-systems/gcp-secret-manager-emulator/internal/clock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// jwt returns a JWT with the given claims and a fake RS256 signature.
+func jwt(claims string) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + enc([]byte(claims)) + "." + enc([]byte("signature"))
+}
+
+func TestResolver_Principal(t *testing.T) {
+	r := NewResolver(clock.NewFrozen(start), map[string]string{"ci-token": "serviceAccount:ci@p.iam.gserviceaccount.com"})
+
+	for _, tt := range []struct {
+		name, token, want string
+		err               error
+	}{
+		{"static", "ci-token", "serviceAccount:ci@p.iam.gserviceaccount.com", nil},
+		{"ID token", jwt(`{"email":"alice@example.com","sub":"1234567890"}`), "user:alice@example.com", nil},
+		{"self-signed service account", jwt(`{"iss":"sa@p.iam.gserviceaccount.com","sub":"sa@p.iam.gserviceaccount.com"}`), "serviceAccount:sa@p.iam.gserviceaccount.com", nil},
+		{"sub principal", jwt(`{"sub":"group:admins@example.com"}`), "group:admins@example.com", nil},
+		{"not expired", jwt(fmt.Sprintf(`{"email":"bob@example.com","exp":%d}`, start.Add(time.Minute).Unix())), "user:bob@example.com", nil},
+		{"expired", jwt(fmt.Sprintf(`{"email":"bob@example.com","exp":%d}`, start.Unix())), "", ErrExpired},
+		{"opaque", "ya29.access-token", "", nil},
+		{"no claims", jwt(`{}`), "", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Principal(tt.token)
+			if got != tt.want || !errors.Is(err, tt.err) {
+				t.Errorf("Principal() = %q, %v; want %q, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestResolver_Mint(t *testing.T) {
+	c := clock.NewFrozen(start)
+	r := NewResolver(c, nil)
+
+	for _, principal := range []string{"user:alice@example.com", "serviceAccount:ci@p.iam.gserviceaccount.com", "principal://iam.googleapis.com/x"} {
+		token, expiry, err := r.Mint(principal, 0)
+		if err != nil {
+			t.Fatalf("Mint(%q) error = %v", principal, err)
+		}
+		if !expiry.Equal(start.Add(DefaultLifetime)) {
+			t.Errorf("Mint(%q) expiry = %v, want an hour after start", principal, expiry)
+		}
+		if got, err := r.Principal(token); got != principal || err != nil {
+			t.Errorf("Principal(Mint(%q)) = %q, %v", principal, got, err)
+		}
+	}
+
+	token, _, _ := r.Mint("user:alice@example.com", time.Minute)
+	_ = c.Advance(time.Minute)
+	if _, err := r.Principal(token); !errors.Is(err, ErrExpired) {
+		t.Errorf("Principal() of expired minted token error = %v, want ErrExpired", err)
+	}
+	if _, _, err := r.Mint("alice@example.com", 0); err == nil {
+		t.Error("Mint() without principal type succeeded")
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	r := NewResolver(clock.NewFrozen(start), map[string]string{"ci-token": "serviceAccount:ci@p.iam.gserviceaccount.com"})
+	principal := func(pairs ...string) (string, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+		var got string
+		_, err := r.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+			got = emulatorauth.ExtractPrincipalFromContext(ctx)
+			return nil, nil
+		})
+		return got, err
+	}
+
+	if got, err := principal("authorization", "Bearer ci-token"); got != "serviceAccount:ci@p.iam.gserviceaccount.com" || err != nil {
+		t.Errorf("principal from static token = %q, %v", got, err)
+	}
+	if got, _ := principal("authorization", "bearer "+jwt(`{"email":"alice@example.com"}`)); got != "user:alice@example.com" {
+		t.Errorf("principal from ID token = %q", got)
+	}
+	// An explicit principal wins over the token
+	if got, _ := principal("authorization", "Bearer ci-token", emulatorauth.PrincipalMetadataKey, "user:bob@example.com"); got != "user:bob@example.com" {
+		t.Errorf("principal with explicit principal = %q, want user:bob@example.com", got)
+	}
+	if got, err := principal("authorization", "Basic dXNlcjpwYXNz"); got != "" || err != nil {
+		t.Errorf("principal from basic auth = %q, %v; want none", got, err)
+	}
+	expired := jwt(fmt.Sprintf(`{"email":"alice@example.com","exp":%d}`, start.Unix()))
+	if _, err := principal("authorization", "Bearer "+expired); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expired token error = %v, want Unauthenticated", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tokens.yaml")
+	if err := os.WriteFile(path, []byte("tokens:\n  ci-token: serviceAccount:ci@p.iam.gserviceaccount.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := Load(path)
+	if err != nil || tokens["ci-token"] != "serviceAccount:ci@p.iam.gserviceaccount.com" {
+		t.Errorf("Load() = %v, %v", tokens, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"tokens": {"ci-token": "ci@p.iam.gserviceaccount.com"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() with a principal without type succeeded")
+	}
+}
@@ -0,0 +1,97 @@
+// Package tracing configures OpenTelemetry tracing for the emulator.
+//
+// W3C trace context is always propagated, so traces started by a caller
+// continue through the REST gateway into the gRPC server even when the
+// emulator itself exports nothing. Spans are exported only when an exporter
+// is configured: "otlp" sends them to an OTLP/gRPC collector configured with
+// the standard OTEL_EXPORTER_OTLP_* environment variables, and "stdout"
+// writes them as JSON for local debugging.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies spans created by the emulator itself.
+const InstrumentationName = "github.com/blackwell-systems/gcp-secret-manager-emulator"
+
+// Exporter names accepted by Setup.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config configures tracing.
+type Config struct {
+	// Exporter is "none" (or empty), "otlp" or "stdout".
+	Exporter string
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+	// ServiceVersion is reported as the service.version resource attribute.
+	ServiceVersion string
+	// Writer receives spans for the stdout exporter. Defaults to os.Stdout.
+	Writer io.Writer
+}
+
+// Setup installs the global propagator and, if an exporter is configured, a
+// global tracer provider. The returned function flushes and stops the
+// exporter; it is safe to call when tracing is disabled.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(strings.TrimSpace(cfg.Exporter)) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		opts := []stdouttrace.Option{stdouttrace.WithPrettyPrint()}
+		if cfg.Writer != nil {
+			opts = append(opts, stdouttrace.WithWriter(cfg.Writer))
+		}
+		exporter, err = stdouttrace.New(opts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q (want none, otlp or stdout)", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the emulator's tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{Exporter: "jaeger"}); err == nil {
+		t.Error("Setup() should reject unknown exporters")
+	}
+}
+
+func TestSetup_StdoutExporter(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	var buf bytes.Buffer
+	shutdown, err := Setup(context.Background(), Config{
+		Exporter:    ExporterStdout,
+		ServiceName: "test-emulator",
+		Writer:      &buf,
+	})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	_, span := Tracer().Start(context.Background(), "test-span")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "test-span") || !strings.Contains(buf.String(), "test-emulator") {
+		t.Errorf("stdout exporter output missing span or service name: %s", buf.String())
+	}
+}
+
+func TestSetup_NoneInstallsPropagator(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	defer shutdown(context.Background())
+
+	fields := otel.GetTextMapPropagator().Fields()
+	if !strings.Contains(strings.Join(fields, ","), "traceparent") {
+		t.Errorf("propagator fields = %v, want traceparent", fields)
+	}
+}